@@ -0,0 +1,178 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/urfave/cli"
+)
+
+var completionCommands = []cli.Command{
+	{
+		Name:      "completion",
+		Usage:     "Print a completion script for bash, zsh or fish, e.g. source <(axe completion bash)",
+		ArgsUsage: "bash|zsh|fish",
+		Action:    completion,
+	},
+	{
+		// the scripts call back for what changes between clusters, the flags are written into them
+		Name:   "__complete",
+		Hidden: true,
+		Action: complete,
+	},
+}
+
+func completion(c *cli.Context) error {
+	program := filepath.Base(os.Args[0])
+	flags := completionFlags(c.App.Flags)
+	switch c.Args().First() {
+	case "bash":
+		return bashCompletion(c.App.Writer, program, flags)
+	case "zsh":
+		// zsh runs the bash script through its bash compatibility layer
+		fmt.Fprintln(c.App.Writer, "autoload -U +X compinit && compinit")
+		fmt.Fprintln(c.App.Writer, "autoload -U +X bashcompinit && bashcompinit")
+		return bashCompletion(c.App.Writer, program, flags)
+	case "fish":
+		return fishCompletion(c.App.Writer, program, flags)
+	}
+	return fmt.Errorf("expected bash, zsh or fish, got %q", c.Args().First())
+}
+
+func complete(c *cli.Context) error {
+	list, ok := bladeCompletions[c.GlobalString("blade")]
+	if !ok {
+		return nil
+	}
+	candidates, err := list(c.Parent(), c.Args().First())
+	if err != nil {
+		return err
+	}
+	for _, candidate := range candidates {
+		fmt.Fprintln(c.App.Writer, candidate)
+	}
+	return nil
+}
+
+type completionFlag struct {
+	long, short string
+	usage       string
+	takesValue  bool
+}
+
+func completionFlags(flags []cli.Flag) []completionFlag {
+	var out []completionFlag
+	for _, f := range flags {
+		var cf completionFlag
+		switch f := f.(type) {
+		case cli.BoolFlag:
+			cf.usage = f.Usage
+		case cli.StringFlag:
+			cf.usage, cf.takesValue = f.Usage, true
+		case cli.StringSliceFlag:
+			cf.usage, cf.takesValue = f.Usage, true
+		case cli.IntFlag:
+			cf.usage, cf.takesValue = f.Usage, true
+		case cli.Float64Flag:
+			cf.usage, cf.takesValue = f.Usage, true
+		default:
+			continue
+		}
+		for _, name := range strings.Split(f.GetName(), ",") {
+			name = strings.TrimSpace(name)
+			if len(name) == 1 {
+				cf.short = name
+			} else {
+				cf.long = name
+			}
+		}
+		out = append(out, cf)
+	}
+	return out
+}
+
+// bashCompletion passes --kubeconfig and --context typed so far on, so namespaces come from the cluster being picked
+func bashCompletion(w io.Writer, program string, flags []completionFlag) error {
+	var names, valued []string
+	for _, f := range flags {
+		for _, name := range []string{f.long, f.short} {
+			if name == "" {
+				continue
+			}
+			dashes := "--"
+			if len(name) == 1 {
+				dashes = "-"
+			}
+			names = append(names, dashes+name)
+			if f.takesValue {
+				valued = append(valued, dashes+name)
+			}
+		}
+	}
+	fn := "_" + strings.Replace(program, "-", "_", -1)
+	_, err := fmt.Fprintf(w, `%[1]s() {
+	local cur prev i connection=()
+	cur="${COMP_WORDS[COMP_CWORD]}"
+	prev="${COMP_WORDS[COMP_CWORD-1]}"
+	for ((i = 1; i < COMP_CWORD; i++)); do
+		case "${COMP_WORDS[i]}" in
+		--kubeconfig|--context) connection+=("${COMP_WORDS[i]}" "${COMP_WORDS[i+1]}") ;;
+		esac
+	done
+	case "$prev" in
+	--context)
+		COMPREPLY=($(compgen -W "$(%[2]s "${connection[@]}" __complete contexts 2>/dev/null)" -- "$cur"))
+		return ;;
+	-n|--namespace)
+		COMPREPLY=($(compgen -W "$(%[2]s "${connection[@]}" __complete namespaces 2>/dev/null)" -- "$cur"))
+		return ;;
+	--keymap)
+		COMPREPLY=($(compgen -W "default vim" -- "$cur"))
+		return ;;
+	%[3]s)
+		COMPREPLY=()
+		return ;;
+	completion)
+		COMPREPLY=($(compgen -W "bash zsh fish" -- "$cur"))
+		return ;;
+	replay)
+		COMPREPLY=($(compgen -f -- "$cur"))
+		return ;;
+	esac
+	case "$cur" in
+	-*) COMPREPLY=($(compgen -W "%[4]s" -- "$cur")) ;;
+	*) COMPREPLY=($(compgen -W "kinds replay completion $(%[2]s "${connection[@]}" __complete kinds 2>/dev/null)" -- "$cur")) ;;
+	esac
+}
+complete -o default -F %[1]s %[2]s
+`, fn, program, strings.Join(valued, "|"), strings.Join(names, " "))
+	return err
+}
+
+func fishCompletion(w io.Writer, program string, flags []completionFlag) error {
+	fmt.Fprintf(w, "complete -c %s -f\n", program)
+	for _, f := range flags {
+		line := fmt.Sprintf("complete -c %s -l %s", program, f.long)
+		if f.short != "" {
+			line += " -s " + f.short
+		}
+		switch {
+		case f.long == "context":
+			line += fmt.Sprintf(" -x -a '(%s __complete contexts 2>/dev/null)'", program)
+		case f.long == "namespace":
+			line += fmt.Sprintf(" -x -a '(%s __complete namespaces 2>/dev/null)'", program)
+		case f.long == "keymap":
+			line += " -x -a 'default vim'"
+		case f.takesValue:
+			line += " -r"
+		}
+		fmt.Fprintf(w, "%s -d '%s'\n", line, strings.Replace(f.usage, "'", `\'`, -1))
+	}
+	fmt.Fprintf(w, "complete -c %s -n '__fish_seen_subcommand_from completion' -a 'bash zsh fish'\n", program)
+	fmt.Fprintf(w, "complete -c %s -n '__fish_seen_subcommand_from replay' -F\n", program)
+	_, err := fmt.Fprintf(w, "complete -c %[1]s -n 'not __fish_seen_subcommand_from completion kinds replay' -a 'kinds replay completion (%[1]s __complete kinds 2>/dev/null)'\n", program)
+	return err
+}
@@ -4,21 +4,34 @@
 package main
 
 import (
-	"github.com/rancher/axe/throwing/k8s"
-	"github.com/rancher/axe/throwing/rio"
+	"context"
+	"fmt"
 	"os"
+	"os/signal"
+	"runtime/debug"
+	"syscall"
+
+	"github.com/rancher/axe/throwing"
+	"github.com/rancher/axe/throwing/k8s"
 
 	"github.com/rancher/axe/version"
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli"
 )
 
-func init() {
-	file, err := os.Create("throwing.logs")
-	if err != nil {
-		panic(err)
-	}
-	logrus.SetOutput(file)
+// blades are the views built on top of throwing, main only wires flags to one of them
+var blades = map[string]func(c *cli.Context) error{
+	"k8s": k8s.Start,
+}
+
+// bladeKinds print what the blade can navigate to, for the kinds command
+var bladeKinds = map[string]func(c *cli.Context) error{
+	"k8s": k8s.Kinds,
+}
+
+// bladeCompletions list contexts, namespaces and kinds for the completion scripts
+var bladeCompletions = map[string]func(c *cli.Context, what string) ([]string, error){
+	"k8s": k8s.Complete,
 }
 
 func main() {
@@ -26,31 +39,199 @@ func main() {
 	app.Name = "throwing"
 	app.Version = version.VERSION
 	app.Usage = "throwing needs help!"
+	app.ArgsUsage = "[kind[/name] | kind name] [-n namespace]"
 	app.Flags = []cli.Flag{
 		cli.StringFlag{
 			Name:   "kubeconfig",
+			Usage:  "Kubeconfig file, defaults to ~/.kube/config and to the in-cluster config inside a pod",
 			EnvVar: "KUBECONFIG",
-			Value:  "${HOME}/.kube/config",
 		},
 		cli.StringFlag{
-			Name:   "blade",
-			Value:  "rio",
+			Name:  "context",
+			Usage: "Kubeconfig context to use instead of the current one",
+		},
+		cli.StringFlag{
+			Name:  "namespace, n",
+			Usage: "Only list resources of this namespace, all namespaces by default",
+		},
+		cli.StringFlag{
+			Name:  "blade",
+			Usage: "Blade to run",
+			Value: "k8s",
+		},
+		cli.BoolFlag{
+			Name:  "dashboard",
+			Usage: "Land on the cluster overview instead of the api resources (k8s blade)",
+		},
+		cli.StringFlag{
+			Name:  "config",
+			Usage: "Config file with key bindings, defaults to ~/.axe/config.yaml",
+		},
+		cli.StringFlag{
+			Name:  "as",
+			Usage: "Username to impersonate (k8s blade)",
+		},
+		cli.StringSliceFlag{
+			Name:  "as-group",
+			Usage: "Group to impersonate, can be repeated (k8s blade)",
+		},
+		cli.StringFlag{
+			Name:  "as-uid",
+			Usage: "UID to impersonate (k8s blade)",
+		},
+		cli.Float64Flag{
+			Name:  "qps",
+			Usage: "Requests per second to the API server, shared by every view (k8s blade)",
+			Value: 20,
+		},
+		cli.IntFlag{
+			Name:  "burst",
+			Usage: "Requests allowed above --qps in a burst (k8s blade)",
+			Value: 40,
+		},
+		cli.IntFlag{
+			Name:  "max-refreshes",
+			Usage: "Tables refreshing at the same time, the others wait, 0 doesn't limit them",
+			Value: 4,
+		},
+		cli.BoolFlag{
+			Name:  "read-only",
+			Usage: "Hide every action that changes the cluster: edit, delete, exec, patch and the like (k8s blade)",
+		},
+		cli.BoolFlag{
+			Name:  "tmux",
+			Usage: "Open exec, logs and ssh sessions in a new tmux pane instead of suspending the UI, when running inside tmux (k8s blade)",
+		},
+		cli.StringFlag{
+			Name:  "serve",
+			Usage: "Share the table on screen read-only over HTTP on this address, e.g. :8080, as a page and as JSON at /api/table",
+		},
+		cli.StringFlag{
+			Name:  "pprof",
+			Usage: "Serve pprof and the refresh and draw times per view on this address, e.g. :6060, on localhost when no host is given",
+		},
+		cli.StringFlag{
+			Name:  "record",
+			Usage: "Record the screen and the keys pressed to this file, play it back with replay",
+		},
+		cli.BoolFlag{
+			Name:   "plain",
+			Usage:  "Accessible output: no colors, no box drawing, the selected row read out as one line for screen readers",
+			EnvVar: "AXE_PLAIN",
+		},
+		cli.BoolFlag{
+			Name:  "ascii",
+			Usage: "Draw borders, icons and spinners with ASCII only, the default when the locale isn't UTF-8",
+		},
+		cli.StringFlag{
+			Name:   "keymap",
+			Usage:  "Key profile of the tables (default, vim), overrides keymap of the config file",
+			EnvVar: "AXE_KEYMAP",
+		},
+		cli.StringFlag{
+			Name:  "log-file",
+			Usage: "File to write debug logs to, stdout can't be used while the UI is running",
+			Value: "throwing.logs",
+		},
+		cli.StringFlag{
+			Name:  "log-level",
+			Usage: "Log level (debug, info, warn, error)",
+			Value: "info",
 		},
 	}
+	app.Commands = append([]cli.Command{
+		{
+			Name:   "kinds",
+			Usage:  "Print the pages and resource kinds that can be opened, with their keys and aliases",
+			Action: listKinds,
+		},
+		{
+			Name:      "replay",
+			Usage:     "Play back a session recorded with --record",
+			ArgsUsage: "FILE",
+			Action:    replay,
+			Flags: []cli.Flag{
+				cli.Float64Flag{
+					Name:  "speed",
+					Usage: "Playback speed, 2 plays twice as fast",
+					Value: 1,
+				},
+			},
+		},
+	}, completionCommands...)
 	app.Action = run
 
 	if err := app.Run(os.Args); err != nil {
+		// the UI is gone by now, the terminal is where the user looks
+		fmt.Fprintln(os.Stderr, err)
 		logrus.Fatal(err)
 	}
 }
 
-func run(c *cli.Context) error {
-	if c.String("blade") == "rio"{
-		return rio.Start(c)
-	} else if c.String("blade") == "k8s"{
-		return k8s.Start(c)
+// setupLogging is only done for the UI, kinds, replay and the completions print to the terminal and leave no log file behind
+func setupLogging(c *cli.Context) error {
+	level, err := logrus.ParseLevel(c.String("log-level"))
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(c.String("log-file"), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	logrus.SetOutput(file)
+	logrus.SetLevel(level)
+	return nil
+}
+
+func run(c *cli.Context) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			logrus.Errorf("panic: %v\n%s", r, debug.Stack())
+			err = fmt.Errorf("panic: %v, see %s for the stack trace", r, c.String("log-file"))
+		}
+	}()
+	if err := setupLogging(c); err != nil {
+		return err
+	}
+
+	if start, ok := blades[c.String("blade")]; ok {
+		return start(c)
 	}
 
 	logrus.Warnf("You have not register a blade called %s. Exiting...", c.String("blade"))
 	return nil
 }
+
+// replay plays a recording to the terminal until it ends or is interrupted
+func replay(c *cli.Context) error {
+	if c.NArg() != 1 {
+		return fmt.Errorf("replay needs the file recorded with --record")
+	}
+	file, err := os.Open(c.Args().First())
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	interrupts := make(chan os.Signal, 1)
+	signal.Notify(interrupts, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(interrupts)
+	go func() {
+		select {
+		case <-interrupts:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return throwing.Replay(ctx, file, os.Stdout, c.Float64("speed"))
+}
+
+// listKinds runs with the context of the command, the blade reads the global flags from the app's
+func listKinds(c *cli.Context) error {
+	if kinds, ok := bladeKinds[c.GlobalString("blade")]; ok {
+		return kinds(c.Parent())
+	}
+	return fmt.Errorf("blade %s can't list its kinds", c.GlobalString("blade"))
+}
@@ -0,0 +1,83 @@
+package throwing
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/rancher/axe/throwing/datafeeder"
+)
+
+// ageInterval is how often the cells of a datafeeder.Ager are rewritten
+const ageInterval = time.Second
+
+// tick ages the cells of the table while it's on screen, until ctx is done
+func (t *TableView) tick(ctx context.Context) {
+	ticker := time.NewTicker(ageInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+		if t.visible() {
+			t.age()
+		}
+	}
+}
+
+// agedCell is the new text of the cell in row and col
+type agedCell struct {
+	row, col int
+	text     string
+}
+
+/*
+age works out the cells the data source ages from the rows t drew, off the UI goroutine, and rewrites them on it,
+where Table.Draw reads them. The cells are only rewritten when no draw replaced the rows in between.
+*/
+func (t *TableView) age() {
+	t.lock.Lock()
+	ager, ok := t.dataSource.(datafeeder.Ager)
+	if !ok {
+		t.lock.Unlock()
+		return
+	}
+	header := t.dataSource.Header()
+	var aged []agedCell
+	for r, row := range t.drawn {
+		for col := range header {
+			if col >= len(row) {
+				break
+			}
+			text, ok := ager.Age(header, row, col)
+			if !ok || text == row[col] {
+				continue
+			}
+			aged = append(aged, agedCell{row: r + 1, col: col, text: text})
+		}
+	}
+	// the drawn rows are shared with the data source and the pending draw, aged rows are copies
+	for _, c := range aged {
+		row := append(datafeeder.Row(nil), t.drawn[c.row-1]...)
+		row[c.col] = c.text
+		t.drawn[c.row-1] = row
+	}
+	generation := atomic.LoadInt32(&t.drawGeneration)
+	t.lock.Unlock()
+	if len(aged) == 0 {
+		return
+	}
+
+	t.app.QueueUpdateDraw(func() {
+		if generation != atomic.LoadInt32(&t.drawGeneration) {
+			return
+		}
+		for _, c := range aged {
+			if c.row < t.Table.GetRowCount() {
+				t.Table.GetCell(c.row, c.col).SetText(c.text)
+			}
+		}
+	})
+}
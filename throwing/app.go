@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"sync/atomic"
 
 	"github.com/gdamore/tcell"
 	"github.com/rancher/axe/throwing/types"
@@ -32,16 +33,48 @@ type AppView struct {
 	menuView         menuView
 	footerView       footerView
 	searchView       cmdView
+	searchHistories  map[string]*InputHistory
+	notifyView       notifyView
+	connectionView   connectionView
+	latencyView      latencyView
+	detailView       detailView
+	tabBar           tabBar
+	tabs             []*tab
+	activeTab        int
+	keymap           keymap
+	keys             map[string]Keys
+	accent           tcell.Color
+	identity         string
+	stateFile        string
+	serveAddress     string
+	profileAddress   string
+	profile          *profiler
+	recordFile       string
+	recorder         *recorder
+	refreshSlots     chan struct{}
+	startPage        func(root *TableView)
+	running          int32
+	crash            error
+	workers          sync.WaitGroup
+	plain            bool
+	maxCellWidth     int
+	ascii            bool
+	readout          *tview.TextView
+	state            *State
 	content          contentView
 	drawQueue        *PrimitiveQueue
 	tableViews       map[string]*TableView
 	pageRows         map[string]position
 	showMenu         bool
+	split            bool
 	currentPage      string
 	currentPrimitive *TableView
-	switchPage       chan struct{}
-	syncs            map[string]chan struct{}
+	bus              bus
 	lock             sync.Mutex
+
+	// shown is the primitive SwitchPage put on screen last, onLeave what to stop once another one replaces it
+	shown   tview.Primitive
+	onLeave map[tview.Primitive]func()
 }
 
 type position struct {
@@ -49,14 +82,14 @@ type position struct {
 }
 
 /*
-NewAppView takes 4 parameters:
+NewAppView takes 3 parameters:
 	Clientset: Kubernetes client
 	Drawer: Generic drawer to define how the table view looks like
 	Handler: Event handler
-	RefresherSignals: External Signal to trigger table refresh, mapped by resource kind
+Tables refresh when an Event of their kind is published.
 */
-func NewAppView(clientset *kubernetes.Clientset, dr types.Drawer, handler EventHandler, refreshSignals map[string]chan struct{}) *AppView {
-	v := &AppView{Application: tview.NewApplication()}
+func NewAppView(clientset *kubernetes.Clientset, dr types.Drawer, handler EventHandler) *AppView {
+	v := &AppView{Application: tview.NewApplication(), maxCellWidth: defaultMaxCellWidth}
 	{
 		v.Flex = tview.NewFlex()
 		v.drawQueue = &PrimitiveQueue{AppView: v}
@@ -64,11 +97,18 @@ func NewAppView(clientset *kubernetes.Clientset, dr types.Drawer, handler EventH
 		v.content = contentView{AppView: v, Pages: tview.NewPages()}
 		v.footerView = footerView{AppView: v, TextView: tview.NewTextView()}
 		v.searchView = cmdView{AppView: v, InputField: tview.NewInputField()}
+		v.notifyView = notifyView{AppView: v, TextView: tview.NewTextView(), queue: make(chan Notification, notifyQueueSize)}
+		v.detailView = detailView{AppView: v, TextView: tview.NewTextView()}
+		v.connectionView = connectionView{AppView: v, TextView: tview.NewTextView()}
+		v.latencyView = latencyView{AppView: v, TextView: tview.NewTextView()}
+		v.tabBar = tabBar{AppView: v, TextView: tview.NewTextView()}
+		v.tabs = []*tab{{}}
 		v.pageRows = make(map[string]position)
+		v.onLeave = make(map[tview.Primitive]func())
 		v.clientset = clientset
 		v.Drawer = dr
 		v.handler = handler
-		v.syncs = refreshSignals
+		v.initKeys()
 
 		{
 			v.menuView.SetBackgroundColor(tcell.ColorBlack)
@@ -79,63 +119,137 @@ func NewAppView(clientset *kubernetes.Clientset, dr types.Drawer, handler EventH
 	return v
 }
 
+/*
+SetAccent colors the borders and the footer, so the cluster being worked on is obvious at a glance.
+It has to be called before Init, borders take the color when they are created.
+*/
+func (app *AppView) SetAccent(color tcell.Color) {
+	app.accent = color
+	if color != tcell.ColorDefault {
+		tview.Styles.BorderColor = color
+	}
+}
+
+// SetVersion is the app version shown in the menu
+func (app *AppView) SetVersion(version string) {
+	app.version = version
+}
+
+// SetIdentity shows who the app acts as next to the notifications, e.g. an impersonated user
+func (app *AppView) SetIdentity(identity string) {
+	app.identity = identity
+}
+
+// SetMaxRefreshes lets at most n tables refresh at once, the others wait their turn. Zero doesn't limit them.
+func (app *AppView) SetMaxRefreshes(n int) {
+	app.refreshSlots = nil
+	if n > 0 {
+		app.refreshSlots = make(chan struct{}, n)
+	}
+}
+
+/*
+SetStartPage has open called with the root table at the end of Init, to land on another page than the root one,
+like the view asked for on the command line. It wins over the page restored from the last session.
+*/
+func (app *AppView) SetStartPage(open func(root *TableView)) {
+	app.startPage = open
+}
+
 func (app *AppView) Init() error {
-	k8sversion, err := app.getK8sVersion()
-	if err != nil {
-		return err
+	// apps that don't talk to Kubernetes pass no clientset
+	if app.clientset != nil {
+		k8sversion, err := app.getK8sVersion()
+		if err != nil {
+			return err
+		}
+		app.k8sVersion = k8sversion
 	}
 	app.context, app.cancel = context.WithCancel(context.Background())
+	if app.ascii {
+		setBorders('-', '|', '+', '=', '|', '+')
+	}
+	if app.plain {
+		app.initPlain()
+	}
+	if app.recordFile != "" {
+		recorder, err := newRecorder(app.recordFile)
+		if err != nil {
+			return err
+		}
+		app.recorder = recorder
+	}
+	if app.plain || app.ascii || app.recorder != nil {
+		app.Application.SetAfterDrawFunc(app.afterDraw)
+	}
+	app.notifyView.init()
+	app.latencyView.init()
+	app.detailView.init()
 	app.tableViews = map[string]*TableView{
 		app.RootPage: NewTableView(app, app.RootPage, app.Drawer),
 	}
-	app.k8sVersion = k8sversion
 	app.menuView.init()
 	app.footerView.init()
 	app.content.init()
-	app.switchPage = make(chan struct{}, 1)
 
 	// set default page to root page
 	app.footerView.TextView.Highlight(app.RootPage).ScrollToHighlight()
 	app.SwitchPage(app.RootPage, app.tableViews[app.RootPage], app.tableViews[app.RootPage].actions)
+	app.restorePage()
+	if app.startPage != nil {
+		app.startPage(app.tableViews[app.RootPage])
+	}
 
 	// Initialize after switching page so that it has context of current page to search for
 	app.searchView.init()
+	app.tabBar.init()
 
 	app.setInputHandler()
 
-	//go app.watch()
-
 	main := tview.NewFlex()
 	{
 		main.SetDirection(tview.FlexRow)
+		main.AddItem(app.tabBar, 1, 1, false)
+		// the connection banner takes no room until the API server goes away
+		main.AddItem(app.connectionView.TextView, 0, 0, false)
 		main.AddItem(app.content, 0, 15, true)
 
-		search := tview.NewFlex().SetDirection(tview.FlexRow)
+		search := tview.NewFlex().SetDirection(tview.FlexColumn)
 		search.AddItem(app.searchView.InputField, 0, 1, true)
+		search.AddItem(app.notifyView, 0, 1, false)
+		if app.identity != "" {
+			identity := tview.NewTextView().SetText(" " + app.identity + " ").SetTextColor(tcell.ColorBlack)
+			identity.SetBackgroundColor(tcell.ColorYellow)
+			search.AddItem(identity, len(app.identity)+2, 0, false)
+		}
+		search.AddItem(app.latencyView.TextView, latencyWidth, 0, false)
 
 		footer := tview.NewFlex().SetDirection(tview.FlexColumn)
 		footer.AddItem(app.footerView, 0, 1, false)
 		footer.AddItem(app.menuView, 0, 1, false)
 
+		if app.plain {
+			main.AddItem(app.readout, 1, 1, false)
+		}
 		main.AddItem(search, 1, 1, false)
 		main.AddItem(footer, 1, 1, false)
 	}
 
-	app.Application.SetRoot(main, true)
-	return nil
-}
-
-func (app *AppView) watch() {
-	go app.currentPrimitive.run(app.context)
-	for {
-		select {
-		case <-app.switchPage:
-			app.cancel()
-			// recreate context
-			app.context, app.cancel = context.WithCancel(app.context)
-			go app.currentPrimitive.run(app.context)
+	if app.clientset != nil {
+		app.connectionView.init(main)
+	}
+	if app.serveAddress != "" {
+		if err := app.serve(); err != nil {
+			return err
 		}
 	}
+	if app.profileAddress != "" {
+		if err := app.serveProfile(); err != nil {
+			return err
+		}
+	}
+	app.Application.SetRoot(main, true)
+	return nil
 }
 
 /*
@@ -146,7 +260,11 @@ M(Menu): Menu view
 Escape: go back to the previous view
 */
 func (app *AppView) setInputHandler() {
-	app.SetInputCapture(EscapeEventHandler(app))
+	handler := EscapeEventHandler(app)
+	app.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		app.recorder.key(event)
+		return handler(event)
+	})
 }
 
 func (app *AppView) getK8sVersion() (string, error) {
@@ -158,28 +276,54 @@ func (app *AppView) getK8sVersion() (string, error) {
 }
 
 func (app *AppView) SwitchPage(page string, p tview.Primitive, actions []types.Action) {
+	if app.shown != nil && app.shown != p {
+		if leave, ok := app.onLeave[app.shown]; ok {
+			delete(app.onLeave, app.shown)
+			leave()
+		}
+		if t, ok := app.shown.(*TableView); ok {
+			atomic.StoreInt32(&t.shown, 0)
+		}
+	}
+	app.shown = p
+	if t, ok := p.(*TableView); ok {
+		atomic.StoreInt32(&t.shown, 1)
+	}
 	app.Menu = actions
 	app.menuView.TextView.Clear()
 	app.menuView.init()
 	if app.currentPage != page {
-		cp := app.currentPage
 		app.currentPage = page
 		if _, ok := p.(*TableView); ok {
 			app.currentPrimitive = p.(*TableView)
 		}
-		if cp != "" {
-			go func() {
-				app.switchPage <- struct{}{}
-			}()
-		}
 	}
-	app.content.AddAndSwitchToPage(page, p, true)
+	if t, ok := p.(*TableView); ok {
+		t.resume()
+		app.readOut(t)
+	}
+	app.content.AddAndSwitchToPage(page, app.splitLayout(p), true)
 
 	app.drawQueue.Enqueue(PageTrack{
 		PageName:  page,
 		Primitive: p,
 	})
 	app.SetFocus(p)
+	app.tabBar.draw()
+}
+
+// SetActions replaces the menu of the table of kind, and the menu on screen when that table is shown. It's only called from the UI goroutine.
+func (app *AppView) SetActions(kind string, actions []types.Action) {
+	t, ok := app.tableViews[kind]
+	if !ok {
+		return
+	}
+	t.actions = actions
+	if app.shown == t {
+		app.Menu = actions
+		app.menuView.TextView.Clear()
+		app.menuView.init()
+	}
 }
 
 func (app *AppView) SwitchToRootPage() {
@@ -194,7 +338,7 @@ func (app *AppView) CurrentPage() tview.Primitive {
 func (app *AppView) LastPage() {
 	app.drawQueue.Dequeue()
 	page := app.drawQueue.Last()
-	app.SwitchPage(page.PageName, page.Primitive, page.Primitive.(*TableView).actions)
+	app.SwitchPage(page.PageName, page.Primitive, trackActions(page))
 }
 
 type menuView struct {
@@ -232,8 +376,10 @@ func (m *menuView) versionView() *tview.Table {
 
 	t.SetCell(0, 0, rioVersionHeader)
 	t.SetCell(0, 1, rioVersionValue)
-	t.SetCell(1, 0, k8sVersionHeader)
-	t.SetCell(1, 1, k8sVersionValue)
+	if m.k8sVersion != "" {
+		t.SetCell(1, 0, k8sVersionHeader)
+		t.SetCell(1, 1, k8sVersionValue)
+	}
 	return t
 }
 
@@ -268,6 +414,7 @@ func (s *cmdView) init() {
 	s.InputField.SetFieldBackgroundColor(tcell.ColorBlack)
 	s.InputField.SetFieldTextColor(tcell.ColorBlue)
 	s.InputField.SetDoneFunc(searchDoneEventHandler(s.AppView))
+	s.InputField.SetChangedFunc(searchChangedEventHandler(s.AppView))
 }
 
 type footerView struct {
@@ -280,8 +427,14 @@ func (f *footerView) init() {
 		SetDynamicColors(true).
 		SetRegions(true).
 		SetWrap(false).SetBackgroundColor(tcell.ColorGray)
-	for index, t := range f.Footers {
-		fmt.Fprintf(f.TextView, `%d ["%s"][black]%s[white][""] `, index+1, t.Kind, t.Title)
+	if f.accent != tcell.ColorDefault {
+		f.TextView.SetBackgroundColor(f.accent)
+	}
+	for _, t := range f.Footers {
+		if key := f.pageKey(t.Kind); key != "" {
+			fmt.Fprintf(f.TextView, "%s ", key)
+		}
+		fmt.Fprintf(f.TextView, `["%s"][black]%s[white][""] `, t.Kind, t.Title)
 	}
 }
 
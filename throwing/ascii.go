@@ -0,0 +1,69 @@
+package throwing
+
+import (
+	"os"
+	"strings"
+)
+
+// asciiRunes are the ASCII stand-ins of the icons, arrows and spinners apps and tview draw
+var asciiRunes = map[rune]rune{
+	'✔': '+', '✓': '+', '✖': 'x', '✗': 'x', '◔': '~', '…': '.',
+	'●': '*', '•': '*', '○': 'o', '◉': '*', '★': '*', '☆': '*',
+	'▲': '^', '▼': 'v', '▶': '>', '►': '>', '◀': '<', '◄': '<',
+	'↑': '^', '↓': 'v', '→': '>', '←': '<',
+	'█': '#', '▓': '#', '▒': ':', '░': '.',
+}
+
+/*
+SetASCII draws borders, icons and spinners with ASCII, for terminals without UTF-8.
+It has to be called before Init, UnicodeLocale tells whether it's needed.
+*/
+func (app *AppView) SetASCII(ascii bool) {
+	app.ascii = ascii
+}
+
+// ASCII reports whether the app draws with ASCII only, apps can pick their own icons by it
+func (app *AppView) ASCII() bool {
+	return app.ascii
+}
+
+// UnicodeLocale reports whether the locale has a UTF-8 charset, an unset locale is taken for UTF-8
+func UnicodeLocale() bool {
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if locale := os.Getenv(name); locale != "" {
+			locale = strings.ToLower(locale)
+			return strings.Contains(locale, "utf-8") || strings.Contains(locale, "utf8")
+		}
+	}
+	return true
+}
+
+func asciiRune(r rune) rune {
+	if r < 0x80 {
+		return r
+	}
+	if a, ok := asciiRunes[r]; ok {
+		return a
+	}
+	switch {
+	case r >= '─' && r <= '╿':
+		return boxRune(r)
+	case r >= '▀' && r <= '▟':
+		return '#'
+	case r >= 0x2800 && r <= 0x28ff:
+		// braille spinners
+		return '*'
+	}
+	return r
+}
+
+// boxRune maps a box drawing character to -, | or + by the lines it's made of
+func boxRune(r rune) rune {
+	switch r {
+	case '─', '━', '═', '┄', '┅', '┈', '┉', '╌', '╍', '╴', '╶', '╸', '╺':
+		return '-'
+	case '│', '┃', '║', '┆', '┇', '┊', '┋', '╎', '╏', '╵', '╷', '╹', '╻':
+		return '|'
+	}
+	return '+'
+}
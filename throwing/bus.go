@@ -0,0 +1,75 @@
+package throwing
+
+import (
+	"sync"
+)
+
+const subscriberBuffer = 16
+
+// Event types published by the framework, apps are free to publish their own
+const (
+	EventRefresh = "refresh"
+	EventChanged = "changed"
+	EventDeleted = "deleted"
+)
+
+/*
+Event tells the views of Kind that something happened to it.
+Namespace and Name narrow it down to one object when they are set.
+*/
+type Event struct {
+	Kind      string
+	Type      string
+	Namespace string
+	Name      string
+}
+
+// bus hands every event to the subscribers of its kind, a subscriber of "" gets all of them
+type bus struct {
+	lock        sync.Mutex
+	subscribers map[string]map[chan Event]struct{}
+}
+
+/*
+Subscribe returns a channel receiving the events of kind, "" subscribes to every kind.
+A subscriber that falls behind by more than a few events misses the newest ones.
+The returned func unsubscribes and closes the channel.
+*/
+func (app *AppView) Subscribe(kind string) (<-chan Event, func()) {
+	b := &app.bus
+	ch := make(chan Event, subscriberBuffer)
+	b.lock.Lock()
+	if b.subscribers == nil {
+		b.subscribers = map[string]map[chan Event]struct{}{}
+	}
+	if b.subscribers[kind] == nil {
+		b.subscribers[kind] = map[chan Event]struct{}{}
+	}
+	b.subscribers[kind][ch] = struct{}{}
+	b.lock.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.lock.Lock()
+			delete(b.subscribers[kind], ch)
+			b.lock.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// Publish hands e to the subscribers of its kind without blocking, it's safe to call from any goroutine
+func (app *AppView) Publish(e Event) {
+	b := &app.bus
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	for _, kind := range []string{e.Kind, ""} {
+		for ch := range b.subscribers[kind] {
+			select {
+			case ch <- e:
+			default:
+			}
+		}
+	}
+}
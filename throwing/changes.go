@@ -0,0 +1,141 @@
+package throwing
+
+import (
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/gdamore/tcell"
+	"github.com/rancher/axe/throwing/datafeeder"
+)
+
+const highlightDuration = 3 * time.Second
+
+var (
+	addedColor    = tcell.ColorDarkGreen
+	modifiedColor = tcell.ColorDarkBlue
+	removedColor  = tcell.ColorDarkRed
+)
+
+type rowChange int
+
+const (
+	rowUnchanged rowChange = iota
+	rowAdded
+	rowModified
+)
+
+// rowKey identifies a row across refreshes by its namespace and name
+func rowKey(header, row datafeeder.Row) string {
+	nameCol, namespaceCol := nameColumns(header)
+	var name, namespace string
+	if nameCol < len(row) {
+		name = row[nameCol]
+	}
+	if namespaceCol >= 0 && namespaceCol < len(row) {
+		namespace = row[namespaceCol]
+	}
+	return namespace + "/" + name
+}
+
+/*
+diffRows compares data with the rows of the previous refresh and remembers data for the next one.
+The first refresh of a table reports no changes.
+*/
+func (t *TableView) diffRows(header datafeeder.Row, data []datafeeder.Row) (map[string]rowChange, []datafeeder.Row) {
+	current := map[string]datafeeder.Row{}
+	for _, row := range data {
+		if len(row) > 0 && row[0] == "" {
+			continue
+		}
+		current[rowKey(header, row)] = row
+	}
+
+	previous := t.previousRows
+	t.previousRows = current
+	if previous == nil {
+		return nil, nil
+	}
+
+	changes := map[string]rowChange{}
+	for key, row := range current {
+		old, ok := previous[key]
+		switch {
+		case !ok:
+			changes[key] = rowAdded
+		case strings.Join(old, "\t") != strings.Join(row, "\t"):
+			changes[key] = rowModified
+		}
+	}
+	var removed []datafeeder.Row
+	for key, row := range previous {
+		if _, ok := current[key]; !ok {
+			removed = append(removed, row)
+		}
+	}
+	return changes, removed
+}
+
+func (c rowChange) color() tcell.Color {
+	switch c {
+	case rowAdded:
+		return addedColor
+	case rowModified:
+		return modifiedColor
+	}
+	return tcell.ColorDefault
+}
+
+func (t *TableView) setRowBackground(row int, color tcell.Color) {
+	for col := 0; col < t.Table.GetColumnCount(); col++ {
+		t.Table.GetCell(row, col).SetBackgroundColor(color)
+	}
+}
+
+/*
+fadeChanges takes the highlight off changed rows once it has been visible for a while.
+Removed rows are dimmed half way through and dropped at the end, a newer draw makes the pending fade a no-op.
+The steps change the cells on the UI goroutine.
+*/
+func (t *TableView) fadeChanges(generation int32, highlighted []int, removed int) {
+	stale := func() bool {
+		return generation != atomic.LoadInt32(&t.drawGeneration)
+	}
+	fade := func(step func()) {
+		t.app.QueueUpdateDraw(func() {
+			if !stale() {
+				step()
+			}
+		})
+	}
+
+	time.Sleep(highlightDuration / 2)
+	if stale() {
+		return
+	}
+	fade(func() {
+		rows := t.Table.GetRowCount()
+		for row := rows - removed; row < rows; row++ {
+			for col := 0; col < t.Table.GetColumnCount(); col++ {
+				t.Table.GetCell(row, col).SetAttributes(tcell.AttrDim).SetBackgroundColor(tcell.ColorDefault)
+			}
+		}
+	})
+
+	time.Sleep(highlightDuration / 2)
+	if removed > 0 {
+		t.lock.Lock()
+		if !stale() {
+			t.drawn = t.drawn[:len(t.drawn)-removed]
+		}
+		t.lock.Unlock()
+	}
+	fade(func() {
+		for _, row := range highlighted {
+			t.setRowBackground(row, tcell.ColorDefault)
+		}
+		for i := 0; i < removed; i++ {
+			t.Table.RemoveRow(t.Table.GetRowCount() - 1)
+		}
+	})
+}
@@ -0,0 +1,106 @@
+package throwing
+
+import (
+	"strings"
+	"sync/atomic"
+
+	"github.com/gdamore/tcell"
+)
+
+// defaultMaxCellWidth is how wide a body cell is drawn before it's cut off with an ellipsis
+const defaultMaxCellWidth = 50
+
+/*
+SetMaxCellWidth cuts body cells wider than width with an ellipsis, so one long value doesn't push the
+other columns off the screen. ShowCell shows the whole value, zero draws every cell in full.
+*/
+func (app *AppView) SetMaxCellWidth(width int) {
+	app.maxCellWidth = width
+}
+
+/*
+fixedColumns is how many columns stay put when a wide table scrolls sideways:
+NAMESPACE and NAME when the table starts with them, otherwise the first column.
+*/
+func fixedColumns(header []string) int {
+	fixed := 0
+	for fixed < len(header) && fixed < 2 && (header[fixed] == "NAMESPACE" || header[fixed] == "NAME") {
+		fixed++
+	}
+	if fixed == 0 && len(header) > 0 {
+		fixed = 1
+	}
+	return fixed
+}
+
+/*
+columnKeys has Left and Right move the column cursor of t before handler sees the key.
+The cursor's header is drawn reversed and the table scrolls so the cursor is the first column after the fixed ones.
+*/
+func (t *TableView) columnKeys(handler func(event *tcell.EventKey) *tcell.EventKey) func(event *tcell.EventKey) *tcell.EventKey {
+	return func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyLeft:
+			t.moveColumn(-1)
+			return nil
+		case tcell.KeyRight:
+			t.moveColumn(1)
+			return nil
+		}
+		return handler(event)
+	}
+}
+
+func (t *TableView) moveColumn(step int) {
+	columns := t.Table.GetColumnCount()
+	if columns == 0 {
+		return
+	}
+	column := int(atomic.LoadInt32(&t.column)) + step
+	if column < 0 {
+		column = 0
+	}
+	if column >= columns {
+		column = columns - 1
+	}
+	atomic.StoreInt32(&t.column, int32(column))
+
+	var header []string
+	for col := 0; col < columns; col++ {
+		header = append(header, strings.TrimPrefix(t.Table.GetCell(0, col).Text, "[white]"))
+	}
+	offset := column - fixedColumns(header)
+	if offset < 0 {
+		offset = 0
+	}
+	row, _ := t.Table.GetOffset()
+	t.Table.SetOffset(row, offset)
+	t.markColumn()
+}
+
+// markColumn reverses the header of the column cursor, a column past the end after a redraw moves it to the last one
+func (t *TableView) markColumn() {
+	columns := t.Table.GetColumnCount()
+	column := int(atomic.LoadInt32(&t.column))
+	if column >= columns && columns > 0 {
+		column = columns - 1
+		atomic.StoreInt32(&t.column, int32(column))
+	}
+	for col := 0; col < columns; col++ {
+		attributes := tcell.AttrBold
+		if col == column {
+			attributes |= tcell.AttrReverse
+		}
+		t.Table.GetCell(0, col).SetAttributes(attributes)
+	}
+}
+
+// ShowCell pops up the whole value of the selected row in the column cursor's column
+func (t *TableView) ShowCell() {
+	header, row := t.SelectedRow()
+	column := int(atomic.LoadInt32(&t.column))
+	if column >= len(row) || column >= len(header) {
+		return
+	}
+	t.app.showText(header[column], row[column], dialogWidth, false)
+}
@@ -0,0 +1,99 @@
+package throwing
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gdamore/tcell"
+	"github.com/rivo/tview"
+)
+
+const connectionProbePeriod = 5 * time.Second
+
+/*
+connectionView probes the API server and shows a banner while it can't be reached.
+Tables keep their last data in the meantime and draw a red border to mark it stale,
+the current table is refreshed once the server answers again.
+*/
+type connectionView struct {
+	*tview.TextView
+	*AppView
+	layout  *tview.Flex
+	check   chan struct{}
+	lock    sync.Mutex
+	offline bool
+	since   time.Time
+}
+
+func (c *connectionView) init(layout *tview.Flex) {
+	c.layout = layout
+	c.check = make(chan struct{}, 1)
+	c.TextView.
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter).
+		SetWrap(false).SetBackgroundColor(tcell.ColorRed)
+	c.AppView.Go(func() { c.run(c.AppView.context) })
+}
+
+func (c *connectionView) run(ctx context.Context) {
+	ticker := time.NewTicker(connectionProbePeriod)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-c.check:
+		case <-ctx.Done():
+			return
+		}
+		_, err := c.clientset.Discovery().ServerVersion()
+		c.update(err)
+	}
+}
+
+// probe checks the connection right away, e.g. after a refresh failed
+func (c *connectionView) probe() {
+	select {
+	case c.check <- struct{}{}:
+	default:
+	}
+}
+
+func (c *connectionView) update(err error) {
+	c.lock.Lock()
+	wasOffline := c.offline
+	c.offline = err != nil
+	if c.offline && !wasOffline {
+		c.since = time.Now()
+	}
+	since := c.since
+	c.lock.Unlock()
+
+	switch {
+	case err != nil:
+		text := fmt.Sprintf("[black]API server unreachable since %s, reconnecting... showing stale data: %s",
+			since.Format("15:04:05"), tview.Escape(err.Error()))
+		c.Application.QueueUpdateDraw(func() {
+			c.TextView.SetText(text)
+			c.layout.ResizeItem(c.TextView, 1, 0)
+		})
+	case wasOffline:
+		// the pages are the UI goroutine's, the page on screen is looked up there
+		c.Application.QueueUpdateDraw(func() {
+			c.TextView.Clear()
+			c.layout.ResizeItem(c.TextView, 0, 0)
+			if t, ok := c.tableViews[c.currentPage]; ok {
+				t.Refresh()
+			}
+		})
+		c.Notify("reconnected to the API server", SeverityInfo)
+	}
+}
+
+// Offline reports whether the API server couldn't be reached on the last probe
+func (app *AppView) Offline() bool {
+	app.connectionView.lock.Lock()
+	defer app.connectionView.lock.Unlock()
+	return app.connectionView.offline
+}
@@ -0,0 +1,130 @@
+package datafeeder
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// fixtureSeparator splits the scripted updates of a fixture file
+const fixtureSeparator = "---"
+
+// FakeStep is what one Refresh of a Fake does: fail with Err, or replace the rows
+type FakeStep struct {
+	Rows []Row
+	Err  error
+}
+
+/*
+Fake is a DataSource for developing and demoing views without a cluster.
+It serves static rows, plays back scripted updates one per Refresh and fails on demand,
+a failing refresh keeps the rows of the last good one.
+*/
+type Fake struct {
+	lock      sync.Mutex
+	header    Row
+	rows      []Row
+	script    []FakeStep
+	err       error
+	refreshes int
+}
+
+func NewFake(header Row, rows ...Row) *Fake {
+	return &Fake{header: header, rows: rows}
+}
+
+/*
+LoadFixture reads a fixture in the format the refreshers write: a tab separated header line, then a tab separated line per row.
+Lines of --- start a scripted update with rows of its own, blank lines and lines starting with # are skipped.
+*/
+func LoadFixture(path string) (*Fake, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ReadFixture(file)
+}
+
+// ReadFixture reads a fixture like LoadFixture from r
+func ReadFixture(r io.Reader) (*Fake, error) {
+	f := &Fake{}
+	var steps []FakeStep
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#"):
+			continue
+		case strings.TrimSpace(line) == fixtureSeparator:
+			steps = append(steps, FakeStep{})
+		case f.header == nil:
+			f.header = Row(strings.Split(line, "\t"))
+		case len(steps) == 0:
+			f.rows = append(f.rows, Row(strings.Split(line, "\t")))
+		default:
+			steps[len(steps)-1].Rows = append(steps[len(steps)-1].Rows, Row(strings.Split(line, "\t")))
+		}
+	}
+	f.script = steps
+	return f, scanner.Err()
+}
+
+// Script queues steps, every Refresh plays the next one, once they're used up the data stays as it is
+func (f *Fake) Script(steps ...FakeStep) *Fake {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.script = append(f.script, steps...)
+	return f
+}
+
+// SetRows replaces the rows right away, the table shows them on its next refresh
+func (f *Fake) SetRows(rows ...Row) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.rows = rows
+}
+
+// FailWith makes every Refresh after the script return err, nil makes them succeed again
+func (f *Fake) FailWith(err error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.err = err
+}
+
+// Refreshes counts the calls to Refresh
+func (f *Fake) Refreshes() int {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return f.refreshes
+}
+
+func (f *Fake) Refresh() error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.refreshes++
+	if len(f.script) > 0 {
+		step := f.script[0]
+		f.script = f.script[1:]
+		if step.Err != nil {
+			return step.Err
+		}
+		f.rows = step.Rows
+		return nil
+	}
+	return f.err
+}
+
+func (f *Fake) Data() []Row {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return append([]Row(nil), f.rows...)
+}
+
+func (f *Fake) Header() Row {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return f.header
+}
@@ -0,0 +1,130 @@
+package datafeeder
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadFixture(t *testing.T) {
+	tests := []struct {
+		name    string
+		fixture string
+		header  Row
+		rows    []Row
+		script  []FakeStep
+	}{
+		{
+			name:    "header only",
+			fixture: "NAME\tSTATUS\n",
+			header:  Row{"NAME", "STATUS"},
+		},
+		{
+			name:    "rows",
+			fixture: "NAME\tSTATUS\nweb\tRunning\ndb\tPending\n",
+			header:  Row{"NAME", "STATUS"},
+			rows:    []Row{{"web", "Running"}, {"db", "Pending"}},
+		},
+		{
+			name:    "comments and blank lines",
+			fixture: "# pods\n\nNAME\tSTATUS\n\nweb\tRunning\n# db is gone\n",
+			header:  Row{"NAME", "STATUS"},
+			rows:    []Row{{"web", "Running"}},
+		},
+		{
+			name:    "scripted updates",
+			fixture: "NAME\tSTATUS\nweb\tPending\n---\nweb\tRunning\n ---\n---\nweb\tFailed\ndb\tRunning\n",
+			header:  Row{"NAME", "STATUS"},
+			rows:    []Row{{"web", "Pending"}},
+			script: []FakeStep{
+				{Rows: []Row{{"web", "Running"}}},
+				{},
+				{Rows: []Row{{"web", "Failed"}, {"db", "Running"}}},
+			},
+		},
+		{
+			name:    "empty cells",
+			fixture: "NAME\tSTATUS\tAGE\nweb\t\t5m\n",
+			header:  Row{"NAME", "STATUS", "AGE"},
+			rows:    []Row{{"web", "", "5m"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := ReadFixture(strings.NewReader(tt.fixture))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(f.Header(), tt.header) {
+				t.Errorf("header is %q, want %q", f.Header(), tt.header)
+			}
+			if !reflect.DeepEqual(f.Data(), tt.rows) {
+				t.Errorf("rows are %q, want %q", f.Data(), tt.rows)
+			}
+			if !reflect.DeepEqual(f.script, tt.script) {
+				t.Errorf("script is %+v, want %+v", f.script, tt.script)
+			}
+		})
+	}
+}
+
+func TestRefresh(t *testing.T) {
+	errDown := errors.New("api server down")
+	header := Row{"NAME", "STATUS"}
+	initial := []Row{{"web", "Pending"}}
+	running := []Row{{"web", "Running"}}
+	failed := []Row{{"web", "Failed"}}
+
+	tests := []struct {
+		name   string
+		script []FakeStep
+		// failWith is set after the script is queued, it's returned once the script is used up
+		failWith error
+		// want is the error and the rows after each Refresh
+		want []FakeStep
+	}{
+		{
+			name: "no script keeps the rows",
+			want: []FakeStep{{Rows: initial}, {Rows: initial}},
+		},
+		{
+			name:   "steps in order",
+			script: []FakeStep{{Rows: running}, {Rows: failed}},
+			want:   []FakeStep{{Rows: running}, {Rows: failed}, {Rows: failed}},
+		},
+		{
+			name:   "injected error keeps the last good rows",
+			script: []FakeStep{{Rows: running}, {Err: errDown}, {Rows: failed}},
+			want:   []FakeStep{{Rows: running}, {Err: errDown, Rows: running}, {Rows: failed}},
+		},
+		{
+			name:   "error as the first step",
+			script: []FakeStep{{Err: errDown}, {Rows: running}},
+			want:   []FakeStep{{Err: errDown, Rows: initial}, {Rows: running}},
+		},
+		{
+			name:     "failing after the script",
+			script:   []FakeStep{{Rows: running}},
+			failWith: errDown,
+			want:     []FakeStep{{Rows: running}, {Err: errDown, Rows: running}, {Err: errDown, Rows: running}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := NewFake(header, initial...).Script(tt.script...)
+			f.FailWith(tt.failWith)
+			for i, want := range tt.want {
+				if err := f.Refresh(); err != want.Err {
+					t.Errorf("refresh %d failed with %v, want %v", i+1, err, want.Err)
+				}
+				if !reflect.DeepEqual(f.Data(), want.Rows) {
+					t.Errorf("rows after refresh %d are %q, want %q", i+1, f.Data(), want.Rows)
+				}
+			}
+			if f.Refreshes() != len(tt.want) {
+				t.Errorf("counted %d refreshes, want %d", f.Refreshes(), len(tt.want))
+			}
+		})
+	}
+}
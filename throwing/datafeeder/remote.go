@@ -0,0 +1,252 @@
+package datafeeder
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gdamore/tcell"
+)
+
+const (
+	defaultRemoteTimeout = 10 * time.Second
+	maxReconnectDelay    = 30 * time.Second
+)
+
+/*
+Watcher is optionally implemented by a DataSource that knows when its data changes.
+Tables run Watch for as long as they live and refresh whenever changed is called.
+*/
+type Watcher interface {
+	Watch(ctx context.Context, changed func())
+}
+
+// Snapshot is the JSON a remote endpoint answers with: {"header": ["NAME", ...], "rows": [["a", ...], ...]}
+type Snapshot struct {
+	Header Row   `json:"header"`
+	Rows   []Row `json:"rows"`
+}
+
+/*
+Remote is a DataSource reading Snapshots from an HTTP endpoint, so tables can show data that doesn't come from the cluster.
+By default every Refresh fetches the URL, SetPollInterval refreshes it on a timer as well.
+SetPush instead keeps a streaming request open and takes every Snapshot the server sends, one JSON document per line;
+lines of server-sent events ("data: {...}") work as well.
+Only HTTP with JSON is spoken, a gRPC endpoint is deliberately left for later, it needs a proto schema
+and grpc-go vendored and a gateway in front of the service serves this package in the meantime.
+*/
+type Remote struct {
+	lock     sync.Mutex
+	url      string
+	client   *http.Client
+	headers  http.Header
+	interval time.Duration
+	push     bool
+	snapshot Snapshot
+	err      error
+	rowColor RowColorFunc
+}
+
+func NewRemote(url string) *Remote {
+	return &Remote{
+		url:     url,
+		client:  &http.Client{Timeout: defaultRemoteTimeout},
+		headers: http.Header{},
+	}
+}
+
+// SetHeader adds a header to every request, e.g. Authorization
+func (r *Remote) SetHeader(key, value string) *Remote {
+	r.headers.Set(key, value)
+	return r
+}
+
+// SetClient replaces the http.Client, a streaming endpoint needs one without a timeout
+func (r *Remote) SetClient(client *http.Client) *Remote {
+	r.client = client
+	return r
+}
+
+// SetPollInterval refreshes the tables showing r every interval, zero only refreshes on demand
+func (r *Remote) SetPollInterval(interval time.Duration) *Remote {
+	r.interval = interval
+	return r
+}
+
+// SetPush has the server push Snapshots over a streaming request instead of fetching them
+func (r *Remote) SetPush(push bool) *Remote {
+	r.push = push
+	return r
+}
+
+// SetRowColor sets the function used to color rows, nil leaves every row uncolored
+func (r *Remote) SetRowColor(f RowColorFunc) *Remote {
+	r.rowColor = f
+	return r
+}
+
+func (r *Remote) RowColor(header, row Row) tcell.Color {
+	if r.rowColor == nil {
+		return tcell.ColorDefault
+	}
+	return r.rowColor(header, row)
+}
+
+// Refresh fetches the URL, when pushing it reports whether the stream is broken. A failure keeps the previous data.
+func (r *Remote) Refresh() error {
+	if r.push {
+		r.lock.Lock()
+		defer r.lock.Unlock()
+		return r.err
+	}
+
+	resp, err := r.get(context.Background(), r.client)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var snapshot Snapshot
+	if err := json.NewDecoder(resp.Body).Decode(&snapshot); err != nil {
+		return fmt.Errorf("decoding %s: %v", r.url, err)
+	}
+	r.set(snapshot, nil)
+	return nil
+}
+
+func (r *Remote) Data() []Row {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.snapshot.Rows
+}
+
+func (r *Remote) Header() Row {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.snapshot.Header
+}
+
+// Watch polls or follows the stream of r until ctx is done
+func (r *Remote) Watch(ctx context.Context, changed func()) {
+	if r.push {
+		r.stream(ctx, changed)
+		return
+	}
+	if r.interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			changed()
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// stream reads Snapshots off the open request and reconnects with a growing delay when it breaks
+func (r *Remote) stream(ctx context.Context, changed func()) {
+	// the client's timeout would cut the stream, only the context ends it
+	client := *r.client
+	client.Timeout = 0
+
+	delay := time.Second
+	for {
+		received, err := r.follow(ctx, &client, changed)
+		if ctx.Err() != nil {
+			return
+		}
+		if received {
+			delay = time.Second
+		}
+		r.set(r.current(), fmt.Errorf("stream %s: %v", r.url, err))
+		changed()
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return
+		}
+		if delay *= 2; delay > maxReconnectDelay {
+			delay = maxReconnectDelay
+		}
+	}
+}
+
+// follow reads one streaming response, received tells whether any Snapshot came through
+func (r *Remote) follow(ctx context.Context, client *http.Client, changed func()) (received bool, err error) {
+	resp, err := r.get(ctx, client)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(nil, 16*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		line = bytes.TrimSpace(bytes.TrimPrefix(line, []byte("data:")))
+		if len(line) == 0 || line[0] != '{' {
+			// blank lines, event names and comments of server-sent events
+			continue
+		}
+		var snapshot Snapshot
+		if err := json.Unmarshal(line, &snapshot); err != nil {
+			return received, fmt.Errorf("decoding: %v", err)
+		}
+		received = true
+		r.set(snapshot, nil)
+		changed()
+	}
+	if err := scanner.Err(); err != nil {
+		return received, err
+	}
+	return received, fmt.Errorf("closed by the server")
+}
+
+func (r *Remote) get(ctx context.Context, client *http.Client) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, r.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	for key, values := range r.headers {
+		req.Header[key] = values
+	}
+	if r.push {
+		req.Header.Set("Accept", "text/event-stream, application/x-ndjson")
+	} else {
+		req.Header.Set("Accept", "application/json")
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s: %s", r.url, resp.Status)
+	}
+	return resp, nil
+}
+
+func (r *Remote) set(snapshot Snapshot, err error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.snapshot = snapshot
+	r.err = err
+}
+
+func (r *Remote) current() Snapshot {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.snapshot
+}
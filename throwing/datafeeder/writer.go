@@ -3,6 +3,8 @@ package datafeeder
 import (
 	"bytes"
 	"strings"
+
+	"github.com/gdamore/tcell"
 )
 
 type Row []string
@@ -13,39 +15,95 @@ type DataSource interface {
 	Refresh() error
 }
 
-type dataFeeder struct {
+/*
+RowStyler is optionally implemented by a DataSource to give rows a color hint.
+Returning tcell.ColorDefault keeps the table's default color.
+*/
+type RowStyler interface {
+	RowColor(header, row Row) tcell.Color
+}
+
+type RowColorFunc func(header, row Row) tcell.Color
+
+/*
+Ager is optionally implemented by a DataSource with cells derived from the clock, like AGE.
+Age returns what cell col of row reads now, false for cells that don't change with time.
+Tables ask every second so ages stay right between refreshes.
+*/
+type Ager interface {
+	Age(header, row Row, col int) (string, bool)
+}
+
+type AgeFunc func(header, row Row, col int) (string, bool)
+
+// DataFeeder is a DataSource reading the tab separated lines written by its refresher, the first line is the header
+type DataFeeder struct {
 	rows       []Row
 	rowLocator map[string]int
 	header     Row
 	refresher  func(buffer *bytes.Buffer) error
 	buffer     *bytes.Buffer
+	rowColor   RowColorFunc
+	age        AgeFunc
 }
 
-func NewDataFeeder(r func(buffer *bytes.Buffer) error) *dataFeeder {
-	return &dataFeeder{
+func NewDataFeeder(r func(buffer *bytes.Buffer) error) *DataFeeder {
+	return &DataFeeder{
 		buffer:     new(bytes.Buffer),
 		refresher:  r,
 		rowLocator: map[string]int{},
 	}
 }
 
-func (c *dataFeeder) Refresh() error {
-	c.buffer.Reset()
+// SetRowColor sets the function used to color rows, nil leaves every row uncolored
+func (c *DataFeeder) SetRowColor(f RowColorFunc) *DataFeeder {
+	c.rowColor = f
+	return c
+}
+
+func (c *DataFeeder) RowColor(header, row Row) tcell.Color {
+	if c.rowColor == nil {
+		return tcell.ColorDefault
+	}
+	return c.rowColor(header, row)
+}
+
+// SetAge sets the function aging cells between refreshes, nil leaves every cell as the refresher wrote it
+func (c *DataFeeder) SetAge(f AgeFunc) *DataFeeder {
+	c.age = f
+	return c
+}
+
+func (c *DataFeeder) Age(header, row Row, col int) (string, bool) {
+	if c.age == nil {
+		return "", false
+	}
+	return c.age(header, row, col)
+}
+
+// Refresh keeps the previous data when the refresher fails, so tables still show something while the API server is away
+func (c *DataFeeder) Refresh() error {
+	buffer := new(bytes.Buffer)
+	if err := c.refresher(buffer); err != nil {
+		return err
+	}
+	c.buffer = buffer
 	c.header = nil
 	c.rows = nil
-	return c.refresher(c.buffer)
+	return nil
 }
 
-func (c *dataFeeder) Data() []Row {
+func (c *DataFeeder) Data() []Row {
 	content := c.buffer.String()
 	body := strings.Split(content, "\n")[1:]
+	c.rows = nil
 	for _, b := range body {
 		c.rows = append(c.rows, Row(strings.Split(b, "\t")))
 	}
 	return c.rows
 }
 
-func (c *dataFeeder) Header() Row {
+func (c *DataFeeder) Header() Row {
 	content := c.buffer.String()
 	header := strings.Split(content, "\n")[0]
 	c.header = strings.Split(header, "\t")
@@ -0,0 +1,131 @@
+package throwing
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gdamore/tcell"
+	"github.com/rivo/tview"
+)
+
+// detailDelay lets the selection settle before the detail of a row is fetched
+const detailDelay = 200 * time.Millisecond
+
+/*
+detailView is the pane next to the table in split mode.
+It follows the selected row of the current table, rendering it with the drawer's Detail func.
+*/
+type detailView struct {
+	*tview.TextView
+	*AppView
+	lock       sync.Mutex
+	generation int
+}
+
+func (d *detailView) init() {
+	d.TextView.SetBorder(true)
+	d.TextView.SetTitle("Detail")
+	d.TextView.SetTitleColor(tcell.ColorPurple)
+	d.TextView.SetDynamicColors(true).SetBackgroundColor(tcell.ColorBlack)
+}
+
+// SelectedRow reads the header and the selected row from the table cells, so search filtering is taken into account
+func (t *TableView) SelectedRow() ([]string, []string) {
+	row, _ := t.Table.GetSelection()
+	var header, values []string
+	for col := 0; col < t.Table.GetColumnCount(); col++ {
+		header = append(header, strings.TrimPrefix(t.Table.GetCell(0, col).Text, "[white]"))
+		values = append(values, t.Table.GetCell(row, col).Text)
+	}
+	return header, values
+}
+
+// show renders the selected row of t, results of older calls are dropped when the selection moved on
+func (d *detailView) show(t *TableView) {
+	if !d.split || t != d.currentPrimitive {
+		return
+	}
+	header, row := t.SelectedRow()
+	kind := t.resourceKind.Kind
+
+	d.lock.Lock()
+	d.generation++
+	generation := d.generation
+	d.lock.Unlock()
+
+	d.AppView.Go(func() {
+		time.Sleep(detailDelay)
+		d.lock.Lock()
+		stale := generation != d.generation
+		d.lock.Unlock()
+		if stale {
+			return
+		}
+
+		text := rowSummary(header, row)
+		if d.Detail != nil {
+			detail, err := d.Detail(kind, header, row)
+			if err != nil {
+				text = fmt.Sprintf("[red]%s[white]\n\n%s", tview.Escape(err.Error()), text)
+			} else if detail != "" {
+				text = detail
+			}
+		}
+		d.Application.QueueUpdateDraw(func() {
+			d.lock.Lock()
+			defer d.lock.Unlock()
+			if generation != d.generation {
+				return
+			}
+			d.TextView.SetText(text).ScrollToBeginning()
+		})
+	})
+}
+
+// expandWidth is how wide ExpandRow lays out the row
+const expandWidth = 100
+
+// ExpandRow pops up every column of the selected row as header: value lines, scrolled off and cut cells in full
+func (t *TableView) ExpandRow() {
+	header, row := t.SelectedRow()
+	if len(row) == 0 {
+		return
+	}
+	title := t.resourceKind.Title
+	if col := headerColumn(header, "NAME"); col >= 0 && col < len(row) {
+		title = row[col]
+	}
+	t.app.showText(title, rowSummary(header, row), expandWidth, true)
+}
+
+// rowSummary lists the row as header: value lines, it's the fallback when the drawer has no detail for a kind
+func rowSummary(header, row []string) string {
+	b := &strings.Builder{}
+	for i, h := range header {
+		if i < len(row) {
+			fmt.Fprintf(b, "[aqua]%s:[white] %s\n", tview.Escape(h), tview.Escape(row[i]))
+		}
+	}
+	return b.String()
+}
+
+// toggleSplit shows or hides the detail pane next to the current table
+func (app *AppView) toggleSplit() {
+	app.split = !app.split
+	app.SwitchToRootPage()
+	if app.split {
+		app.detailView.show(app.currentPrimitive)
+	}
+}
+
+// splitLayout wraps a table with the detail pane when split mode is on
+func (app *AppView) splitLayout(p tview.Primitive) tview.Primitive {
+	if _, ok := p.(*TableView); !ok || !app.split {
+		return p
+	}
+	return tview.NewFlex().
+		AddItem(p, 0, 2, true).
+		AddItem(app.detailView.TextView, 0, 1, false)
+}
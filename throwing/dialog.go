@@ -0,0 +1,153 @@
+package throwing
+
+import (
+	"strings"
+
+	"github.com/gdamore/tcell"
+	"github.com/rivo/tview"
+)
+
+const (
+	dialogWidth     = 60
+	maxChoiceHeight = 20
+	maxTextHeight   = 30
+	promptHeight    = 3
+	cancelButton    = "Cancel"
+)
+
+/*
+showDialog lays dialog over the current page without adding it to the page history,
+the returned func puts the page back and gives the focus back to whatever had it before.
+A width of zero leaves the dialog the whole page, tview.Modal centers itself.
+*/
+func (app *AppView) showDialog(name string, dialog tview.Primitive, width, height int) func() {
+	previous := app.drawQueue.Last()
+	focus := app.GetFocus()
+
+	layer := dialog
+	if width > 0 {
+		layer = center(dialog, width, height)
+	}
+	pages := tview.NewPages()
+	pages.AddPage(previous.PageName, previous.Primitive, true, true).
+		AddPage(name, layer, true, true)
+	app.content.AddAndSwitchToPage(previous.PageName, pages, true)
+	app.SetFocus(dialog)
+
+	return func() {
+		app.content.AddAndSwitchToPage(previous.PageName, app.splitLayout(previous.Primitive), true)
+		app.SetFocus(focus)
+	}
+}
+
+/*
+showText lays text over the page in a box width wide that's as high as the wrapped text up to maxTextHeight
+and scrolls past that. With colors the text is read for color tags. Esc or Enter close it.
+*/
+func (app *AppView) showText(title, text string, width int, colors bool) {
+	view := tview.NewTextView().SetDynamicColors(colors).SetWrap(true).SetWordWrap(false)
+	view.SetText(text)
+	view.SetBorder(true)
+	view.SetTitle(title)
+	view.SetBackgroundColor(tcell.ColorBlack)
+	lines := 0
+	for _, line := range strings.Split(strings.TrimSuffix(text, "\n"), "\n") {
+		printed := len(line)
+		if colors {
+			printed = tview.TaggedStringWidth(line)
+		}
+		lines += printed/(width-2) + 1
+	}
+	height := lines + 2
+	if height > maxTextHeight {
+		height = maxTextHeight
+	}
+	closeDialog := app.showDialog("text", view, width, height)
+	view.SetDoneFunc(func(tcell.Key) {
+		closeDialog()
+	})
+}
+
+// Confirm asks before running do, the dialog goes away whichever button is picked
+func (app *AppView) Confirm(text, button string, do func()) {
+	modal := tview.NewModal().
+		SetText(text).
+		AddButtons([]string{button, cancelButton})
+	closeDialog := app.showDialog("confirm", modal, 0, 0)
+	modal.SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+		closeDialog()
+		if buttonLabel == button {
+			do()
+		}
+	})
+}
+
+// Prompt asks for one line of text starting from value, done isn't called when the prompt is escaped
+func (app *AppView) Prompt(title, label, value string, done func(text string)) {
+	app.prompt(title, label, value, done)
+}
+
+func (app *AppView) prompt(title, label, value string, done func(text string)) *tview.InputField {
+	input := tview.NewInputField().
+		SetLabel(label).
+		SetText(value).
+		SetFieldBackgroundColor(tcell.ColorBlack).
+		SetFieldTextColor(tcell.ColorBlue)
+	input.SetBorder(true)
+	input.SetTitle(title)
+	input.SetBackgroundColor(tcell.ColorBlack)
+	closeDialog := app.showDialog("prompt", input, dialogWidth, promptHeight)
+	input.SetDoneFunc(func(key tcell.Key) {
+		closeDialog()
+		if key == tcell.KeyEnter {
+			done(input.GetText())
+		}
+	})
+	return input
+}
+
+// Choose offers options in a list, done gets the index and the option picked and isn't called when the list is escaped
+func (app *AppView) Choose(title string, options []string, done func(index int, option string)) {
+	list := tview.NewList().ShowSecondaryText(false)
+	list.SetBorder(true)
+	list.SetTitle(title)
+	list.SetBackgroundColor(tcell.ColorBlack)
+	for _, option := range options {
+		list.AddItem(option, "", 0, nil)
+	}
+	height := len(options) + 2
+	if height > maxChoiceHeight {
+		height = maxChoiceHeight
+	}
+	closeDialog := app.showDialog("choose", list, dialogWidth, height)
+	list.SetSelectedFunc(func(index int, option, _ string, _ rune) {
+		closeDialog()
+		done(index, option)
+	})
+	list.SetDoneFunc(closeDialog)
+}
+
+// Confirm asks before running do on the app of t
+func (t *TableView) Confirm(text, button string, do func()) {
+	t.app.Confirm(text, button, do)
+}
+
+// ShowMessage lays text with color tags over the page of t, as wide as ExpandRow, Esc or Enter close it
+func (t *TableView) ShowMessage(title, text string) {
+	t.app.showText(title, text, expandWidth, true)
+}
+
+// Prompt asks for one line of text on the app of t
+func (t *TableView) Prompt(title, label, value string, done func(text string)) {
+	t.app.Prompt(title, label, value, done)
+}
+
+// PromptWithHistory asks for one line of text on the app of t, Up and Down walk history
+func (t *TableView) PromptWithHistory(title, label string, history *InputHistory, done func(text string)) {
+	t.app.PromptWithHistory(title, label, history, done)
+}
+
+// Choose offers options on the app of t
+func (t *TableView) Choose(title string, options []string, done func(index int, option string)) {
+	t.app.Choose(title, options, done)
+}
@@ -0,0 +1,74 @@
+/*
+Package throwing is a small framework for terminal apps built around tables of rows that refresh.
+
+An app describes its pages in a types.Drawer and hands it to NewAppView:
+
+	drawer := types.Drawer{
+		RootPage: "books",
+		ViewMap: map[string]types.View{
+			"books": {
+				Kind:    types.ResourceKind{Title: "Books", Kind: "books"},
+				Feeder:  datafeeder.NewDataFeeder(refreshBooks),
+				Actions: []types.Action{{Name: "open", Shortcut: "o", Description: "open the book"}},
+			},
+		},
+		PageNav: map[rune]string{'1': "books"},
+		Footers: []types.ResourceView{{Title: "Books", Kind: "books"}},
+	}
+	app := throwing.NewAppView(nil, drawer, handler)
+	if err := app.Init(); err != nil {
+		return err
+	}
+	return app.Run()
+
+The pieces are:
+
+	DataSource      datafeeder.DataSource feeds a table with a header and rows, datafeeder.NewDataFeeder
+	                builds one from a func writing tab separated lines. RowStyler colors rows,
+	                Ager rewrites cells like AGE every second while the table is on screen.
+	                datafeeder.Fake serves fixture rows, scripted updates and errors without a cluster,
+	                datafeeder.Remote reads JSON from an HTTP endpoint by polling or server push, gRPC isn't spoken yet.
+	                A DataSource implementing datafeeder.Watcher refreshes its tables when it changes.
+	TableView       a page showing one DataSource. EventHandler returns the input capture of a table,
+	                from there actions open nested tables (NewNestTableView), dialogs (InsertDialog)
+	                or read the selection (SelectedRow). SetStartPage opens another page than the root one on launch,
+	                OnLeave stops what feeds a page once it's left.
+	Search          ShowSearch filters the rows of a table on every keystroke and highlights the matches,
+	                a word matches NAME, status=Running, status!=Running and node~worker the named column
+	                of the header, words add up. Up and Down in the input go through the earlier searches of the view,
+	                NextMatch and PreviousMatch select the rows matching the last one without filtering.
+	Columns         wide tables scroll sideways with Left and Right while NAMESPACE and NAME stay put, cells wider
+	                than SetMaxCellWidth are cut with an ellipsis and ShowCell pops up the whole value,
+	                ExpandRow every column of the selected row.
+	Actions         types.Action describes a key for the menu, running it is up to the EventHandler.
+	                SetActions changes the menu of a table once it's built, e.g. when an access check answers.
+	Dialogs         Confirm, Prompt and Choose lay a dialog over the page and give the focus back when it closes,
+	                PromptWithHistory walks an InputHistory with Up and Down, ShowMessage reports a result.
+	Forms           ShowForm collects FormFields with defaults and validators like Required and IntBetween,
+	                ShowWizard spreads them over WizardSteps with Next, Back and a summary.
+	Tasks           RunTask runs a long action in the background behind a progress dialog that can cancel it.
+	Sharing         SetServeAddress serves the table on screen over HTTP as a reloading page, as JSON and as Markdown,
+	                Markdown renders a table as GitHub flavored Markdown to paste elsewhere.
+	Recording       SetRecordFile writes the frames and keys of a session to a file, Replay plays it back.
+	Profiling       SetProfileAddress serves pprof and the refresh and draw costs per view (ViewProfile).
+	Accessibility   SetPlain drops colors and box drawing and reads the selected row out as one line,
+	                SetASCII draws with ASCII only for terminals without UTF-8, see UnicodeLocale.
+	Notifications   Notify and NotifyWithTimeout show toasts in the status bar, Bell rings the terminal bell.
+	Events          Publish hands an Event to the Subscribers of its kind, every table subscribes to
+	                its own kind and refreshes on its events. Refresh publishes one for the table's kind.
+	                Hidden tables hold their refresh until they're shown again unless SetKeepWarm is on,
+	                Close stops a table for good. SetMaxRefreshes caps the tables refreshing at once.
+	Goroutines      Go runs a goroutine whose panic stops the app and restores the terminal instead of
+	                leaving it in raw mode, Run returns the panic. Recover does the same when deferred.
+	                Context is cancelled when the app stops, on quit or SIGTERM, and Run waits a moment
+	                for the goroutines started with Go to end with it.
+
+The clientset is optional, without one the Kubernetes version and the connection banner are left out.
+
+Everything exported here follows APIVersion: additions bump the minor version, anything that breaks
+an app built on the package bumps the major version.
+*/
+package throwing
+
+// APIVersion is the version of the exported API of the package
+const APIVersion = "2.22.0"
@@ -0,0 +1,62 @@
+package throwing
+
+import (
+	"time"
+
+	"github.com/rivo/tview"
+	"k8s.io/client-go/kubernetes"
+)
+
+/*
+GenericDrawer is what a blade gets to work with inside its event handlers.
+TableView is the only implementation, blades should prefer this interface over reaching into TableView.
+*/
+type GenericDrawer interface {
+	GetTable() *tview.Table
+	GetSelectionName() string
+	GetResourceKind() string
+	GetCurrentPage() string
+	GetCurrentPrimitive() tview.Primitive
+	GetClientSet() *kubernetes.Clientset
+	GetApplication() *tview.Application
+
+	SwitchPage(page string, draw tview.Primitive)
+	OnLeave(p tview.Primitive, leave func())
+	SwitchToRootPage()
+	InsertDialog(name string, page tview.Primitive, dialog tview.Primitive)
+	Confirm(text, button string, do func())
+	Prompt(title, label, value string, done func(text string))
+	PromptWithHistory(title, label string, history *InputHistory, done func(text string))
+	Choose(title string, options []string, done func(index int, option string))
+	ShowMessage(title, text string)
+	ShowForm(title, submit string, fields []FormField, done func(values FormValues) error)
+	ShowWizard(title string, steps []WizardStep, done func(values FormValues) error)
+	RunTask(title string, task Task)
+	Navigate(r rune)
+	RootPage()
+	BackPage()
+	LastPage()
+
+	Refresh()
+	RefreshManual()
+	Publish(e Event)
+	SelectedRow() ([]string, []string)
+	Markdown() string
+	ShowSearch()
+	ShowCell()
+	ExpandRow()
+	NextMatch()
+	PreviousMatch()
+	SetFollow(follow bool)
+	Following() bool
+
+	StateValue(key string) string
+	SetStateValue(key, value string)
+
+	Notify(message string, severity Severity)
+	Bell()
+	NotifyWithTimeout(message string, severity Severity, timeout time.Duration)
+	UpdateStatus(status string, isError bool) tview.Primitive
+}
+
+var _ GenericDrawer = &TableView{}
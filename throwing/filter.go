@@ -0,0 +1,87 @@
+package throwing
+
+import (
+	"strings"
+
+	"github.com/rancher/axe/throwing/datafeeder"
+)
+
+/*
+rowFilter is what was typed into the search, split into terms that all have to match:
+
+	web                   NAME contains web
+	status=Running        the STATUS column is Running, case aside
+	status!=Running       it isn't
+	node~worker           the NODE column contains worker
+	last_seen~m           headers with spaces are written with _ or -
+
+A term naming a column the table doesn't have matches no row.
+*/
+type rowFilter []filterTerm
+
+type filterTerm struct {
+	column string
+	op     string
+	value  string
+}
+
+const (
+	filterContains = "~"
+	filterEquals   = "="
+	filterNot      = "!="
+)
+
+func parseFilter(text string) rowFilter {
+	var filter rowFilter
+	for _, word := range strings.Fields(text) {
+		term := filterTerm{op: filterContains, value: word}
+		for _, op := range []string{filterNot, filterEquals, filterContains} {
+			if i := strings.Index(word, op); i > 0 {
+				term = filterTerm{column: word[:i], op: op, value: word[i+len(op):]}
+				break
+			}
+		}
+		filter = append(filter, term)
+	}
+	return filter
+}
+
+// matches reports whether row passes every term, plain terms look at nameCol
+func (f rowFilter) matches(header, row datafeeder.Row, nameCol int) bool {
+	for _, term := range f {
+		col := nameCol
+		if term.column != "" {
+			col = headerColumn(header, term.column)
+		}
+		if col < 0 || col >= len(row) {
+			return false
+		}
+		value, want := strings.ToLower(row[col]), strings.ToLower(term.value)
+		switch term.op {
+		case filterEquals:
+			if value != want {
+				return false
+			}
+		case filterNot:
+			if value == want {
+				return false
+			}
+		default:
+			if !strings.Contains(value, want) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// headerColumn finds a column by a name typed in a filter, -1 when there's none
+func headerColumn(header datafeeder.Row, name string) int {
+	name = strings.NewReplacer("_", " ", "-", " ").Replace(name)
+	for i, h := range header {
+		if strings.EqualFold(h, name) {
+			return i
+		}
+	}
+	return -1
+}
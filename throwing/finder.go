@@ -0,0 +1,210 @@
+package throwing
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/gdamore/tcell"
+	"github.com/rivo/tview"
+)
+
+const maxFinderResults = 100
+
+type finderEntry struct {
+	kind, namespace, name string
+	score                 int
+}
+
+// fuzzyMatch reports whether all of pattern appears in s in order, scoring consecutive and word start matches higher
+func fuzzyMatch(pattern, s string) (int, bool) {
+	pattern, s = strings.ToLower(pattern), strings.ToLower(s)
+	score, last := 0, -1
+	runes := []rune(s)
+	i := 0
+	for _, p := range pattern {
+		found := false
+		for ; i < len(runes); i++ {
+			if runes[i] != p {
+				continue
+			}
+			score++
+			if i == last+1 {
+				score += 2
+			}
+			if i == 0 || !unicode.IsLetter(runes[i-1]) && !unicode.IsDigit(runes[i-1]) {
+				score += 3
+			}
+			last = i
+			i++
+			found = true
+			break
+		}
+		if !found {
+			return 0, false
+		}
+	}
+	// prefer shorter names when the pattern matches equally well
+	return score*100 - len(runes), true
+}
+
+// nameColumns finds the NAME and NAMESPACE columns, namespace is -1 for cluster scoped tables
+func nameColumns(header []string) (int, int) {
+	nameCol, namespaceCol := 0, -1
+	for i, h := range header {
+		switch h {
+		case "NAME":
+			nameCol = i
+		case "NAMESPACE":
+			namespaceCol = i
+		}
+	}
+	return nameCol, namespaceCol
+}
+
+// finderEntries collects the name of every row of every table loaded so far
+func (app *AppView) finderEntries() []finderEntry {
+	var entries []finderEntry
+	for kind, t := range app.tableViews {
+		t.lock.Lock()
+		header := t.dataSource.Header()
+		data := t.dataSource.Data()
+		t.lock.Unlock()
+
+		nameCol, namespaceCol := nameColumns(header)
+		for _, row := range data {
+			if len(row) <= nameCol || row[nameCol] == "" {
+				continue
+			}
+			e := finderEntry{kind: kind, name: row[nameCol]}
+			if namespaceCol >= 0 && len(row) > namespaceCol {
+				e.namespace = row[namespaceCol]
+			}
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+func filterFinderEntries(entries []finderEntry, pattern string) []finderEntry {
+	var matches []finderEntry
+	for _, e := range entries {
+		target := e.name
+		if e.namespace != "" {
+			target = e.namespace + "/" + e.name
+		}
+		score, ok := fuzzyMatch(pattern, target)
+		if !ok {
+			continue
+		}
+		e.score = score
+		matches = append(matches, e)
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+	if len(matches) > maxFinderResults {
+		matches = matches[:maxFinderResults]
+	}
+	return matches
+}
+
+/*
+showFinder opens the global finder over the current page.
+
+Typing filters the names of all loaded tables, Down moves to the results and Enter jumps to the selected one.
+*/
+func (app *AppView) showFinder() {
+	entries := app.finderEntries()
+	var matches []finderEntry
+
+	results := tview.NewTable()
+	results.SetSelectable(true, false)
+	results.SetBackgroundColor(tcell.ColorBlack)
+
+	render := func(pattern string) {
+		results.Clear()
+		matches = filterFinderEntries(entries, pattern)
+		for i, m := range matches {
+			results.SetCell(i, 0, tview.NewTableCell(m.kind).SetTextColor(tcell.ColorPurple))
+			results.SetCell(i, 1, tview.NewTableCell(m.namespace).SetTextColor(tcell.ColorAntiqueWhite))
+			results.SetCell(i, 2, tview.NewTableCell(m.name).SetTextColor(tcell.ColorAntiqueWhite).SetExpansion(1))
+		}
+		results.Select(0, 0).ScrollToBeginning()
+	}
+
+	jump := func() {
+		row, _ := results.GetSelection()
+		if row < 0 || row >= len(matches) {
+			return
+		}
+		app.jumpTo(matches[row])
+	}
+
+	input := tview.NewInputField()
+	input.SetLabel("> ")
+	input.SetFieldBackgroundColor(tcell.ColorBlack)
+	input.SetFieldTextColor(tcell.ColorBlue)
+	input.SetChangedFunc(render)
+	input.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEnter {
+			jump()
+		}
+	})
+	input.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyDown {
+			app.SetFocus(results)
+			return nil
+		}
+		return event
+	})
+	results.SetSelectedFunc(func(row, column int) {
+		jump()
+	})
+	render("")
+
+	finder := tview.NewFlex().SetDirection(tview.FlexRow)
+	finder.SetBorder(true)
+	finder.SetTitle("Find")
+	finder.SetBackgroundColor(tcell.ColorBlack)
+	finder.AddItem(input, 1, 1, true)
+	finder.AddItem(results, 0, 1, false)
+
+	newpage := tview.NewPages()
+	if t, ok := app.tableViews[app.currentPage]; ok {
+		newpage.AddPage("handler", t, true, true)
+	}
+	newpage.AddPage("finder", center(finder, 80, 20), true, true)
+	app.SwitchPage(app.currentPage, newpage, nil)
+	app.SetFocus(input)
+}
+
+// jumpTo switches to the table holding e and selects its row
+func (app *AppView) jumpTo(e finderEntry) {
+	t, ok := app.tableViews[e.kind]
+	if !ok {
+		return
+	}
+	app.footerView.TextView.Highlight(e.kind).ScrollToHighlight()
+	app.SwitchPage(e.kind, t, t.actions)
+
+	t.SelectRow(e.namespace, e.name)
+}
+
+// SelectRow moves the selection to the row of the named object, it reports whether the row was found
+func (t *TableView) SelectRow(namespace, name string) bool {
+	t.lock.Lock()
+	nameCol, namespaceCol := nameColumns(t.dataSource.Header())
+	t.lock.Unlock()
+	for row := 1; row < t.Table.GetRowCount(); row++ {
+		if t.Table.GetCell(row, nameCol).Text != name {
+			continue
+		}
+		if namespaceCol >= 0 && t.Table.GetCell(row, namespaceCol).Text != namespace {
+			continue
+		}
+		t.Table.Select(row, 0)
+		return true
+	}
+	return false
+}
@@ -0,0 +1,181 @@
+package throwing
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell"
+	"github.com/rivo/tview"
+)
+
+const (
+	formWidth      = 80
+	maxFormHeight  = 30
+	fieldWidth     = 40
+	formButtonRows = 3
+)
+
+/*
+FormField describes one input of a form shown with ShowForm.
+Name keys the value in FormValues and defaults to Label, Options turns the field into a dropdown
+and Checkbox into a checkbox, checked when Default is "true".
+*/
+type FormField struct {
+	Name     string
+	Label    string
+	Default  string
+	Options  []string
+	Checkbox bool
+	Width    int
+	Numeric  bool
+	Validate func(value string) error
+}
+
+func (f FormField) name() string {
+	if f.Name == "" {
+		return f.Label
+	}
+	return f.Name
+}
+
+// FormValues are the values of a submitted form keyed by field name
+type FormValues map[string]string
+
+// Int reads a field as a number, fields marked Numeric always parse
+func (v FormValues) Int(name string) int {
+	i, _ := strconv.Atoi(v[name])
+	return i
+}
+
+// Bool reads a checkbox
+func (v FormValues) Bool(name string) bool {
+	b, _ := strconv.ParseBool(v[name])
+	return b
+}
+
+// Required refuses empty values
+func Required(value string) error {
+	if strings.TrimSpace(value) == "" {
+		return fmt.Errorf("can't be empty")
+	}
+	return nil
+}
+
+// IntBetween refuses values that aren't numbers from min to max
+func IntBetween(min, max int) func(value string) error {
+	return func(value string) error {
+		i, err := strconv.Atoi(value)
+		if err != nil || i < min || i > max {
+			return fmt.Errorf("must be a number from %d to %d", min, max)
+		}
+		return nil
+	}
+}
+
+func acceptNumber(text string, last rune) bool {
+	_, err := strconv.Atoi(text)
+	return err == nil || text == "-"
+}
+
+// addFields adds the fields to form, the returned func validates them and collects their values
+func addFields(form *tview.Form, fields []FormField) func() (FormValues, error) {
+	values := make([]func() string, len(fields))
+	for i, f := range fields {
+		width := f.Width
+		if width == 0 {
+			width = fieldWidth
+		}
+		if len(f.Options) > 0 {
+			dropdown := tview.NewDropDown().SetLabel(f.Label).SetOptions(f.Options, nil)
+			for j, option := range f.Options {
+				if option == f.Default {
+					dropdown.SetCurrentOption(j)
+				}
+			}
+			values[i] = func() string {
+				_, option := dropdown.GetCurrentOption()
+				return option
+			}
+			form.AddFormItem(dropdown)
+			continue
+		}
+		if f.Checkbox {
+			checked, _ := strconv.ParseBool(f.Default)
+			checkbox := tview.NewCheckbox().SetLabel(f.Label).SetChecked(checked)
+			values[i] = func() string {
+				return strconv.FormatBool(checkbox.IsChecked())
+			}
+			form.AddFormItem(checkbox)
+			continue
+		}
+		input := tview.NewInputField().SetLabel(f.Label).SetText(f.Default).SetFieldWidth(width)
+		if f.Numeric {
+			input.SetAcceptanceFunc(acceptNumber)
+		}
+		values[i] = input.GetText
+		form.AddFormItem(input)
+	}
+
+	return func() (FormValues, error) {
+		collected := FormValues{}
+		for i, f := range fields {
+			value := values[i]()
+			if f.Validate != nil {
+				if err := f.Validate(value); err != nil {
+					return nil, fmt.Errorf("%s %v", f.Label, err)
+				}
+			}
+			collected[f.name()] = value
+		}
+		return collected, nil
+	}
+}
+
+func newForm(title string) *tview.Form {
+	form := tview.NewForm()
+	form.SetBorder(true)
+	form.SetTitle(title)
+	form.SetBackgroundColor(tcell.ColorBlack)
+	form.SetFieldBackgroundColor(tcell.ColorGray)
+	return form
+}
+
+func formHeight(fields int) int {
+	height := 2*fields + formButtonRows + 2
+	if height > maxFormHeight {
+		height = maxFormHeight
+	}
+	return height
+}
+
+/*
+ShowForm collects the fields in a dialog over the current page.
+Every field is validated on submit, the first failure is notified.
+done runs with the values once they're valid, an error it returns is notified and keeps the form open.
+Cancel and Escape close the form without calling done.
+*/
+func (app *AppView) ShowForm(title, submit string, fields []FormField, done func(values FormValues) error) {
+	form := newForm(title)
+	collect := addFields(form, fields)
+	closeDialog := app.showDialog("form", form, formWidth, formHeight(len(fields)))
+
+	form.AddButton(submit, func() {
+		values, err := collect()
+		if err == nil {
+			err = done(values)
+		}
+		if err != nil {
+			app.Notify(err.Error(), SeverityError)
+			return
+		}
+		closeDialog()
+	})
+	form.AddButton(cancelButton, closeDialog)
+	form.SetCancelFunc(closeDialog)
+}
+
+// ShowForm collects the fields in a dialog on the app of t
+func (t *TableView) ShowForm(title, submit string, fields []FormField, done func(values FormValues) error) {
+	t.app.ShowForm(title, submit, fields, done)
+}
@@ -2,12 +2,58 @@ package throwing
 
 import (
 	"github.com/gdamore/tcell"
+	"github.com/rivo/tview"
+	"github.com/sirupsen/logrus"
 )
 
 var (
 	EscapeEventHandler = func(app *AppView) func(event *tcell.EventKey) *tcell.EventKey {
 		return func(event *tcell.EventKey) *tcell.EventKey {
-			if event.Key() == tcell.KeyEscape || event.Rune() == 'q' {
+			logrus.Debugf("key %s on page %s", event.Name(), app.currentPage)
+			// let input fields receive q as a plain character
+			if _, ok := app.GetFocus().(*tview.InputField); ok && event.Key() != tcell.KeyEscape {
+				return event
+			}
+			if app.keys[KeyFinder].Matches(event) {
+				app.showFinder()
+				return nil
+			}
+			if app.keys[KeySplit].Matches(event) {
+				app.toggleSplit()
+				return nil
+			}
+			if app.keys[KeyHistory].Matches(event) {
+				app.showHistory()
+				return nil
+			}
+			// tabs and pages are only switched from tables, other pages use Tab to move between their fields
+			if t, ok := app.GetFocus().(*TableView); ok {
+				switch {
+				case app.keys[KeyNextTab].Matches(event):
+					app.cycleTab(1)
+					return nil
+				case app.keys[KeyPreviousTab].Matches(event):
+					app.cycleTab(-1)
+					return nil
+				case app.keys[KeyNewTab].Matches(event):
+					app.newTab()
+					return nil
+				case app.keys[KeyCloseTab].Matches(event):
+					app.closeTab()
+					return nil
+				case app.keys[KeyNextPage].Matches(event):
+					t.cyclePage(1)
+					return nil
+				case app.keys[KeyPrevPage].Matches(event):
+					t.cyclePage(-1)
+					return nil
+				}
+				if kind, ok := app.pageFor(event); ok {
+					t.navigate(kind)
+					return nil
+				}
+			}
+			if app.keys[KeyBack].Matches(event) {
 				app.showMenu = false
 				app.SwitchPage(app.currentPage, app.tableViews[app.currentPage], app.tableViews[app.currentPage].actions)
 			}
@@ -15,17 +61,32 @@ var (
 		}
 	}
 
+	searchChangedEventHandler = func(app *AppView) func(text string) {
+		return func(text string) {
+			if t, ok := app.tableViews[app.currentPage]; ok {
+				t.liveSearch(text)
+			}
+		}
+	}
+
 	searchDoneEventHandler = func(app *AppView) func(key tcell.Key) {
 		return func(key tcell.Key) {
+			// the current page isn't a table when a blade switched to a page of its own
+			t, ok := app.tableViews[app.currentPage]
 			switch key {
 			case tcell.KeyEscape:
+				if ok {
+					t.endSearch("")
+				}
 				app.SetFocus(app.content)
 				app.searchView.InputField.SetText("")
 			case tcell.KeyEnter:
-				t := app.tableViews[app.currentPage]
-				t.UpdateWithSearch(app.searchView.InputField.GetText())
+				if ok {
+					app.searchHistory(t.resourceKind.Kind).Add(app.searchView.InputField.GetText())
+					t.endSearch(app.searchView.InputField.GetText())
+				}
+				app.SetFocus(app.content)
 				app.searchView.InputField.SetText("")
-				t.Refresh()
 			}
 		}
 	}
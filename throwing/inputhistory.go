@@ -0,0 +1,90 @@
+package throwing
+
+import (
+	"sync"
+
+	"github.com/gdamore/tcell"
+	"github.com/rivo/tview"
+)
+
+/*
+InputHistory keeps what was entered in a prompt, oldest first, for PromptWithHistory to walk with Up and Down.
+Entries repeating the newest one aren't added again, past the limit the oldest are dropped.
+*/
+type InputHistory struct {
+	lock    sync.Mutex
+	entries []string
+	limit   int
+}
+
+func NewInputHistory(limit int, entries ...string) *InputHistory {
+	h := &InputHistory{limit: limit}
+	for _, e := range entries {
+		h.Add(e)
+	}
+	return h
+}
+
+func (h *InputHistory) Add(entry string) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if entry == "" || len(h.entries) > 0 && h.entries[len(h.entries)-1] == entry {
+		return
+	}
+	h.entries = append(h.entries, entry)
+	if h.limit > 0 && len(h.entries) > h.limit {
+		h.entries = h.entries[len(h.entries)-h.limit:]
+	}
+}
+
+// Entries returns a copy of the entries, oldest first
+func (h *InputHistory) Entries() []string {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return append([]string(nil), h.entries...)
+}
+
+/*
+PromptWithHistory is Prompt with a shell like history: Up goes back through the entries,
+Down forward again and past the newest to what was typed. Entered text is added to history.
+*/
+func (app *AppView) PromptWithHistory(title, label string, history *InputHistory, done func(text string)) {
+	input := app.prompt(title, label, "", func(text string) {
+		history.Add(text)
+		done(text)
+	})
+	walkHistory(input, history)
+}
+
+// walkHistory has Up and Down in input go through the entries history has now
+func walkHistory(input *tview.InputField, history *InputHistory) {
+	entries := history.Entries()
+	// position len(entries) is the line being typed, kept aside while walking the entries
+	position, typed := len(entries), ""
+	input.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyUp:
+			if position == 0 {
+				return nil
+			}
+			if position == len(entries) {
+				typed = input.GetText()
+			}
+			position--
+			input.SetText(entries[position])
+		case tcell.KeyDown:
+			if position == len(entries) {
+				return nil
+			}
+			position++
+			if position == len(entries) {
+				input.SetText(typed)
+			} else {
+				input.SetText(entries[position])
+			}
+		default:
+			return event
+		}
+		return nil
+	})
+}
@@ -0,0 +1,242 @@
+package k8s
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rancher/axe/throwing"
+	"github.com/rancher/axe/throwing/types"
+	"github.com/sirupsen/logrus"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const accessCacheTTL = 30 * time.Second
+
+type accessResult struct {
+	allowed bool
+	reason  string
+	checked time.Time
+}
+
+var (
+	accessCache     = map[authorizationv1.ResourceAttributes]accessResult{}
+	accessCacheLock sync.Mutex
+)
+
+// splitKind turns a group qualified kind like cronjobs.batch into resource and group
+func splitKind(kind string) (string, string) {
+	parts := strings.SplitN(kind, ".", 2)
+	if len(parts) == 1 {
+		return parts[0], ""
+	}
+	return parts[0], parts[1]
+}
+
+/*
+canI runs a SelfSubjectAccessReview for verb on kind in namespace.
+A denied review is reported in the status bar, a failing review lets the action through so kubectl can report the real error.
+Only reads pass in read-only mode.
+*/
+func canI(t *throwing.TableView, verb, kind, subresource, namespace string) bool {
+	if deniedReadOnly(t, verb) {
+		return false
+	}
+	resource, group := splitKind(kind)
+	attributes := authorizationv1.ResourceAttributes{
+		Namespace:   namespace,
+		Verb:        verb,
+		Group:       group,
+		Resource:    resource,
+		Subresource: subresource,
+	}
+
+	result, err := review(t.GetClientSet(), attributes)
+	if err != nil {
+		logrus.Debugf("access review for %s %s failed: %v", verb, kind, err)
+		return true
+	}
+	if !result.allowed {
+		t.Notify(deniedMessage(attributes, result.reason), throwing.SeverityWarning)
+	}
+	return result.allowed
+}
+
+// review runs a SelfSubjectAccessReview for attributes, the answer is cached for accessCacheTTL
+func review(client kubernetes.Interface, attributes authorizationv1.ResourceAttributes) (accessResult, error) {
+	accessCacheLock.Lock()
+	result, ok := accessCache[attributes]
+	accessCacheLock.Unlock()
+	if ok && time.Since(result.checked) <= accessCacheTTL {
+		return result, nil
+	}
+
+	answer, err := client.AuthorizationV1().SelfSubjectAccessReviews().Create(&authorizationv1.SelfSubjectAccessReview{
+		Spec: authorizationv1.SelfSubjectAccessReviewSpec{
+			ResourceAttributes: &attributes,
+		},
+	})
+	if err != nil {
+		return accessResult{}, err
+	}
+	result = accessResult{
+		allowed: answer.Status.Allowed,
+		reason:  answer.Status.Reason,
+		checked: time.Now(),
+	}
+	accessCacheLock.Lock()
+	accessCache[attributes] = result
+	accessCacheLock.Unlock()
+	return result, nil
+}
+
+// actionCheck is the access an action needs, an empty kind is the kind of the table it runs on
+type actionCheck struct {
+	verb, kind, subresource string
+	// clusterWide actions are checked without a namespace
+	clusterWide bool
+}
+
+// actionAccess are the checks of the actions that write, an action without one is always offered
+var actionAccess = map[string]actionCheck{
+	"edit":      {verb: "patch"},
+	"patch":     {verb: "patch"},
+	"set image": {verb: "patch"},
+	"delete":    {verb: "delete"},
+	"exec":      {verb: "create", kind: "pods", subresource: "exec"},
+	"command":   {verb: "create", kind: "pods", subresource: "exec"},
+	"ssh":       {verb: "create", kind: "pods", subresource: "exec"},
+	"curl":      {verb: "create", kind: "pods", subresource: "portforward"},
+	"trigger":   {verb: "create", kind: "jobs.batch"},
+	"rerun":     {verb: "create", kind: "jobs.batch"},
+	"bounds":    {verb: "patch", kind: hpaKind},
+	"renew":     {verb: "update", kind: certManagerKind, subresource: "status"},
+	"rollback":  {verb: "create", kind: "secrets"},
+	"uninstall": {verb: "delete", kind: "secrets"},
+	"taint":     {verb: "patch", kind: "nodes", clusterWide: true},
+	"drain":     {verb: "create", kind: "pods", subresource: "eviction", clusterWide: true},
+}
+
+// checkMenus turns on the access checks of the menus once Start knows the cluster, the menus built before offer everything
+var checkMenus bool
+
+var (
+	// menuApp rebuilds the menus the access reviews answered for, menuViews are the views of the drawer it draws
+	menuApp   *throwing.AppView
+	menuViews map[string]types.View
+	// menuReviews are the reviews of the menus under way, menuKinds the kinds of the menus waiting for a rebuild
+	menuReviews = map[authorizationv1.ResourceAttributes]bool{}
+	menuKinds   = map[string]bool{}
+)
+
+/*
+permitted reports whether the menu of kind offers a, the access is checked in the namespace the table lists.
+A table of all namespaces is checked in the current one, a user allowed there sees the action and canI has the last word.
+It only reads the answers cached by review and is called on the UI goroutine, an action without an answer yet is offered
+while reviewMenu asks in the background. A failing review offers the action, like canI does.
+*/
+func permitted(kind string, a kindAction) bool {
+	check, ok := actionAccess[a.Name]
+	if !checkMenus || !ok {
+		return true
+	}
+	if check.kind == "" {
+		check.kind = kind
+	}
+	namespace := ""
+	if !check.clusterWide {
+		if namespace = scopedNamespace(kind); namespace == "" {
+			namespace = currentNamespace()
+		}
+	}
+	resource, group := splitKind(check.kind)
+	attributes := authorizationv1.ResourceAttributes{
+		Namespace:   namespace,
+		Verb:        check.verb,
+		Group:       group,
+		Resource:    resource,
+		Subresource: check.subresource,
+	}
+
+	accessCacheLock.Lock()
+	result, ok := accessCache[attributes]
+	accessCacheLock.Unlock()
+	offered := !ok || result.allowed
+	if !ok || time.Since(result.checked) > accessCacheTTL {
+		reviewMenu(kind, a.Name, attributes, offered)
+	}
+	return offered
+}
+
+// reviewMenu runs the review of attributes off the UI goroutine, the menu of kind is rebuilt when the answer differs from offered
+func reviewMenu(kind, action string, attributes authorizationv1.ResourceAttributes, offered bool) {
+	accessCacheLock.Lock()
+	defer accessCacheLock.Unlock()
+	if menuApp == nil || menuReviews[attributes] {
+		return
+	}
+	menuReviews[attributes] = true
+
+	menuApp.Go(func() {
+		defer func() {
+			accessCacheLock.Lock()
+			delete(menuReviews, attributes)
+			accessCacheLock.Unlock()
+		}()
+		clientset, _, err := cachedClients()
+		if err != nil {
+			return
+		}
+		result, err := review(clientset, attributes)
+		if err != nil {
+			logrus.Debugf("access review for the %s action on %s failed: %v", action, kind, err)
+			return
+		}
+		if result.allowed != offered {
+			rebuildMenu(kind)
+		}
+	})
+}
+
+// rebuildMenu has the menu of kind built again from the cached answers, the answers of a burst share one rebuild
+func rebuildMenu(kind string) {
+	accessCacheLock.Lock()
+	queued := len(menuKinds) > 0
+	menuKinds[kind] = true
+	accessCacheLock.Unlock()
+	if queued {
+		return
+	}
+
+	menuApp.QueueUpdateDraw(func() {
+		accessCacheLock.Lock()
+		kinds := menuKinds
+		menuKinds = map[string]bool{}
+		accessCacheLock.Unlock()
+		for kind := range kinds {
+			actions := actionsForKind(kind)
+			if view, ok := menuViews[kind]; ok && kind != k8sKind {
+				view.Actions = actions
+				menuViews[kind] = view
+			}
+			menuApp.SetActions(kind, actions)
+		}
+	})
+}
+
+func deniedMessage(attributes authorizationv1.ResourceAttributes, reason string) string {
+	resource := attributes.Resource
+	if attributes.Subresource != "" {
+		resource += "/" + attributes.Subresource
+	}
+	msg := fmt.Sprintf("you are not allowed to %s %s", attributes.Verb, resource)
+	if attributes.Namespace != "" {
+		msg += " in namespace " + attributes.Namespace
+	}
+	if reason != "" {
+		msg += ": " + reason
+	}
+	return msg
+}
@@ -0,0 +1,74 @@
+package k8s
+
+import (
+	"sync"
+	"time"
+
+	"github.com/rancher/axe/throwing/datafeeder"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+// cellTimes are the timestamps behind the clock derived cells of one object
+type cellTimes struct {
+	created time.Time
+	// started is the start of a job still running, zero once it completed
+	started time.Time
+}
+
+// objectTimes keeps the cellTimes of the last list of every kind, keyed by namespace/name
+var objectTimes = struct {
+	sync.Mutex
+	byKind map[string]map[string]cellTimes
+}{
+	byKind: map[string]map[string]cellTimes{},
+}
+
+func recordTimes(kind string, times map[string]cellTimes) {
+	objectTimes.Lock()
+	defer objectTimes.Unlock()
+	objectTimes.byKind[kind] = times
+}
+
+// timesOf reads the timestamps of a listed object, the start only counts while a job hasn't completed
+func timesOf(obj *unstructured.Unstructured) cellTimes {
+	times := cellTimes{created: obj.GetCreationTimestamp().Time}
+	if _, completed, _ := unstructured.NestedString(obj.Object, "status", "completionTime"); completed {
+		return times
+	}
+	if started, ok, _ := unstructured.NestedString(obj.Object, "status", "startTime"); ok {
+		times.started, _ = time.Parse(time.RFC3339, started)
+	}
+	return times
+}
+
+// ageForKind ages AGE from the creation time and DURATION of running jobs from their start, between refreshes of kind
+func ageForKind(kind string) datafeeder.AgeFunc {
+	return func(header, row datafeeder.Row, col int) (string, bool) {
+		if header[col] != "AGE" && header[col] != "DURATION" {
+			return "", false
+		}
+		namespace, name := listedNamespace(kind), ""
+		for i, h := range header {
+			switch {
+			case i >= len(row):
+			case h == "NAME":
+				name = row[i]
+			case h == "NAMESPACE":
+				namespace = row[i]
+			}
+		}
+
+		objectTimes.Lock()
+		times, ok := objectTimes.byKind[kind][namespace+"/"+name]
+		objectTimes.Unlock()
+		since := times.created
+		if header[col] == "DURATION" {
+			since = times.started
+		}
+		if !ok || since.IsZero() {
+			return "", false
+		}
+		return duration.HumanDuration(time.Since(since)), true
+	}
+}
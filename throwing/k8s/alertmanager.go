@@ -0,0 +1,297 @@
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gdamore/tcell"
+	"github.com/rancher/axe/throwing"
+	"github.com/rancher/axe/throwing/datafeeder"
+)
+
+const (
+	alertmanagerKind    = "alertmanager"
+	alertmanagerTimeout = 10 * time.Second
+	defaultSilence      = "2h"
+)
+
+// alertmanagerURL is alertmanager from the config, e.g. http://alertmanager.monitoring:9093
+var alertmanagerURL string
+
+var alertmanagerClient = &http.Client{Timeout: alertmanagerTimeout}
+
+/*
+alertObjectLabels are the labels kube-state-metrics and the kubernetes mixin put on alerts, with the kind they name.
+The first one an alert has decides the row, an alert with only a namespace refers to the namespace.
+*/
+var alertObjectLabels = []struct{ label, kind string }{
+	{"pod", "pods"},
+	{"deployment", "deployments.apps"},
+	{"statefulset", "statefulsets.apps"},
+	{"daemonset", "daemonsets.apps"},
+	{"job_name", "jobs.batch"},
+	{"cronjob", "cronjobs.batch"},
+	{"horizontalpodautoscaler", "horizontalpodautoscalers.autoscaling"},
+	{"persistentvolumeclaim", "persistentvolumeclaims"},
+	{"persistentvolume", "persistentvolumes"},
+	{"service", "services"},
+	{"node", "nodes"},
+}
+
+// firingAlert is an alert of the v2 API, state is active, suppressed or unprocessed
+type firingAlert struct {
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+	StartsAt    time.Time         `json:"startsAt"`
+	Fingerprint string            `json:"fingerprint"`
+	Status      struct {
+		State       string   `json:"state"`
+		SilencedBy  []string `json:"silencedBy"`
+		InhibitedBy []string `json:"inhibitedBy"`
+	} `json:"status"`
+}
+
+// object is the Kubernetes object the labels of the alert point at, kind is empty when they don't name one
+func (a firingAlert) object() (namespace, name, kind string) {
+	namespace = a.Labels["namespace"]
+	for _, l := range alertObjectLabels {
+		if value := a.Labels[l.label]; value != "" {
+			if l.kind == "nodes" || l.kind == "persistentvolumes" {
+				namespace = ""
+			}
+			return namespace, value, l.kind
+		}
+	}
+	if namespace != "" {
+		return "", namespace, "namespaces"
+	}
+	return "", "", ""
+}
+
+// otherLabels are the labels the other columns don't show already, sorted
+func (a firingAlert) otherLabels() string {
+	shown := map[string]bool{"alertname": true, "severity": true, "namespace": true}
+	for _, l := range alertObjectLabels {
+		shown[l.label] = true
+	}
+	var labels []string
+	for k, v := range a.Labels {
+		if !shown[k] {
+			labels = append(labels, k+"="+v)
+		}
+	}
+	sort.Strings(labels)
+	return strings.Join(labels, ",")
+}
+
+func (a firingAlert) summary() string {
+	for _, key := range []string{"summary", "message", "description"} {
+		if text := a.Annotations[key]; text != "" {
+			return strings.Join(strings.Fields(text), " ")
+		}
+	}
+	return ""
+}
+
+// row is the alert on the alertmanager page, the actions find the alert of a row by its first six cells
+func (a firingAlert) row() []string {
+	namespace, name, kind := a.object()
+	return []string{namespace, name, kind, a.Labels["alertname"], a.Labels["severity"], a.otherLabels()}
+}
+
+// firingAlerts are the alerts of the last refresh, for the actions to find their labels and silences
+var firingAlerts = struct {
+	sync.Mutex
+	list []firingAlert
+}{}
+
+// alertmanagerDo sends a request to the v2 API, the JSON answer is decoded into out unless it's nil
+func alertmanagerDo(ctx context.Context, method, path string, in, out interface{}) error {
+	if alertmanagerURL == "" {
+		return fmt.Errorf("no Alertmanager, set alertmanager in the config")
+	}
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, strings.TrimSuffix(alertmanagerURL, "/")+"/api/v2"+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := alertmanagerClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		message := &bytes.Buffer{}
+		io.Copy(message, io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("alertmanager answered %s: %s", resp.Status, strings.TrimSpace(message.String()))
+	}
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+/*
+RefreshAlertmanager lists the alerts Alertmanager has, silenced and inhibited ones as well, the most severe first.
+NAMESPACE, NAME and KIND come from the labels so Enter opens the object an alert is about.
+*/
+func RefreshAlertmanager(b *bytes.Buffer) error {
+	ctx, cancel := context.WithTimeout(context.Background(), alertmanagerTimeout)
+	defer cancel()
+	var list []firingAlert
+	if err := alertmanagerDo(ctx, http.MethodGet, "/alerts", nil, &list); err != nil {
+		return err
+	}
+	rank := map[string]int{"critical": 0, "error": 1, "warning": 2}
+	severity := func(a firingAlert) int {
+		if r, ok := rank[a.Labels["severity"]]; ok {
+			return r
+		}
+		return len(rank)
+	}
+	sort.SliceStable(list, func(i, j int) bool {
+		if severity(list[i]) != severity(list[j]) {
+			return severity(list[i]) < severity(list[j])
+		}
+		return list[i].StartsAt.Before(list[j].StartsAt)
+	})
+	firingAlerts.Lock()
+	firingAlerts.list = list
+	firingAlerts.Unlock()
+
+	var rows [][]string
+	for _, a := range list {
+		rows = append(rows, append(a.row(), a.Status.State, a.StartsAt.Local().Format("2006-01-02 15:04"), a.summary()))
+	}
+	writeTable(b, []string{"NAMESPACE", "NAME", "KIND", "ALERT", "SEVERITY", "LABELS", "STATE", "SINCE", "SUMMARY"}, rows)
+	return nil
+}
+
+func alertmanagerRowColor(header, row datafeeder.Row) tcell.Color {
+	if columnOrEmpty(header, row, "STATE") == "suppressed" {
+		return tcell.ColorGray
+	}
+	switch columnOrEmpty(header, row, "SEVERITY") {
+	case "critical", "error":
+		return tcell.ColorRed
+	case "warning":
+		return tcell.ColorYellow
+	}
+	return tcell.ColorDefault
+}
+
+// selectedFiringAlert finds the alert of the selected row
+func selectedFiringAlert(t *throwing.TableView) (firingAlert, bool) {
+	_, row := t.SelectedRow()
+	firingAlerts.Lock()
+	defer firingAlerts.Unlock()
+	for _, a := range firingAlerts.list {
+		if len(row) >= len(a.row()) && strings.Join(a.row(), "\t") == strings.Join(row[:len(a.row())], "\t") {
+			return a, true
+		}
+	}
+	return firingAlert{}, false
+}
+
+type silenceMatcher struct {
+	Name    string `json:"name"`
+	Value   string `json:"value"`
+	IsRegex bool   `json:"isRegex"`
+	IsEqual bool   `json:"isEqual"`
+}
+
+type silence struct {
+	Matchers  []silenceMatcher `json:"matchers"`
+	StartsAt  time.Time        `json:"startsAt"`
+	EndsAt    time.Time        `json:"endsAt"`
+	CreatedBy string           `json:"createdBy"`
+	Comment   string           `json:"comment"`
+}
+
+// silenceAlert silences the selected alert by all its labels, so only alerts with the same labels are muted
+func silenceAlert(t *throwing.TableView) {
+	a, ok := selectedFiringAlert(t)
+	if !ok {
+		return
+	}
+	fields := []throwing.FormField{
+		{Name: "duration", Label: "for", Default: defaultSilence, Validate: func(value string) error {
+			d, err := time.ParseDuration(value)
+			if err == nil && d <= 0 {
+				err = fmt.Errorf("%s isn't in the future", value)
+			}
+			return err
+		}},
+		{Name: "comment", Label: "comment", Validate: throwing.Required},
+		{Name: "createdBy", Label: "created by", Default: os.Getenv("USER"), Validate: throwing.Required},
+	}
+	t.ShowForm(fmt.Sprintf("silence %s", a.Labels["alertname"]), "Silence", fields, func(values throwing.FormValues) error {
+		duration, err := time.ParseDuration(values["duration"])
+		if err != nil {
+			return err
+		}
+		s := silence{
+			StartsAt:  time.Now(),
+			EndsAt:    time.Now().Add(duration),
+			CreatedBy: values["createdBy"],
+			Comment:   values["comment"],
+		}
+		for k, v := range a.Labels {
+			s.Matchers = append(s.Matchers, silenceMatcher{Name: k, Value: v, IsEqual: true})
+		}
+		sort.Slice(s.Matchers, func(i, j int) bool { return s.Matchers[i].Name < s.Matchers[j].Name })
+
+		var created struct {
+			SilenceID string `json:"silenceID"`
+		}
+		ctx, cancel := context.WithTimeout(t.Context(), alertmanagerTimeout)
+		defer cancel()
+		if err := alertmanagerDo(ctx, http.MethodPost, "/silences", s, &created); err != nil {
+			return err
+		}
+		t.Notify(fmt.Sprintf("silenced %s for %s, silence %s", a.Labels["alertname"], duration, created.SilenceID), throwing.SeverityInfo)
+		t.Refresh()
+		return nil
+	})
+}
+
+// expireSilences ends the silences muting the selected alert
+func expireSilences(t *throwing.TableView) {
+	a, ok := selectedFiringAlert(t)
+	if !ok {
+		return
+	}
+	if len(a.Status.SilencedBy) == 0 {
+		t.Notify(fmt.Sprintf("%s isn't silenced", a.Labels["alertname"]), throwing.SeverityInfo)
+		return
+	}
+	t.Confirm(fmt.Sprintf("Do you want to expire the silences of %s?", a.Labels["alertname"]), "expire", func() {
+		ctx, cancel := context.WithTimeout(t.Context(), alertmanagerTimeout)
+		defer cancel()
+		for _, id := range a.Status.SilencedBy {
+			if err := alertmanagerDo(ctx, http.MethodDelete, "/silence/"+id, nil, nil); err != nil {
+				t.Notify(err.Error(), throwing.SeverityError)
+				return
+			}
+		}
+		t.Notify(fmt.Sprintf("%s isn't silenced anymore", a.Labels["alertname"]), throwing.SeverityInfo)
+		t.Refresh()
+	})
+}
@@ -0,0 +1,233 @@
+package k8s
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gdamore/tcell"
+	"github.com/rancher/axe/throwing"
+	"github.com/rancher/axe/throwing/datafeeder"
+	"github.com/sirupsen/logrus"
+	"k8s.io/client-go/discovery"
+)
+
+const (
+	alertsKind = "alerts"
+	// alertPeriod is how often the kinds the alert rules watch are listed
+	alertPeriod = 30 * time.Second
+)
+
+// alertRule is a rule of the alerts config, a row of kind whose column matches the regex raises an alert
+type alertRule struct {
+	Kind     string `json:"kind"`
+	Column   string `json:"column"`
+	Match    string `json:"match"`
+	Severity string `json:"severity,omitempty"`
+}
+
+// compiledRule is an alertRule with the kind resolved by discovery
+type compiledRule struct {
+	alertRule
+	kind     string
+	w        wrapper
+	match    *regexp.Regexp
+	severity throwing.Severity
+}
+
+type alert struct {
+	rule            *compiledRule
+	namespace, name string
+	value           string
+	severity        throwing.Severity
+	since           time.Time
+}
+
+var alertRules []*compiledRule
+
+// alerts holds the rows matching a rule in the last round, keyed by rule, namespace and name
+var alerts = struct {
+	sync.Mutex
+	active map[string]alert
+}{
+	active: map[string]alert{},
+}
+
+// compileAlertRules checks the rules of the config once at start, a rule that can't work fails the start
+func compileAlertRules(d discovery.DiscoveryInterface, rules []alertRule) ([]*compiledRule, error) {
+	var compiled []*compiledRule
+	for i, r := range rules {
+		if r.Kind == "" || r.Column == "" {
+			return nil, fmt.Errorf("alert rule %d: kind and column are required", i+1)
+		}
+		gvr, err := resolveKind(d, r.Kind)
+		if err != nil {
+			return nil, fmt.Errorf("alert rule %d: %v", i+1, err)
+		}
+		match, err := regexp.Compile(r.Match)
+		if err != nil {
+			return nil, fmt.Errorf("alert rule %d: %v", i+1, err)
+		}
+		severity, err := parseSeverity(r.Severity)
+		if err != nil {
+			return nil, fmt.Errorf("alert rule %d: %v", i+1, err)
+		}
+		compiled = append(compiled, &compiledRule{
+			alertRule: r,
+			kind:      kindOf(gvr),
+			w:         wrapper{group: gvr.Group, version: gvr.Version, name: gvr.Resource},
+			match:     match,
+			severity:  severity,
+		})
+	}
+	return compiled, nil
+}
+
+// parseSeverity reads the severity of a rule, warning when it's left out
+func parseSeverity(s string) (throwing.Severity, error) {
+	for _, severity := range []throwing.Severity{throwing.SeverityInfo, throwing.SeverityWarning, throwing.SeverityError} {
+		if strings.EqualFold(s, severity.String()) {
+			return severity, nil
+		}
+	}
+	if s == "" {
+		return throwing.SeverityWarning, nil
+	}
+	return throwing.SeverityInfo, fmt.Errorf("unknown severity %q, info, warning or error", s)
+}
+
+/*
+watchAlerts lists the kinds of the rules every alertPeriod for the lifetime of axe and checks their rows.
+A row starting to match rings the bell and shows up in the status bar, the alerts page lists what matches now.
+*/
+func watchAlerts(app *throwing.AppView, rules []*compiledRule) {
+	feeders := map[string]*datafeeder.DataFeeder{}
+	for _, r := range rules {
+		if _, ok := feeders[r.kind]; !ok {
+			feeders[r.kind] = datafeeder.NewDataFeeder(refresherForKind(r.w))
+		}
+	}
+	for {
+		checkAlerts(app, rules, feeders)
+		select {
+		case <-app.Context().Done():
+			return
+		case <-time.After(alertPeriod):
+		}
+	}
+}
+
+func checkAlerts(app *throwing.AppView, rules []*compiledRule, feeders map[string]*datafeeder.DataFeeder) {
+	failed := map[string]bool{}
+	for kind, feeder := range feeders {
+		if err := feeder.Refresh(); err != nil {
+			logrus.Debugf("alerts: listing %s: %v", kind, err)
+			failed[kind] = true
+		}
+	}
+
+	alerts.Lock()
+	defer alerts.Unlock()
+	active := map[string]alert{}
+	var raised []alert
+	for i, r := range rules {
+		if failed[r.kind] {
+			// a kind that couldn't be listed keeps its alerts until it can
+			for key, a := range alerts.active {
+				if a.rule == r {
+					active[key] = a
+				}
+			}
+			continue
+		}
+		feeder := feeders[r.kind]
+		header := feeder.Header()
+		for _, row := range feeder.Data() {
+			value, ok := column(header, row, strings.ToUpper(r.Column))
+			if !ok || !r.match.MatchString(value) {
+				continue
+			}
+			a := alert{
+				rule:      r,
+				namespace: columnOrEmpty(header, row, "NAMESPACE"),
+				name:      columnOrEmpty(header, row, "NAME"),
+				value:     value,
+				severity:  r.severity,
+				since:     time.Now(),
+			}
+			key := fmt.Sprintf("%d/%s/%s", i, a.namespace, a.name)
+			if old, ok := alerts.active[key]; ok {
+				a.since = old.since
+			} else {
+				raised = append(raised, a)
+			}
+			active[key] = a
+		}
+	}
+	alerts.active = active
+
+	switch len(raised) {
+	case 0:
+	case 1:
+		a := raised[0]
+		app.Bell()
+		app.Notify(fmt.Sprintf("alert: %s %s %s is %s", a.rule.kind, objectName(a.namespace, a.name), a.rule.Column, a.value), a.severity)
+	default:
+		severity := throwing.SeverityInfo
+		for _, a := range raised {
+			if a.severity > severity {
+				severity = a.severity
+			}
+		}
+		app.Bell()
+		app.Notify(fmt.Sprintf("%d new alerts, the alerts page lists them", len(raised)), severity)
+	}
+	app.Publish(throwing.Event{Kind: alertsKind, Type: throwing.EventRefresh})
+}
+
+func objectName(namespace, name string) string {
+	if namespace == "" {
+		return name
+	}
+	return namespace + "/" + name
+}
+
+// RefreshAlerts lists the rows matching a rule, the most severe and then the oldest first
+func RefreshAlerts(b *bytes.Buffer) error {
+	alerts.Lock()
+	list := make([]alert, 0, len(alerts.active))
+	for _, a := range alerts.active {
+		list = append(list, a)
+	}
+	alerts.Unlock()
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].severity != list[j].severity {
+			return list[i].severity > list[j].severity
+		}
+		if !list[i].since.Equal(list[j].since) {
+			return list[i].since.Before(list[j].since)
+		}
+		return objectName(list[i].namespace, list[i].name) < objectName(list[j].namespace, list[j].name)
+	})
+	var rows [][]string
+	for _, a := range list {
+		rows = append(rows, []string{a.namespace, a.name, a.rule.kind, a.severity.String(), strings.ToUpper(a.rule.Column), a.value, a.since.Format(time.RFC3339)})
+	}
+	writeTable(b, []string{"NAMESPACE", "NAME", "KIND", "SEVERITY", "COLUMN", "VALUE", "SINCE"}, rows)
+	return nil
+}
+
+func alertRowColor(header, row datafeeder.Row) tcell.Color {
+	switch columnOrEmpty(header, row, "SEVERITY") {
+	case throwing.SeverityError.String():
+		return tcell.ColorRed
+	case throwing.SeverityWarning.String():
+		return tcell.ColorYellow
+	}
+	return tcell.ColorDefault
+}
@@ -0,0 +1,88 @@
+package k8s
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rancher/axe/throwing"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+)
+
+// the goto action opens tables, which use the action lists, so it can't be part of their initializer
+func init() {
+	gotoAction := action("goto", ":", "open a kind by name, short name or alias", gotoKind)
+	rootActions = append(rootActions, gotoAction)
+	genericActions = append(genericActions, gotoAction)
+}
+
+// kindAliases are the user defined aliases from the config, they're looked up before discovery
+var kindAliases = map[string]string{}
+
+/*
+resolveKind turns what a user typed into a resource: a configured alias, a short name like po or deploy,
+the singular or plural resource name, the Kind, or any of those qualified with the group like deployments.apps.
+*/
+func resolveKind(d discovery.DiscoveryInterface, typed string) (schema.GroupVersionResource, error) {
+	typed = strings.ToLower(strings.TrimSpace(typed))
+	if alias, ok := kindAliases[typed]; ok {
+		typed = strings.ToLower(alias)
+	}
+	name, group := splitKind(typed)
+
+	// discovery fails as a whole when one aggregated API is down, the other lists are still usable
+	lists, err := d.ServerPreferredResources()
+	if len(lists) == 0 && err != nil {
+		return schema.GroupVersionResource{}, err
+	}
+	for _, list := range lists {
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil {
+			continue
+		}
+		if group != "" && gv.Group != group {
+			continue
+		}
+		for _, r := range list.APIResources {
+			// subresources like pods/log can't be listed
+			if strings.Contains(r.Name, "/") {
+				continue
+			}
+			names := append([]string{r.Name, r.SingularName, strings.ToLower(r.Kind)}, r.ShortNames...)
+			for _, n := range names {
+				if n == name {
+					return gv.WithResource(r.Name), nil
+				}
+			}
+		}
+	}
+	// a kind added since the documents on disk were written needs a fresh look
+	if cached, ok := d.(discovery.CachedDiscoveryInterface); ok && !cached.Fresh() {
+		cached.Invalidate()
+		return resolveKind(d, typed)
+	}
+	return schema.GroupVersionResource{}, fmt.Errorf("the server doesn't have a resource type %q", typed)
+}
+
+// kindOf is the group qualified name axe keys tables by
+func kindOf(gvr schema.GroupVersionResource) string {
+	return wrapper{group: gvr.Group, version: gvr.Version, name: gvr.Resource}.kind()
+}
+
+// gotoKind asks for a kind, aliases and short names included, and opens its table
+func gotoKind(t *throwing.TableView) {
+	t.Prompt("Go to", "kind ", "", func(text string) {
+		d, err := cachedDiscovery()
+		if err != nil {
+			t.Notify(err.Error(), throwing.SeverityError)
+			return
+		}
+		gvr, err := resolveKind(d, text)
+		if err != nil {
+			t.Notify(err.Error(), throwing.SeverityError)
+			return
+		}
+		w := wrapper{group: gvr.Group, version: gvr.Version, name: gvr.Resource}
+		openResourceTable(t, w, kindOf(gvr))
+	})
+}
@@ -0,0 +1,99 @@
+package k8s
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rancher/axe/throwing"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	bookmarksKind  = "bookmarks"
+	bookmarksState = "bookmarks"
+)
+
+type bookmark struct {
+	Kind      string    `json:"kind"`
+	Namespace string    `json:"namespace,omitempty"`
+	Name      string    `json:"name"`
+	Added     time.Time `json:"added"`
+}
+
+var bookmarks = struct {
+	sync.Mutex
+	list []bookmark
+}{}
+
+// restoreBookmarks loads the bookmarks saved with the session state
+func restoreBookmarks(saved string) {
+	if saved == "" {
+		return
+	}
+	bookmarks.Lock()
+	defer bookmarks.Unlock()
+	if err := json.Unmarshal([]byte(saved), &bookmarks.list); err != nil {
+		logrus.Debugf("ignoring saved bookmarks: %v", err)
+	}
+}
+
+// saveBookmarks has to be called with the bookmarks locked
+func saveBookmarks(t *throwing.TableView) {
+	if saved, err := json.Marshal(bookmarks.list); err == nil {
+		t.SetStateValue(bookmarksState, string(saved))
+	}
+}
+
+// toggleBookmark bookmarks the selected object, or removes the bookmark when it's already there
+func toggleBookmark(t *throwing.TableView) {
+	namespace, name := getNamespaceAndName(t)
+	b := bookmark{Kind: t.GetResourceKind(), Namespace: namespace, Name: name, Added: time.Now()}
+	if t.GetResourceKind() == bookmarksKind {
+		b.Kind = selectedColumn(t, "KIND")
+	}
+
+	bookmarks.Lock()
+	defer bookmarks.Unlock()
+	for i, o := range bookmarks.list {
+		if o.Kind == b.Kind && o.Namespace == b.Namespace && o.Name == b.Name {
+			bookmarks.list = append(bookmarks.list[:i], bookmarks.list[i+1:]...)
+			saveBookmarks(t)
+			t.Notify(fmt.Sprintf("removed bookmark %s %s", b.Kind, b.Name), throwing.SeverityInfo)
+			t.Refresh()
+			return
+		}
+	}
+	bookmarks.list = append(bookmarks.list, b)
+	saveBookmarks(t)
+	t.Notify(fmt.Sprintf("bookmarked %s %s", b.Kind, b.Name), throwing.SeverityInfo)
+}
+
+func RefreshBookmarks(b *bytes.Buffer) error {
+	bookmarks.Lock()
+	var rows [][]string
+	for _, bm := range bookmarks.list {
+		rows = append(rows, []string{bm.Namespace, bm.Name, bm.Kind, bm.Added.Format(time.RFC3339)})
+	}
+	bookmarks.Unlock()
+	writeTable(b, []string{"NAMESPACE", "NAME", "KIND", "ADDED"}, rows)
+	return nil
+}
+
+// openBookmark opens the table of the bookmarked kind with the object selected
+func openBookmark(t *throwing.TableView) {
+	namespace, name := getNamespaceAndName(t)
+	kind := selectedColumn(t, "KIND")
+	gvr, err := groupVersionResource(t.GetClientSet(), kind)
+	if err != nil {
+		t.Notify(err.Error(), throwing.SeverityError)
+		return
+	}
+	w := wrapper{group: gvr.Group, version: gvr.Version, name: gvr.Resource}
+	openResourceTable(t, w, kind)
+	if table := t.GetNestedTable(kind); table == nil || !table.SelectRow(namespace, name) {
+		t.Notify(fmt.Sprintf("%s %s not found", kind, name), throwing.SeverityWarning)
+	}
+}
@@ -0,0 +1,231 @@
+package k8s
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/gdamore/tcell"
+	"github.com/rancher/axe/throwing"
+	"github.com/rancher/axe/throwing/datafeeder"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	canIKind = "can-i"
+	// canISelf is the subject of a check run as whoever axe connects as
+	canISelf = "me"
+)
+
+var canIVerbs = []string{"get", "list", "watch", "create", "update", "patch", "delete", "deletecollection", "impersonate", "escalate", "bind", "*"}
+
+// accessCheck is a question asked on the can-i page, user and groups are empty when it's asked for axe's own identity
+type accessCheck struct {
+	attributes authorizationv1.ResourceAttributes
+	user       string
+	groups     []string
+}
+
+func (c accessCheck) subject() string {
+	if c.user == "" && len(c.groups) == 0 {
+		return canISelf
+	}
+	if len(c.groups) == 0 {
+		return c.user
+	}
+	return fmt.Sprintf("%s (%s)", c.user, strings.Join(c.groups, ","))
+}
+
+func (c accessCheck) resource() string {
+	resource := c.attributes.Resource
+	if c.attributes.Group != "" {
+		resource += "." + c.attributes.Group
+	}
+	if c.attributes.Subresource != "" {
+		resource += "/" + c.attributes.Subresource
+	}
+	return resource
+}
+
+// row is what the check looks like on the can-i page, checks are told apart by it
+func (c accessCheck) row() []string {
+	return []string{c.attributes.Verb, c.resource(), c.attributes.Name, c.attributes.Namespace, c.subject()}
+}
+
+// review asks the API server, a check for another subject needs the right to create subjectaccessreviews
+func (c accessCheck) review(clientset kubernetes.Interface) (authorizationv1.SubjectAccessReviewStatus, error) {
+	attributes := c.attributes
+	if c.user == "" && len(c.groups) == 0 {
+		review, err := clientset.AuthorizationV1().SelfSubjectAccessReviews().Create(&authorizationv1.SelfSubjectAccessReview{
+			Spec: authorizationv1.SelfSubjectAccessReviewSpec{ResourceAttributes: &attributes},
+		})
+		if err != nil {
+			return authorizationv1.SubjectAccessReviewStatus{}, err
+		}
+		return review.Status, nil
+	}
+	review, err := clientset.AuthorizationV1().SubjectAccessReviews().Create(&authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			ResourceAttributes: &attributes,
+			User:               c.user,
+			Groups:             c.groups,
+		},
+	})
+	if err != nil {
+		return authorizationv1.SubjectAccessReviewStatus{}, err
+	}
+	return review.Status, nil
+}
+
+// accessChecks are the checks asked this session, in the order they were asked
+var accessChecks = struct {
+	sync.Mutex
+	list []accessCheck
+}{}
+
+/*
+RefreshAccessChecks asks every check again, so r shows what changed after editing a role.
+The reason is what the authorizer gives, RBAC names the binding and role that allowed the request.
+*/
+func RefreshAccessChecks(b *bytes.Buffer) error {
+	accessChecks.Lock()
+	checks := append([]accessCheck(nil), accessChecks.list...)
+	accessChecks.Unlock()
+
+	clientset, err := newClientset()
+	if err != nil {
+		return err
+	}
+	var rows [][]string
+	for _, c := range checks {
+		result, reason := "no", ""
+		status, err := c.review(clientset)
+		switch {
+		case err != nil:
+			result, reason = "error", err.Error()
+		case status.Allowed:
+			result, reason = "yes", status.Reason
+		case status.Denied:
+			result, reason = "denied", status.Reason
+		default:
+			reason = status.Reason
+		}
+		if err == nil && status.EvaluationError != "" {
+			reason = strings.TrimSpace(reason + " " + status.EvaluationError)
+		}
+		if reason == "" && result == "no" {
+			reason = "no rule allows it"
+		}
+		rows = append(rows, append(c.row(), result, reason))
+	}
+	writeTable(b, []string{"VERB", "RESOURCE", "NAME", "NAMESPACE", "SUBJECT", "ALLOWED", "REASON"}, rows)
+	return nil
+}
+
+func accessCheckRowColor(header, row datafeeder.Row) tcell.Color {
+	switch columnOrEmpty(header, row, "ALLOWED") {
+	case "yes":
+		return tcell.ColorGreen
+	case "error":
+		return tcell.ColorYellow
+	}
+	return tcell.ColorRed
+}
+
+// selectedAccessCheck finds the check of the selected row
+func selectedAccessCheck(t *throwing.TableView) (int, bool) {
+	_, row := t.SelectedRow()
+	accessChecks.Lock()
+	defer accessChecks.Unlock()
+	for i, c := range accessChecks.list {
+		if len(row) >= len(c.row()) && strings.Join(c.row(), "\t") == strings.Join(row[:len(c.row())], "\t") {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// askAccess asks for a new check, starting from the selected one if there is one
+func askAccess(t *throwing.TableView) {
+	var from accessCheck
+	from.attributes.Verb = "get"
+	from.attributes.Namespace = currentNamespace()
+	if i, ok := selectedAccessCheck(t); ok {
+		accessChecks.Lock()
+		from = accessChecks.list[i]
+		accessChecks.Unlock()
+	}
+	resource := from.attributes.Resource
+	if from.attributes.Group != "" {
+		resource += "." + from.attributes.Group
+	}
+
+	fields := []throwing.FormField{
+		{Name: "verb", Label: "verb", Default: from.attributes.Verb, Options: canIVerbs},
+		{Name: "resource", Label: "resource", Default: resource, Validate: throwing.Required},
+		{Name: "subresource", Label: "subresource", Default: from.attributes.Subresource},
+		{Name: "name", Label: "name", Default: from.attributes.Name},
+		{Name: "namespace", Label: "namespace, empty for all", Default: from.attributes.Namespace},
+		{Name: "user", Label: "as user, empty for me", Default: from.user},
+		{Name: "groups", Label: "in groups, comma separated", Default: strings.Join(from.groups, ",")},
+	}
+	t.ShowForm("can-i", "Check", fields, func(values throwing.FormValues) error {
+		check := accessCheck{
+			attributes: authorizationv1.ResourceAttributes{
+				Verb:        values["verb"],
+				Subresource: strings.TrimSpace(values["subresource"]),
+				Name:        strings.TrimSpace(values["name"]),
+				Namespace:   strings.TrimSpace(values["namespace"]),
+			},
+			user: strings.TrimSpace(values["user"]),
+		}
+		for _, g := range strings.Split(values["groups"], ",") {
+			if g = strings.TrimSpace(g); g != "" {
+				check.groups = append(check.groups, g)
+			}
+		}
+		if check.user == "" && len(check.groups) > 0 {
+			return fmt.Errorf("groups need a user")
+		}
+		typed := strings.TrimSpace(values["resource"])
+		if typed == "*" {
+			check.attributes.Resource = "*"
+		} else {
+			d, err := cachedDiscovery()
+			if err != nil {
+				return err
+			}
+			gvr, err := resolveKind(d, typed)
+			if err != nil {
+				return err
+			}
+			check.attributes.Group, check.attributes.Resource = gvr.Group, gvr.Resource
+		}
+
+		accessChecks.Lock()
+		defer accessChecks.Unlock()
+		for _, c := range accessChecks.list {
+			if strings.Join(c.row(), "\t") == strings.Join(check.row(), "\t") {
+				t.Refresh()
+				return nil
+			}
+		}
+		accessChecks.list = append(accessChecks.list, check)
+		t.Refresh()
+		return nil
+	})
+}
+
+// removeAccessCheck drops the selected check from the page
+func removeAccessCheck(t *throwing.TableView) {
+	i, ok := selectedAccessCheck(t)
+	if !ok {
+		return
+	}
+	accessChecks.Lock()
+	accessChecks.list = append(accessChecks.list[:i], accessChecks.list[i+1:]...)
+	accessChecks.Unlock()
+	t.Refresh()
+}
@@ -0,0 +1,266 @@
+package k8s
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gdamore/tcell"
+	"github.com/rancher/axe/throwing"
+	"github.com/rancher/axe/throwing/datafeeder"
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	capacityKind = "capacity"
+	// clusterTotal is the first row of the capacity page, every namespace added up
+	clusterTotal = "(total)"
+)
+
+// capacityResources are the resources the capacity page adds up, in the order of its columns
+var capacityResources = []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory}
+
+// namespaceCapacity is what the pods of a namespace ask for, and what its quotas allow, in milli units
+type namespaceCapacity struct {
+	pods     int
+	requests map[v1.ResourceName]int64
+	limits   map[v1.ResourceName]int64
+	// quota is the tightest hard limit of the quotas of the namespace, by requests.cpu, limits.memory and so on
+	quota map[v1.ResourceName]int64
+}
+
+func newNamespaceCapacity() *namespaceCapacity {
+	return &namespaceCapacity{
+		requests: map[v1.ResourceName]int64{},
+		limits:   map[v1.ResourceName]int64{},
+		quota:    map[v1.ResourceName]int64{},
+	}
+}
+
+/*
+podResources is what the scheduler reserves for a pod: the containers added up, or the largest init container
+when that's more. A container without a limit leaves the limit of the pod unbounded,
+unlimited reports that so the LIMITS columns don't look tighter than they are.
+*/
+func podResources(pod v1.Pod, name v1.ResourceName) (request, limit int64, unlimited bool) {
+	for _, c := range pod.Spec.Containers {
+		if q, ok := c.Resources.Requests[name]; ok {
+			request += q.MilliValue()
+		}
+		if q, ok := c.Resources.Limits[name]; ok {
+			limit += q.MilliValue()
+		} else {
+			unlimited = true
+		}
+	}
+	for _, c := range pod.Spec.InitContainers {
+		if q, ok := c.Resources.Requests[name]; ok && q.MilliValue() > request {
+			request = q.MilliValue()
+		}
+		if q, ok := c.Resources.Limits[name]; ok && q.MilliValue() > limit {
+			limit = q.MilliValue()
+		}
+	}
+	return request, limit, unlimited
+}
+
+// quotaNames are the quota resources that cap requests and limits of name, requests.cpu and cpu both cap requests
+func quotaNames(name v1.ResourceName) (requests []v1.ResourceName, limits v1.ResourceName) {
+	return []v1.ResourceName{name, v1.ResourceName("requests." + name)}, v1.ResourceName("limits." + name)
+}
+
+// formatMilli prints milli units of name the way kubectl top does, cores for cpu and Mi or Gi for memory
+func formatMilli(name v1.ResourceName, milli int64) string {
+	if name == v1.ResourceCPU {
+		if milli < 1000 {
+			return fmt.Sprintf("%dm", milli)
+		}
+		return fmt.Sprintf("%.1f", float64(milli)/1000)
+	}
+	value := milli / 1000
+	switch {
+	case value >= 1<<30:
+		return fmt.Sprintf("%.1fGi", float64(value)/(1<<30))
+	case value >= 1<<20:
+		return fmt.Sprintf("%dMi", value>>20)
+	}
+	return resource.NewQuantity(value, resource.BinarySI).String()
+}
+
+// percentOf is part of whole in percent, in float math since a hundred times the memory of a cluster in milli units overflows
+func percentOf(part, whole int64) int64 {
+	return int64(float64(part) * 100 / float64(whole))
+}
+
+// capacityCell is the amount with its share of the quota, or of the cluster when there's no quota
+func capacityCell(name v1.ResourceName, milli, quota, allocatable int64) string {
+	switch {
+	case quota > 0:
+		return fmt.Sprintf("%s (%d%% quota)", formatMilli(name, milli), percentOf(milli, quota))
+	case allocatable > 0:
+		return fmt.Sprintf("%s (%d%%)", formatMilli(name, milli), percentOf(milli, allocatable))
+	}
+	return formatMilli(name, milli)
+}
+
+/*
+RefreshCapacity adds up the requests and limits of the pods of every namespace and holds them against its
+quotas and against the allocatable capacity of the schedulable nodes, percents without "quota" are of the cluster.
+OVERCOMMIT says what's over: requests over a quota or over the cluster can't all be scheduled,
+limits over the cluster can't all be honoured at once.
+*/
+func RefreshCapacity(b *bytes.Buffer) error {
+	clientset, err := newClientset()
+	if err != nil {
+		return err
+	}
+	nodes, err := clientset.CoreV1().Nodes().List(metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	pods, err := clientset.CoreV1().Pods(v1.NamespaceAll).List(metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	quotas, err := clientset.CoreV1().ResourceQuotas(v1.NamespaceAll).List(metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	allocatable := map[v1.ResourceName]int64{}
+	for _, node := range nodes.Items {
+		if node.Spec.Unschedulable {
+			continue
+		}
+		for _, name := range capacityResources {
+			if q, ok := node.Status.Allocatable[name]; ok {
+				allocatable[name] += q.MilliValue()
+			}
+		}
+	}
+
+	total := newNamespaceCapacity()
+	namespaces := map[string]*namespaceCapacity{}
+	unlimited := map[string]map[v1.ResourceName]bool{}
+	capacityOf := func(namespace string) *namespaceCapacity {
+		c, ok := namespaces[namespace]
+		if !ok {
+			c = newNamespaceCapacity()
+			namespaces[namespace] = c
+			unlimited[namespace] = map[v1.ResourceName]bool{}
+		}
+		return c
+	}
+	for _, pod := range pods.Items {
+		if pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+			continue
+		}
+		c := capacityOf(pod.Namespace)
+		c.pods++
+		total.pods++
+		for _, name := range capacityResources {
+			request, limit, noLimit := podResources(pod, name)
+			c.requests[name] += request
+			c.limits[name] += limit
+			total.requests[name] += request
+			total.limits[name] += limit
+			if noLimit {
+				unlimited[pod.Namespace][name] = true
+			}
+		}
+	}
+	for _, q := range quotas.Items {
+		c := capacityOf(q.Namespace)
+		for name, hard := range q.Spec.Hard {
+			if current, ok := c.quota[name]; !ok || hard.MilliValue() < current {
+				c.quota[name] = hard.MilliValue()
+			}
+		}
+	}
+
+	var names []string
+	for namespace := range namespaces {
+		names = append(names, namespace)
+	}
+	sort.Strings(names)
+
+	header := []string{"NAMESPACE", "PODS"}
+	for _, name := range capacityResources {
+		upper := strings.ToUpper(string(name))
+		header = append(header, upper+" REQUESTS", upper+" LIMITS")
+	}
+	header = append(header, "OVERCOMMIT")
+
+	row := func(namespace string, c *namespaceCapacity, noLimit map[v1.ResourceName]bool) []string {
+		cells := []string{namespace, fmt.Sprint(c.pods)}
+		var over []string
+		for _, name := range capacityResources {
+			requestNames, limitName := quotaNames(name)
+			requestQuota := int64(0)
+			for _, n := range requestNames {
+				if q, ok := c.quota[n]; ok && (requestQuota == 0 || q < requestQuota) {
+					requestQuota = q
+				}
+			}
+			limitQuota := c.quota[limitName]
+
+			limits := capacityCell(name, c.limits[name], limitQuota, allocatable[name])
+			if noLimit[name] {
+				limits += " +unlimited"
+			}
+			cells = append(cells, capacityCell(name, c.requests[name], requestQuota, allocatable[name]), limits)
+
+			switch {
+			case requestQuota > 0 && c.requests[name] > requestQuota:
+				over = append(over, fmt.Sprintf("%s requests over quota", name))
+			case allocatable[name] > 0 && c.requests[name] > allocatable[name]:
+				over = append(over, fmt.Sprintf("%s requests over allocatable", name))
+			}
+			switch {
+			case limitQuota > 0 && c.limits[name] > limitQuota:
+				over = append(over, fmt.Sprintf("%s limits over quota", name))
+			case allocatable[name] > 0 && c.limits[name] > allocatable[name]:
+				over = append(over, fmt.Sprintf("%s limits %d%% of allocatable", name, percentOf(c.limits[name], allocatable[name])))
+			}
+		}
+		return append(cells, strings.Join(over, ", "))
+	}
+
+	totalUnlimited := map[v1.ResourceName]bool{}
+	for _, noLimit := range unlimited {
+		for name := range noLimit {
+			totalUnlimited[name] = true
+		}
+	}
+	rows := [][]string{row(clusterTotal, total, totalUnlimited)}
+	for _, namespace := range names {
+		rows = append(rows, row(namespace, namespaces[namespace], unlimited[namespace]))
+	}
+	writeTable(b, header, rows)
+	return nil
+}
+
+// capacityRowColor makes requests that can't be met red and limits that can't all be honoured yellow
+func capacityRowColor(header, row datafeeder.Row) tcell.Color {
+	over := columnOrEmpty(header, row, "OVERCOMMIT")
+	switch {
+	case strings.Contains(over, "requests"):
+		return tcell.ColorRed
+	case over != "":
+		return tcell.ColorYellow
+	}
+	return tcell.ColorDefault
+}
+
+// capacityPods opens the pods of the selected namespace
+func capacityPods(t *throwing.TableView) {
+	namespace := selectedColumn(t, "NAMESPACE")
+	if namespace == "" || namespace == clusterTotal {
+		return
+	}
+	w := wrapper{version: "v1", name: "pods", namespace: namespace}
+	openResourceTable(t, w, fmt.Sprintf("pods - %s", namespace))
+}
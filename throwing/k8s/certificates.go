@@ -0,0 +1,228 @@
+package k8s
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gdamore/tcell"
+	"github.com/rancher/axe/throwing"
+	"github.com/rancher/axe/throwing/datafeeder"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+)
+
+const (
+	certificatesKind = "certificates"
+	certManagerKind  = "certificates.cert-manager.io"
+	// a certificate expiring within certCriticalDays is red, within certWarningDays yellow
+	certCriticalDays = 7
+	certWarningDays  = 30
+)
+
+type certificateRow struct {
+	namespace, name, kind, issuer, ready string
+	notAfter, renewal                    time.Time
+}
+
+func (c certificateRow) row() []string {
+	days := ""
+	if !c.notAfter.IsZero() {
+		days = strconv.Itoa(int(math.Floor(time.Until(c.notAfter).Hours() / 24)))
+	}
+	return []string{c.namespace, c.name, c.kind, c.issuer, c.ready, formatCertTime(c.notAfter), formatCertTime(c.renewal), days}
+}
+
+func formatCertTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
+
+/*
+RefreshCertificates lists the cert-manager Certificates and the TLS secrets in all namespaces, soonest to expire first.
+A secret a Certificate writes to is left out, the Certificate stands for it. Without cert-manager only secrets are listed.
+*/
+func RefreshCertificates(b *bytes.Buffer) error {
+	clientset, err := newClientset()
+	if err != nil {
+		return err
+	}
+	var rows []certificateRow
+	issued := map[string]bool{}
+	if gvr, err := groupVersionResource(clientset, certManagerKind); err == nil {
+		client, err := newDynamicClient()
+		if err != nil {
+			return err
+		}
+		list, err := client.Resource(gvr).Namespace(v1.NamespaceAll).List(metav1.ListOptions{})
+		if err != nil {
+			return err
+		}
+		for i := range list.Items {
+			c := certManagerRow(&list.Items[i])
+			rows = append(rows, c)
+			if secret, _, _ := unstructured.NestedString(list.Items[i].Object, "spec", "secretName"); secret != "" {
+				issued[c.namespace+"/"+secret] = true
+			}
+		}
+	}
+
+	secrets, err := clientset.CoreV1().Secrets(v1.NamespaceAll).List(metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("type", string(v1.SecretTypeTLS)).String(),
+	})
+	if err != nil {
+		return err
+	}
+	for _, s := range secrets.Items {
+		if issued[s.Namespace+"/"+s.Name] {
+			continue
+		}
+		rows = append(rows, secretRow(s))
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := rows[i].notAfter, rows[j].notAfter
+		if a.IsZero() != b.IsZero() {
+			return !a.IsZero()
+		}
+		return a.Before(b)
+	})
+	var table [][]string
+	for _, r := range rows {
+		table = append(table, r.row())
+	}
+	writeTable(b, []string{"NAMESPACE", "NAME", "KIND", "ISSUER", "READY", "NOT AFTER", "RENEWAL", "DAYS LEFT"}, table)
+	return nil
+}
+
+func certManagerRow(obj *unstructured.Unstructured) certificateRow {
+	c := certificateRow{namespace: obj.GetNamespace(), name: obj.GetName(), kind: certManagerKind, ready: "Unknown"}
+	issuerKind, _, _ := unstructured.NestedString(obj.Object, "spec", "issuerRef", "kind")
+	issuerName, _, _ := unstructured.NestedString(obj.Object, "spec", "issuerRef", "name")
+	if issuerKind == "" {
+		issuerKind = "Issuer"
+	}
+	c.issuer = issuerKind + "/" + issuerName
+	for _, field := range []struct {
+		name string
+		t    *time.Time
+	}{{"notAfter", &c.notAfter}, {"renewalTime", &c.renewal}} {
+		if value, _, _ := unstructured.NestedString(obj.Object, "status", field.name); value != "" {
+			if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+				*field.t = parsed
+			}
+		}
+	}
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, condition := range conditions {
+		if condition, ok := condition.(map[string]interface{}); ok && condition["type"] == "Ready" {
+			c.ready = fmt.Sprint(condition["status"])
+		}
+	}
+	return c
+}
+
+// secretRow reads the leaf certificate of a TLS secret, the issuer is the common name of whoever signed it
+func secretRow(s v1.Secret) certificateRow {
+	c := certificateRow{namespace: s.Namespace, name: s.Name, kind: "secrets", ready: "-", issuer: "-"}
+	block, _ := pem.Decode(s.Data[v1.TLSCertKey])
+	if block == nil {
+		c.ready = "no certificate"
+		return c
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		c.ready = "unreadable"
+		return c
+	}
+	c.notAfter = cert.NotAfter
+	c.issuer = cert.Issuer.CommonName
+	if c.issuer == "" {
+		c.issuer = cert.Issuer.String()
+	}
+	return c
+}
+
+func certificateRowColor(header, row datafeeder.Row) tcell.Color {
+	days, err := strconv.Atoi(columnOrEmpty(header, row, "DAYS LEFT"))
+	switch {
+	case err != nil:
+		return tcell.ColorDefault
+	case days < certCriticalDays:
+		return tcell.ColorRed
+	case days < certWarningDays:
+		return tcell.ColorYellow
+	}
+	return tcell.ColorGreen
+}
+
+/*
+renewCertificate has cert-manager issue the selected Certificate again now, like cmctl renew:
+it sets the Issuing condition with the ManuallyTriggered reason on the status.
+*/
+func renewCertificate(t *throwing.TableView) {
+	namespace, name := getNamespaceAndName(t)
+	if selectedColumn(t, "KIND") != certManagerKind {
+		t.Notify("only cert-manager certificates can be renewed", throwing.SeverityWarning)
+		return
+	}
+	if !canI(t, "update", certManagerKind, "status", namespace) {
+		return
+	}
+	t.Confirm(fmt.Sprintf("Do you want cert-manager to renew %s now?", name), "renew", func() {
+		if err := triggerIssuing(t, namespace, name); err != nil {
+			t.Notify(err.Error(), throwing.SeverityError)
+			return
+		}
+		t.Notify(fmt.Sprintf("renewal of %s triggered", name), throwing.SeverityInfo)
+		t.Refresh()
+	})
+}
+
+func triggerIssuing(t *throwing.TableView, namespace, name string) error {
+	gvr, err := groupVersionResource(t.GetClientSet(), certManagerKind)
+	if err != nil {
+		return err
+	}
+	client, err := newDynamicClient()
+	if err != nil {
+		return err
+	}
+	resource := client.Resource(gvr).Namespace(namespace)
+	obj, err := resource.Get(name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	var kept []interface{}
+	for _, condition := range conditions {
+		if condition, ok := condition.(map[string]interface{}); ok && condition["type"] == "Issuing" {
+			if condition["status"] == "True" {
+				return fmt.Errorf("%s is being issued already", name)
+			}
+			continue
+		}
+		kept = append(kept, condition)
+	}
+	kept = append(kept, map[string]interface{}{
+		"type":               "Issuing",
+		"status":             "True",
+		"reason":             "ManuallyTriggered",
+		"message":            "Certificate re-issuance manually triggered",
+		"lastTransitionTime": time.Now().UTC().Format(time.RFC3339),
+	})
+	if err := unstructured.SetNestedSlice(obj.Object, kept, "status", "conditions"); err != nil {
+		return err
+	}
+	_, err = resource.UpdateStatus(obj, metav1.UpdateOptions{})
+	return err
+}
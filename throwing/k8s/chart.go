@@ -0,0 +1,134 @@
+package k8s
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rancher/axe/throwing"
+	"github.com/rivo/tview"
+)
+
+const (
+	// chartHeight is in lines, every line has four dots
+	chartHeight = 8
+	// chartLabelWidth is the space left of the axis for the largest and the smallest value
+	chartLabelWidth = 9
+)
+
+// brailleDots are the bits of the dots of a braille cell, by column and by row from the top
+var brailleDots = [2][4]rune{{0x01, 0x02, 0x04, 0x40}, {0x08, 0x10, 0x20, 0x80}}
+
+// formatPlain prints a value without a unit, short enough for the labels of a chart
+func formatPlain(v float64) string {
+	return strconv.FormatFloat(v, 'g', 4, 64)
+}
+
+// resample fits values to n points, a point is the largest of the values it covers so spikes aren't lost
+func resample(values []float64, n int) []float64 {
+	points := make([]float64, n)
+	for i := range points {
+		from, to := i*len(values)/n, (i+1)*len(values)/n
+		if to <= from {
+			to = from + 1
+		}
+		points[i] = math.NaN()
+		for _, v := range values[from:to] {
+			if !math.IsNaN(v) && (math.IsNaN(points[i]) || v > points[i]) {
+				points[i] = v
+			}
+		}
+	}
+	return points
+}
+
+/*
+brailleChart draws values as an area chart of width cells, two points to a cell, oldest left.
+NaN values are gaps. The scale starts at zero unless a value is below it, format prints the labels.
+ASCII mode turns the dots into stars, which still shows the shape.
+*/
+func brailleChart(values []float64, width int, format func(float64) string) string {
+	if width < 1 || len(values) == 0 {
+		return ""
+	}
+	points := resample(values, width*2)
+	low, high := 0.0, math.Inf(-1)
+	for _, v := range points {
+		if math.IsNaN(v) {
+			continue
+		}
+		low, high = math.Min(low, v), math.Max(high, v)
+	}
+	if math.IsInf(high, -1) {
+		return "[gray]no data[white]\n"
+	}
+	if high <= low {
+		high = low + 1
+	}
+
+	dots := chartHeight * 4
+	cells := make([][]rune, chartHeight)
+	for i := range cells {
+		cells[i] = []rune(strings.Repeat(string(rune(0x2800)), width))
+	}
+	for x, v := range points {
+		if math.IsNaN(v) {
+			continue
+		}
+		filled := int(math.Round((v - low) / (high - low) * float64(dots)))
+		if filled == 0 && v > low {
+			filled = 1
+		}
+		for d := 0; d < filled; d++ {
+			// d counts dots from the bottom
+			line := chartHeight - 1 - d/4
+			cells[line][x/2] |= brailleDots[x%2][3-d%4]
+		}
+	}
+
+	b := &strings.Builder{}
+	for i, line := range cells {
+		label := ""
+		switch i {
+		case 0:
+			label = format(high)
+		case chartHeight - 1:
+			label = format(low)
+		}
+		// blank cells are spaces, ASCII mode would make every braille cell a star
+		text := strings.Replace(string(line), string(rune(0x2800)), " ", -1)
+		fmt.Fprintf(b, "%*s │[green]%s[white]\n", chartLabelWidth, tview.Escape(label), text)
+	}
+	return b.String()
+}
+
+// chartAxis is the line under a chart with the times of its ends
+func chartAxis(start, end time.Time, width int) string {
+	from, to := start.Format("15:04"), end.Format("15:04")
+	gap := width - len(from) - len(to)
+	if gap < 1 {
+		gap = 1
+	}
+	return fmt.Sprintf("%*s └%s\n%*s  %s%s%s\n", chartLabelWidth, "", strings.Repeat("─", width),
+		chartLabelWidth, "", from, strings.Repeat(" ", gap), to)
+}
+
+// chartWidth is how many cells a chart gets on the screen of t, room for the labels and the border left over
+func chartWidth(t *throwing.TableView) int {
+	_, _, width, _ := t.GetTable().GetInnerRect()
+	width -= chartLabelWidth + 8
+	if width < 20 {
+		width = 20
+	}
+	return width
+}
+
+// showChart opens a page with charts drawn by brailleChart, Escape goes back
+func showChart(t *throwing.TableView, title, content string) {
+	box := textBox(t, title, "", "")
+	box.SetText(content)
+	newpage := tview.NewPages().AddPage("chart", box, true, true)
+	t.SwitchPage(t.GetCurrentPage(), newpage)
+}
@@ -0,0 +1,240 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+// debugTransport logs every API call with its latency when debug logging is on
+type debugTransport struct {
+	next http.RoundTripper
+}
+
+func (d debugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := d.next.RoundTrip(req)
+	if err != nil {
+		logrus.Debugf("%s %s failed after %v: %v", req.Method, req.URL, time.Since(start), err)
+		return resp, err
+	}
+	logrus.Debugf("%s %s %d %v", req.Method, req.URL, resp.StatusCode, time.Since(start))
+	return resp, nil
+}
+
+// impersonation is set from --as, --as-group and --as-uid, client-go has no field for the uid so it's sent as a header
+var impersonation struct {
+	rest.ImpersonationConfig
+	uid string
+}
+
+type uidTransport struct {
+	uid  string
+	next http.RoundTripper
+}
+
+func (u uidTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = utilnet.CloneRequest(req)
+	req.Header.Set("Impersonate-Uid", u.uid)
+	return u.next.RoundTrip(req)
+}
+
+// connection is set from --kubeconfig, --context and --namespace, empty fields fall back to the kubeconfig defaults
+var connection struct {
+	kubeconfig, context, namespace string
+}
+
+// clientConfig loads the kubeconfig, inside a pod without one it falls back to the service account
+func clientConfig() clientcmd.ClientConfig {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = connection.kubeconfig
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{
+		CurrentContext: connection.context,
+	})
+}
+
+// rateLimit is set from --qps and --burst, every client axe creates draws from the one limiter
+var rateLimit struct {
+	qps     float32
+	burst   int
+	once    sync.Once
+	limiter flowcontrol.RateLimiter
+}
+
+func restConfig() (*rest.Config, error) {
+	config, err := clientConfig().ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	rateLimit.once.Do(func() {
+		if rateLimit.qps <= 0 {
+			rateLimit.qps = rest.DefaultQPS
+		}
+		if rateLimit.burst <= 0 {
+			rateLimit.burst = rest.DefaultBurst
+		}
+		rateLimit.limiter = flowcontrol.NewTokenBucketRateLimiter(rateLimit.qps, rateLimit.burst)
+	})
+	config.QPS, config.Burst = rateLimit.qps, rateLimit.burst
+	config.RateLimiter = rateLimit.limiter
+	config.Impersonate = impersonation.ImpersonationConfig
+	config.WrapTransport = func(rt http.RoundTripper) http.RoundTripper {
+		if impersonation.uid != "" {
+			rt = uidTransport{uid: impersonation.uid, next: rt}
+		}
+		return debugTransport{next: rt}
+	}
+	return config, nil
+}
+
+// identity describes who axe acts as, empty when it isn't impersonating anyone
+func identity() string {
+	if impersonation.UserName == "" {
+		return ""
+	}
+	id := "as " + impersonation.UserName
+	if impersonation.uid != "" {
+		id += " (uid " + impersonation.uid + ")"
+	}
+	if len(impersonation.Groups) > 0 {
+		id += " in " + strings.Join(impersonation.Groups, ",")
+	}
+	return id
+}
+
+// lifetime ends when axe stops, the kubectl and helm processes still running then are killed
+var lifetime = context.Background()
+
+// stopWith calls stop once ctx is done, unless release was called before, for watches and streams without a context
+func stopWith(ctx context.Context, stop func()) (release func()) {
+	released := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			stop()
+		case <-released:
+		}
+	}()
+	return func() { close(released) }
+}
+
+/*
+kubectl builds a kubectl command that talks to the cluster the same way axe does.
+The connection flags go first, after a -- of exec or run they would be passed to the command in the container.
+*/
+func kubectl(args ...string) *exec.Cmd {
+	var global []string
+	if connection.kubeconfig != "" {
+		global = append(global, "--kubeconfig", connection.kubeconfig)
+	}
+	if connection.context != "" {
+		global = append(global, "--context", connection.context)
+	}
+	if impersonation.UserName != "" {
+		global = append(global, "--as", impersonation.UserName)
+	}
+	for _, group := range impersonation.Groups {
+		global = append(global, "--as-group", group)
+	}
+	if impersonation.uid != "" {
+		global = append(global, "--as-uid", impersonation.uid)
+	}
+	return exec.CommandContext(lifetime, "kubectl", append(global, args...)...)
+}
+
+// helm builds a helm command that talks to the cluster the same way axe does, the connection flags first like kubectl
+func helm(args ...string) *exec.Cmd {
+	var global []string
+	if connection.kubeconfig != "" {
+		global = append(global, "--kubeconfig", connection.kubeconfig)
+	}
+	if connection.context != "" {
+		global = append(global, "--kube-context", connection.context)
+	}
+	if impersonation.UserName != "" {
+		global = append(global, "--kube-as-user", impersonation.UserName)
+	}
+	for _, group := range impersonation.Groups {
+		global = append(global, "--kube-as-group", group)
+	}
+	return exec.CommandContext(lifetime, "helm", append(global, args...)...)
+}
+
+// currentContext is the kubeconfig context axe talks to, empty when it runs with the in-cluster config
+func currentContext() (string, error) {
+	if connection.context != "" {
+		return connection.context, nil
+	}
+	raw, err := clientConfig().RawConfig()
+	if err != nil {
+		return "", err
+	}
+	return raw.CurrentContext, nil
+}
+
+func newClientset() (*kubernetes.Clientset, error) {
+	config, err := restConfig()
+	if err != nil {
+		return nil, err
+	}
+	return kubernetes.NewForConfig(config)
+}
+
+func newDynamicClient() (dynamic.Interface, error) {
+	config, err := restConfig()
+	if err != nil {
+		return nil, err
+	}
+	return dynamic.NewForConfig(config)
+}
+
+// sharedClients are built once, the connection doesn't change while axe runs
+var sharedClients struct {
+	once      sync.Once
+	clientset *kubernetes.Clientset
+	dynamic   dynamic.Interface
+	err       error
+}
+
+// cachedClients returns the clientset and dynamic client shared by the views that run often, like the split pane detail
+func cachedClients() (*kubernetes.Clientset, dynamic.Interface, error) {
+	sharedClients.once.Do(func() {
+		if sharedClients.clientset, sharedClients.err = newClientset(); sharedClients.err != nil {
+			return
+		}
+		sharedClients.dynamic, sharedClients.err = newDynamicClient()
+	})
+	return sharedClients.clientset, sharedClients.dynamic, sharedClients.err
+}
+
+// groupVersionResource resolves a group qualified kind like deployments.apps to the preferred version of its group
+func groupVersionResource(clientset kubernetes.Interface, kind string) (schema.GroupVersionResource, error) {
+	resource, group := splitKind(kind)
+	groups, err := clientset.Discovery().ServerGroups()
+	if err != nil {
+		return schema.GroupVersionResource{}, err
+	}
+	for _, g := range groups.Groups {
+		if g.Name == group {
+			return schema.GroupVersionResource{
+				Group:    group,
+				Version:  g.PreferredVersion.Version,
+				Resource: resource,
+			}, nil
+		}
+	}
+	return schema.GroupVersionResource{}, fmt.Errorf("the server doesn't serve %s", kind)
+}
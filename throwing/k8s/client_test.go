@@ -0,0 +1,62 @@
+package k8s
+
+import (
+	"os/exec"
+	"reflect"
+	"testing"
+)
+
+func TestCommandArgs(t *testing.T) {
+	savedConnection, savedImpersonation := connection, impersonation
+	defer func() {
+		connection, impersonation = savedConnection, savedImpersonation
+	}()
+	connection.kubeconfig, connection.context = "/tmp/kubeconfig", "prod"
+	impersonation.UserName, impersonation.Groups, impersonation.uid = "jane", []string{"devs"}, "42"
+
+	tests := []struct {
+		name string
+		cmd  *exec.Cmd
+		want []string
+	}{
+		{
+			name: "kubectl exec",
+			cmd:  kubectl("exec", "-n", "default", "web", "--", "sh", "-c", "ls"),
+			want: []string{"kubectl", "--kubeconfig", "/tmp/kubeconfig", "--context", "prod", "--as", "jane", "--as-group", "devs", "--as-uid", "42",
+				"exec", "-n", "default", "web", "--", "sh", "-c", "ls"},
+		},
+		{
+			name: "kubectl run",
+			cmd:  kubectl("run", "probe", "--rm", "-i", "--image", "busybox", "--", "nslookup", "web"),
+			want: []string{"kubectl", "--kubeconfig", "/tmp/kubeconfig", "--context", "prod", "--as", "jane", "--as-group", "devs", "--as-uid", "42",
+				"run", "probe", "--rm", "-i", "--image", "busybox", "--", "nslookup", "web"},
+		},
+		{
+			name: "helm",
+			cmd:  helm("list", "--all-namespaces"),
+			want: []string{"helm", "--kubeconfig", "/tmp/kubeconfig", "--kube-context", "prod", "--kube-as-user", "jane", "--kube-as-group", "devs",
+				"list", "--all-namespaces"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !reflect.DeepEqual(tt.cmd.Args, tt.want) {
+				t.Errorf("args are %q, want %q", tt.cmd.Args, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommandArgsWithoutConnection(t *testing.T) {
+	savedConnection, savedImpersonation := connection, impersonation
+	defer func() {
+		connection, impersonation = savedConnection, savedImpersonation
+	}()
+	connection.kubeconfig, connection.context = "", ""
+	impersonation.UserName, impersonation.Groups, impersonation.uid = "", nil, ""
+
+	want := []string{"kubectl", "exec", "web", "--", "ls"}
+	if got := kubectl("exec", "web", "--", "ls").Args; !reflect.DeepEqual(got, want) {
+		t.Errorf("args are %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,109 @@
+package k8s
+
+import (
+	"strings"
+
+	"github.com/gdamore/tcell"
+	"github.com/rancher/axe/throwing/datafeeder"
+)
+
+var (
+	healthyPodStatus = map[string]bool{
+		"Running":   true,
+		"Completed": true,
+		"Succeeded": true,
+	}
+	pendingPodStatus = map[string]bool{
+		"Pending":           true,
+		"ContainerCreating": true,
+		"PodInitializing":   true,
+		"Terminating":       true,
+	}
+)
+
+// rowColorForKind picks the row coloring of a resource table, nil for kinds without status colors
+func rowColorForKind(kind string) datafeeder.RowColorFunc {
+	switch kind {
+	case "pods":
+		return podRowColor
+	case "deployments.apps", "deployments.extensions":
+		return deploymentRowColor
+	case "resourcequotas":
+		return quotaRowColor
+	case "persistentvolumeclaims", "persistentvolumes":
+		return storageRowColor
+	}
+	return nil
+}
+
+func column(header, row datafeeder.Row, name string) (string, bool) {
+	for i, h := range header {
+		if h == name && i < len(row) {
+			return row[i], true
+		}
+	}
+	return "", false
+}
+
+// readyCount splits a READY column like 1/2
+func readyCount(ready string) (string, string, bool) {
+	parts := strings.SplitN(ready, "/", 2)
+	if len(parts) != 2 {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
+
+func podRowColor(header, row datafeeder.Row) tcell.Color {
+	status, ok := column(header, row, "STATUS")
+	if !ok {
+		return tcell.ColorDefault
+	}
+	// a healthy pod with risky security settings stands out, trouble still wins
+	healthy := tcell.ColorGreen
+	if riskySecurity(columnOrEmpty(header, row, "SECURITY")) {
+		healthy = tcell.ColorFuchsia
+	}
+	switch {
+	case status == "Running":
+		if ready, total, ok := readyCount(columnOrEmpty(header, row, "READY")); ok && ready != total {
+			return tcell.ColorYellow
+		}
+		// running between the restarts of a slow crash loop
+		if restartedRecently(columnOrEmpty(header, row, "TREND")) {
+			return tcell.ColorYellow
+		}
+		return healthy
+	case healthyPodStatus[status]:
+		return healthy
+	case pendingPodStatus[status], strings.HasPrefix(status, "Init:"):
+		return tcell.ColorYellow
+	}
+	// CrashLoopBackOff, Error, ImagePullBackOff, OOMKilled and friends
+	return tcell.ColorRed
+}
+
+func deploymentRowColor(header, row datafeeder.Row) tcell.Color {
+	var available, desired string
+	if ready, ok := column(header, row, "READY"); ok {
+		var valid bool
+		if available, desired, valid = readyCount(ready); !valid {
+			return tcell.ColorDefault
+		}
+	} else {
+		available = columnOrEmpty(header, row, "AVAILABLE")
+		desired = columnOrEmpty(header, row, "DESIRED")
+	}
+	switch {
+	case available == desired:
+		return tcell.ColorGreen
+	case available == "0" || available == "":
+		return tcell.ColorRed
+	}
+	return tcell.ColorYellow
+}
+
+func columnOrEmpty(header, row datafeeder.Row, name string) string {
+	value, _ := column(header, row, name)
+	return value
+}
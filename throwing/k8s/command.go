@@ -0,0 +1,84 @@
+package k8s
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/rancher/axe/throwing"
+	"github.com/sirupsen/logrus"
+)
+
+const commandHistoryState = "commandHistory"
+
+// commandHistory holds the commands run in pods this session, Up and Down in the prompt walk it
+var commandHistory = throwing.NewInputHistory(100)
+
+// persistCommandHistory keeps commandHistory in the session state, set with persistCommandHistory in the config
+var persistCommandHistory bool
+
+// restoreCommandHistory loads the commands saved with the session state when persisting is on
+func restoreCommandHistory(saved string) {
+	if !persistCommandHistory || saved == "" {
+		return
+	}
+	var entries []string
+	if err := json.Unmarshal([]byte(saved), &entries); err != nil {
+		logrus.Debugf("ignoring saved command history: %v", err)
+		return
+	}
+	for _, e := range entries {
+		commandHistory.Add(e)
+	}
+}
+
+func saveCommandHistory(t *throwing.TableView) {
+	if !persistCommandHistory {
+		return
+	}
+	if saved, err := json.Marshal(commandHistory.Entries()); err == nil {
+		t.SetStateValue(commandHistoryState, string(saved))
+	}
+}
+
+func podCommand(t *throwing.TableView) {
+	namespace, name := getNamespaceAndName(t)
+	runCommand(t, namespace, name, "")
+}
+
+func containerCommand(t *throwing.TableView) {
+	namespace, container := getNamespaceAndName(t)
+	runCommand(t, namespace, selectedColumn(t, "POD"), container)
+}
+
+// runCommand prompts for a command, runs it with sh -c in the pod and shows what it printed
+func runCommand(t *throwing.TableView, namespace, pod, container string) {
+	if !canI(t, "create", "pods", "exec", namespace) {
+		return
+	}
+	target := pod
+	if container != "" {
+		target = pod + "/" + container
+	}
+	t.PromptWithHistory(fmt.Sprintf("run in %s", target), "$ ", commandHistory, func(command string) {
+		if strings.TrimSpace(command) == "" {
+			return
+		}
+		saveCommandHistory(t)
+		args := []string{"exec", "-n", namespace, pod}
+		if container != "" {
+			args = append(args, "-c", container)
+		}
+		args = append(args, "--", "sh", "-c", command)
+		t.Go(func() {
+			out, err := kubectl(args...).CombinedOutput()
+			content := string(out)
+			if err != nil {
+				content += fmt.Sprintf("\n%v\n", err)
+			}
+			t.GetApplication().QueueUpdateDraw(func() {
+				showText(t, "command", fmt.Sprintf("%s $ %s", target, command), content, "")
+			})
+		})
+	})
+}
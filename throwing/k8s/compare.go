@@ -0,0 +1,223 @@
+package k8s
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell"
+	"github.com/rancher/axe/throwing"
+	"github.com/rivo/tview"
+)
+
+type diffOp int
+
+const (
+	diffEqual diffOp = iota
+	diffRemoved
+	diffAdded
+	diffChanged
+)
+
+// diffRow is one row of a side by side diff, a side is -1 when it has no line on that row
+type diffRow struct {
+	op          diffOp
+	left, right int
+}
+
+type objectRef struct {
+	kind, namespace, name string
+}
+
+func (o objectRef) String() string {
+	if o.namespace == "" {
+		return fmt.Sprintf("%s %s", o.kind, o.name)
+	}
+	return fmt.Sprintf("%s %s/%s", o.kind, o.namespace, o.name)
+}
+
+// compareMark is the object picked with mark, compare diffs the selected object against it
+var compareMark *objectRef
+
+// diffLines aligns a and b on their longest common subsequence, runs of removed and added lines are paired up as changes
+func diffLines(a, b []string) []diffRow {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var rows []diffRow
+	var removed, added []int
+	flush := func() {
+		for len(removed) > 0 && len(added) > 0 {
+			rows = append(rows, diffRow{op: diffChanged, left: removed[0], right: added[0]})
+			removed, added = removed[1:], added[1:]
+		}
+		for _, l := range removed {
+			rows = append(rows, diffRow{op: diffRemoved, left: l, right: -1})
+		}
+		for _, r := range added {
+			rows = append(rows, diffRow{op: diffAdded, left: -1, right: r})
+		}
+		removed, added = nil, nil
+	}
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			flush()
+			rows = append(rows, diffRow{op: diffEqual, left: i, right: j})
+			i++
+			j++
+		case j == len(b) || i < len(a) && lcs[i+1][j] >= lcs[i][j+1]:
+			removed = append(removed, i)
+			i++
+		default:
+			added = append(added, j)
+			j++
+		}
+	}
+	flush()
+	return rows
+}
+
+func diffColor(op diffOp, left bool) tcell.Color {
+	switch {
+	case op == diffChanged:
+		return tcell.ColorYellow
+	case op == diffRemoved && left:
+		return tcell.ColorRed
+	case op == diffAdded && !left:
+		return tcell.ColorGreen
+	}
+	return tcell.ColorAntiqueWhite
+}
+
+func markForCompare(t *throwing.TableView) {
+	ref := selectedRef(t)
+	compareMark = &ref
+	t.Notify(fmt.Sprintf("marked %s, select another object and press C to compare", compareMark), throwing.SeverityInfo)
+}
+
+// compareWithMark renders the YAML of the marked and the selected object next to each other
+func compareWithMark(t *throwing.TableView) {
+	if compareMark == nil {
+		t.Notify("mark an object with M first", throwing.SeverityWarning)
+		return
+	}
+	selected := selectedRef(t)
+
+	left, err := kubectlObject("get", compareMark.kind, compareMark.namespace, compareMark.name, "-o", "yaml")
+	if err != nil {
+		t.Notify(err.Error(), throwing.SeverityError)
+		return
+	}
+	right, err := kubectlObject("get", selected.kind, selected.namespace, selected.name, "-o", "yaml")
+	if err != nil {
+		t.Notify(err.Error(), throwing.SeverityError)
+		return
+	}
+	recordRevision(*compareMark, left)
+	recordRevision(selected, right)
+	if externalDiff(t, compareMark.String(), left, selected.String(), right) {
+		return
+	}
+	a := strings.Split(strings.TrimSuffix(left, "\n"), "\n")
+	b := strings.Split(strings.TrimSuffix(right, "\n"), "\n")
+
+	table := tview.NewTable()
+	table.SetBorder(true)
+	table.SetTitle(fmt.Sprintf("compare - (%s <> %s)", compareMark, selected))
+	table.SetTitleColor(tcell.ColorPurple)
+	table.SetBackgroundColor(tcell.ColorBlack)
+	table.SetSelectable(true, false)
+	table.SetFixed(1, 0)
+	table.SetSeparator('│')
+
+	header := func(col int, text string) {
+		table.SetCell(0, col, tview.NewTableCell(text).SetTextColor(tcell.ColorPurple).SetSelectable(false))
+	}
+	header(0, "")
+	header(1, compareMark.String())
+	header(2, "")
+	header(3, selected.String())
+
+	side := func(row, col, line int, lines []string, color tcell.Color) {
+		if line < 0 {
+			table.SetCell(row, col, tview.NewTableCell(""))
+			table.SetCell(row, col+1, tview.NewTableCell("").SetExpansion(1))
+			return
+		}
+		table.SetCell(row, col, tview.NewTableCell(fmt.Sprint(line+1)).SetTextColor(tcell.ColorGray).SetAlign(tview.AlignRight))
+		table.SetCell(row, col+1, tview.NewTableCell(lines[line]).SetTextColor(color).SetExpansion(1))
+	}
+	for i, r := range diffLines(a, b) {
+		side(i+1, 0, r.left, a, diffColor(r.op, true))
+		side(i+1, 2, r.right, b, diffColor(r.op, false))
+	}
+	table.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEscape {
+			t.SwitchToRootPage()
+		}
+	})
+
+	newpage := tview.NewPages().AddPage("compare", table, true, true)
+	t.SwitchPage(t.GetCurrentPage(), newpage)
+}
+
+const diffContext = 3
+
+// unifiedDiff renders the changes from a to b as a unified diff with a few lines of context around each hunk
+func unifiedDiff(a, b []string, fromName, toName string) string {
+	var lines []string
+	var changed []bool
+	for _, r := range diffLines(a, b) {
+		switch r.op {
+		case diffEqual:
+			lines = append(lines, " "+a[r.left])
+			changed = append(changed, false)
+		case diffRemoved:
+			lines = append(lines, "-"+a[r.left])
+			changed = append(changed, true)
+		case diffAdded:
+			lines = append(lines, "+"+b[r.right])
+			changed = append(changed, true)
+		case diffChanged:
+			lines = append(lines, "-"+a[r.left], "+"+b[r.right])
+			changed = append(changed, true, true)
+		}
+	}
+
+	out := &strings.Builder{}
+	fmt.Fprintf(out, "--- %s\n+++ %s\n", fromName, toName)
+	last := -1
+	for i := range lines {
+		near := false
+		for j := i - diffContext; j <= i+diffContext; j++ {
+			if j >= 0 && j < len(changed) && changed[j] {
+				near = true
+				break
+			}
+		}
+		if !near {
+			continue
+		}
+		if last < 0 || i != last+1 {
+			out.WriteString("@@\n")
+		}
+		out.WriteString(lines[i] + "\n")
+		last = i
+	}
+	return out.String()
+}
@@ -0,0 +1,81 @@
+package k8s
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// completionTimeout keeps a shell from hanging on tab when the cluster is away
+const completionTimeout = 3 * time.Second
+
+/*
+Complete returns the candidates the completion scripts ask for: contexts from the kubeconfig,
+namespaces from the cluster, or the kinds the command line takes with their short names and aliases.
+c is the context of the app, the global flags are its flags.
+*/
+func Complete(c *cli.Context, what string) ([]string, error) {
+	switch what {
+	case "contexts":
+		connection.kubeconfig = c.String("kubeconfig")
+		raw, err := clientConfig().RawConfig()
+		if err != nil {
+			return nil, err
+		}
+		var contexts []string
+		for name := range raw.Contexts {
+			contexts = append(contexts, name)
+		}
+		sort.Strings(contexts)
+		return contexts, nil
+	case "namespaces":
+		if _, _, err := configure(c); err != nil {
+			return nil, err
+		}
+		config, err := restConfig()
+		if err != nil {
+			return nil, err
+		}
+		config.Timeout = completionTimeout
+		clientset, err := kubernetes.NewForConfig(config)
+		if err != nil {
+			return nil, err
+		}
+		list, err := clientset.CoreV1().Namespaces().List(metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		var namespaces []string
+		for _, ns := range list.Items {
+			namespaces = append(namespaces, ns.Name)
+		}
+		return namespaces, nil
+	case "kinds":
+		_, d, err := configure(c)
+		if err != nil {
+			return nil, err
+		}
+		resources, err := listableResources(d)
+		if err != nil {
+			return nil, err
+		}
+		seen := map[string]bool{}
+		var kinds []string
+		for _, r := range resources {
+			for _, name := range append([]string{r.gvr.Resource, kindOf(r.gvr), strings.ToLower(r.title)}, r.aliases...) {
+				if !seen[name] {
+					seen[name] = true
+					kinds = append(kinds, name)
+				}
+			}
+		}
+		sort.Strings(kinds)
+		return kinds, nil
+	}
+	return nil, fmt.Errorf("nothing to complete for %q, expected contexts, namespaces or kinds", what)
+}
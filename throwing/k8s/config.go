@@ -0,0 +1,100 @@
+package k8s
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/gdamore/tcell"
+	"github.com/ghodss/yaml"
+	"k8s.io/client-go/util/homedir"
+)
+
+/*
+config is read from ~/.axe/config.yaml, or the file given with --config.
+
+	keys:
+	  finder: ctrl+f        # global keys, see the throwing.Key* names
+	  page.helm: h          # jump to a page
+	  get: y                # an action on every table
+	  pods.logs: L          # an action on one kind
+	footer:                 # pages in the footer, built in ones or resource kinds
+	- kubernetes
+	- po
+	- deploy
+	- events
+	aliases:                # extra names for kinds, on top of the short names from discovery
+	  dp: deployments.apps
+	accents:                # border and footer color per kubeconfig context, a color name or #rrggbb
+	  prod: red
+	  staging: "#ffaf00"
+	readOnly: true          # same as --read-only
+	keymap: vim             # key profile of the tables, --keymap overrides it
+	persistCommandHistory: true  # keep the commands run in pods across sessions
+	logLines: 10000         # lines a log view keeps, the oldest are dropped, 0 keeps them all
+	maxCellWidth: 50        # wider cells are cut with an ellipsis, z shows them whole, 0 never cuts
+	podSecurity: true       # pods get the SECURITY column, P toggles it
+	imageScanner: grype     # scans an image on the images page, trivy by default
+	netDebugImage: nicolaka/netshoot  # the pod K probes from, needs sh, nslookup, nc and curl
+	prometheus: http://prometheus.monitoring:9090  # turns on the prometheus page and U on pods
+	alertmanager: http://alertmanager.monitoring:9093  # lists its alerts on the alertmanager page
+	alerts:                 # rows to be told about, checked every 30s and listed on the alerts page
+	- kind: po
+	  column: status        # a column of the kind's table
+	  match: BackOff|Error  # a regular expression
+	  severity: error       # info, warning (the default) or error
+*/
+type config struct {
+	Keys         map[string]string `json:"keys,omitempty"`
+	Footer       []string          `json:"footer,omitempty"`
+	Aliases      map[string]string `json:"aliases,omitempty"`
+	Accents      map[string]string `json:"accents,omitempty"`
+	ReadOnly     bool              `json:"readOnly,omitempty"`
+	PodSecurity  bool              `json:"podSecurity,omitempty"`
+	Tmux         map[string]string `json:"tmux,omitempty"`
+	SSH          sshConfig         `json:"ssh,omitempty"`
+	DiffTool     string            `json:"diffTool,omitempty"`
+	ImageScanner string            `json:"imageScanner,omitempty"`
+	Prometheus   string            `json:"prometheus,omitempty"`
+	Alertmanager string            `json:"alertmanager,omitempty"`
+	LogLines     *int              `json:"logLines,omitempty"`
+	MaxCellWidth *int              `json:"maxCellWidth,omitempty"`
+	Alerts       []alertRule       `json:"alerts,omitempty"`
+
+	PersistCommandHistory bool   `json:"persistCommandHistory,omitempty"`
+	NetDebugImage         string `json:"netDebugImage,omitempty"`
+	Keymap                string `json:"keymap,omitempty"`
+}
+
+// accent is the color configured for context, ColorDefault when there is none
+func (c config) accent(context string) (tcell.Color, error) {
+	name, ok := c.Accents[context]
+	if !ok {
+		return tcell.ColorDefault, nil
+	}
+	color := tcell.GetColor(name)
+	if color == tcell.ColorDefault {
+		return color, fmt.Errorf("accent of context %s: unknown color %q", context, name)
+	}
+	return color, nil
+}
+
+func defaultConfigPath() string {
+	return filepath.Join(homedir.HomeDir(), ".axe", "config.yaml")
+}
+
+// loadConfig returns an empty config when the file doesn't exist
+func loadConfig(path string) (config, error) {
+	var c config
+	if path == "" {
+		path = defaultConfigPath()
+	}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return c, nil
+	} else if err != nil {
+		return c, err
+	}
+	return c, yaml.Unmarshal(data, &c)
+}
@@ -0,0 +1,97 @@
+package k8s
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/gdamore/tcell"
+	"github.com/rancher/axe/throwing"
+	"github.com/rivo/tview"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+/*
+viewConfigMap lists the configmap keys on the left and shows the selected value on the right.
+
+Tab: switch focus between the keys and the value
+*/
+func viewConfigMap(t *throwing.TableView) {
+	namespace, name := getNamespaceAndName(t)
+	configMap, err := t.GetClientSet().CoreV1().ConfigMaps(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		t.Notify(err.Error(), throwing.SeverityError)
+		return
+	}
+
+	var keys []string
+	for k := range configMap.Data {
+		keys = append(keys, k)
+	}
+	for k := range configMap.BinaryData {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	keyTable := tview.NewTable()
+	keyTable.SetBorder(true)
+	keyTable.SetTitle(fmt.Sprintf("configmap - (%s/%s)", namespace, name))
+	keyTable.SetTitleColor(tcell.ColorPurple)
+	keyTable.SetBackgroundColor(tcell.ColorBlack)
+	keyTable.SetSelectable(true, false)
+	keyTable.SetFixed(1, 0)
+	keyTable.SetCell(0, 0, tview.NewTableCell("KEY").SetSelectable(false).SetAttributes(tcell.AttrBold))
+	keyTable.SetCell(0, 1, tview.NewTableCell("SIZE").SetSelectable(false).SetAttributes(tcell.AttrBold))
+	for i, k := range keys {
+		size := len(configMap.Data[k])
+		if data, ok := configMap.BinaryData[k]; ok {
+			size = len(data)
+		}
+		keyTable.SetCell(i+1, 0, tview.NewTableCell(k).SetTextColor(tcell.ColorAntiqueWhite).SetExpansion(1))
+		keyTable.SetCell(i+1, 1, tview.NewTableCell(fmt.Sprint(size)).SetTextColor(tcell.ColorAntiqueWhite))
+	}
+
+	valueBox := tview.NewTextView()
+	valueBox.SetBorder(true)
+	valueBox.SetDynamicColors(true)
+	valueBox.SetBackgroundColor(tcell.ColorBlack)
+
+	keyTable.SetSelectionChangedFunc(func(row, column int) {
+		if row < 1 || row > len(keys) {
+			return
+		}
+		key := keys[row-1]
+		valueBox.SetTitle(key)
+		if data, ok := configMap.BinaryData[key]; ok {
+			valueBox.SetText(fmt.Sprintf("[gray]<binary data, %d bytes>", len(data)))
+		} else {
+			value := configMap.Data[key]
+			valueBox.SetText(highlight(value, detectLanguage(key, value)))
+		}
+		valueBox.ScrollToBeginning()
+	})
+	if len(keys) > 0 {
+		keyTable.Select(1, 0)
+	}
+
+	layout := tview.NewFlex().
+		AddItem(keyTable, 0, 1, true).
+		AddItem(valueBox, 0, 2, false)
+	keyTable.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyTab || event.Key() == tcell.KeyEnter {
+			t.GetApplication().SetFocus(valueBox)
+			return nil
+		}
+		return event
+	})
+	valueBox.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyTab {
+			t.GetApplication().SetFocus(keyTable)
+			return nil
+		}
+		return event
+	})
+
+	newpage := tview.NewPages().AddPage("configmap", layout, true, true)
+	t.SwitchPage(t.GetCurrentPage(), newpage)
+	t.GetApplication().SetFocus(keyTable)
+}
@@ -0,0 +1,138 @@
+package k8s
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell"
+	"github.com/rancher/axe/throwing"
+	"github.com/rancher/axe/throwing/datafeeder"
+	"github.com/rancher/axe/throwing/types"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const podContainersKind = "podcontainers"
+
+type podContainers struct {
+	namespace, name string
+}
+
+func (p podContainers) refresh(b *bytes.Buffer) error {
+	clientset, err := newClientset()
+	if err != nil {
+		return err
+	}
+	pod, err := clientset.CoreV1().Pods(p.namespace).Get(p.name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	statuses := map[string]v1.ContainerStatus{}
+	for _, cs := range append(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses...) {
+		statuses[cs.Name] = cs
+	}
+
+	var rows [][]string
+	add := func(containerType string, c v1.Container) {
+		cs := statuses[c.Name]
+		rows = append(rows, []string{
+			pod.Namespace,
+			c.Name,
+			pod.Name,
+			containerType,
+			c.Image,
+			containerState(cs.State),
+			strconv.Itoa(int(cs.RestartCount)),
+			lastTermination(cs.LastTerminationState),
+			probes(c),
+		})
+	}
+	for _, c := range pod.Spec.InitContainers {
+		add("init", c)
+	}
+	for _, c := range pod.Spec.Containers {
+		add("app", c)
+	}
+	writeTable(b, []string{"NAMESPACE", "NAME", "POD", "TYPE", "IMAGE", "STATE", "RESTARTS", "LAST TERMINATION", "PROBES"}, rows)
+	return nil
+}
+
+func containerState(state v1.ContainerState) string {
+	switch {
+	case state.Running != nil:
+		return "Running"
+	case state.Waiting != nil:
+		return "Waiting: " + state.Waiting.Reason
+	case state.Terminated != nil:
+		return fmt.Sprintf("Terminated: %s (%d)", state.Terminated.Reason, state.Terminated.ExitCode)
+	}
+	return "Unknown"
+}
+
+func lastTermination(state v1.ContainerState) string {
+	if state.Terminated == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s (%d) %s ago", state.Terminated.Reason, state.Terminated.ExitCode, age(state.Terminated.FinishedAt))
+}
+
+func probes(c v1.Container) string {
+	var out []string
+	for _, p := range []struct {
+		name  string
+		probe *v1.Probe
+	}{
+		{"liveness", c.LivenessProbe},
+		{"readiness", c.ReadinessProbe},
+	} {
+		if p.probe != nil {
+			out = append(out, fmt.Sprintf("%s %s", p.name, probeHandler(p.probe.Handler)))
+		}
+	}
+	return strings.Join(out, ", ")
+}
+
+func probeHandler(h v1.Handler) string {
+	switch {
+	case h.HTTPGet != nil:
+		return fmt.Sprintf("http-get :%s%s", h.HTTPGet.Port.String(), h.HTTPGet.Path)
+	case h.TCPSocket != nil:
+		return fmt.Sprintf("tcp :%s", h.TCPSocket.Port.String())
+	case h.Exec != nil:
+		return fmt.Sprintf("exec %s", strings.Join(h.Exec.Command, " "))
+	}
+	return "unknown"
+}
+
+func containersRowColor(header, row datafeeder.Row) tcell.Color {
+	state := columnOrEmpty(header, row, "STATE")
+	switch {
+	case strings.HasPrefix(state, "Waiting"):
+		return tcell.ColorYellow
+	case strings.HasPrefix(state, "Terminated") && !strings.Contains(state, "Completed"):
+		return tcell.ColorRed
+	}
+	return tcell.ColorDefault
+}
+
+// viewContainers opens one row per container of the selected pod
+func viewContainers(t *throwing.TableView) {
+	namespace, name := getNamespaceAndName(t)
+	p := podContainers{namespace: namespace, name: name}
+	openTable(t, types.ResourceKind{
+		Title: fmt.Sprintf("containers - (%s/%s)", namespace, name),
+		Kind:  podContainersKind,
+	}, datafeeder.NewDataFeeder(p.refresh).SetRowColor(containersRowColor))
+}
+
+func containerLogs(t *throwing.TableView) {
+	namespace, container := getNamespaceAndName(t)
+	podLogs(t, namespace, selectedColumn(t, "POD"), container)
+}
+
+func containerExec(t *throwing.TableView) {
+	namespace, container := getNamespaceAndName(t)
+	podExec(t, namespace, selectedColumn(t, "POD"), container)
+}
@@ -0,0 +1,69 @@
+package k8s
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rancher/axe/throwing"
+)
+
+// selectedCommands are the kubectl commands doing what axe does for the selected object, to paste elsewhere
+func selectedCommands(t *throwing.TableView) []string {
+	kind := t.GetResourceKind()
+	namespace, name := getNamespaceAndName(t)
+	container := ""
+	if kind == podContainersKind {
+		kind, container, name = "pods", name, selectedColumn(t, "POD")
+	}
+
+	object := fmt.Sprintf("kubectl %%s %s %s", kind, name)
+	if namespace != "" {
+		object += " -n " + namespace
+	}
+	commands := []string{
+		fmt.Sprintf(object, "get") + " -o yaml",
+		fmt.Sprintf(object, "describe"),
+		fmt.Sprintf(object, "edit"),
+		fmt.Sprintf(object, "delete"),
+	}
+	if kind == "pods" {
+		pod := fmt.Sprintf("kubectl %%s %s", name)
+		if namespace != "" {
+			pod += " -n " + namespace
+		}
+		if container != "" {
+			pod += " -c " + container
+		}
+		commands = append(commands,
+			fmt.Sprintf(pod, "logs")+" -f",
+			fmt.Sprintf(pod, "exec -it")+" -- sh",
+		)
+	}
+	return commands
+}
+
+// copyMarkdown copies the table as Markdown, without a clipboard tool it's shown to be copied from the terminal
+func copyMarkdown(t *throwing.TableView) {
+	markdown := t.Markdown()
+	if err := copyToClipboard(markdown); err != nil {
+		t.Notify(err.Error(), throwing.SeverityError)
+		showText(t, "markdown", fmt.Sprintf("%s as Markdown", t.GetResourceKind()), markdown, "")
+		return
+	}
+	t.Notify(fmt.Sprintf("%s copied as Markdown", t.GetResourceKind()), throwing.SeverityInfo)
+}
+
+// copyCommand offers the kubectl commands for the selected object and copies the one picked to the clipboard
+func copyCommand(t *throwing.TableView) {
+	_, name := getNamespaceAndName(t)
+	if strings.TrimSpace(name) == "" {
+		return
+	}
+	t.Choose("copy as kubectl", selectedCommands(t), func(_ int, command string) {
+		if err := copyToClipboard(command); err != nil {
+			t.Notify(err.Error(), throwing.SeverityError)
+			return
+		}
+		t.Notify(fmt.Sprintf("copied %s", command), throwing.SeverityInfo)
+	})
+}
@@ -0,0 +1,59 @@
+package k8s
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rancher/axe/throwing"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// triggerCronJob is the equivalent of kubectl create job --from=cronjob/<name>
+func triggerCronJob(t *throwing.TableView) {
+	namespace, name := getNamespaceAndName(t)
+	if !canI(t, "create", "jobs.batch", "", namespace) {
+		return
+	}
+	client := t.GetClientSet()
+
+	cronJob, err := client.BatchV1beta1().CronJobs(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		t.Notify(err.Error(), throwing.SeverityError)
+		return
+	}
+
+	annotations := map[string]string{
+		"cronjob.kubernetes.io/instantiate": "manual",
+	}
+	for k, v := range cronJob.Spec.JobTemplate.Annotations {
+		annotations[k] = v
+	}
+	controller := true
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        fmt.Sprintf("%s-manual-%d", name, time.Now().Unix()),
+			Namespace:   namespace,
+			Labels:      cronJob.Spec.JobTemplate.Labels,
+			Annotations: annotations,
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: "batch/v1beta1",
+					Kind:       "CronJob",
+					Name:       cronJob.Name,
+					UID:        cronJob.UID,
+					Controller: &controller,
+				},
+			},
+		},
+		Spec: cronJob.Spec.JobTemplate.Spec,
+	}
+	job, err = client.BatchV1().Jobs(namespace).Create(job)
+	if err != nil {
+		t.Notify(err.Error(), throwing.SeverityError)
+		return
+	}
+	t.Notify(fmt.Sprintf("job %s created", job.Name), throwing.SeverityInfo)
+
+	openJobPods(t, namespace, job.Name)
+}
@@ -0,0 +1,160 @@
+package k8s
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/gdamore/tcell"
+	"github.com/rancher/axe/throwing"
+	"github.com/rancher/axe/throwing/datafeeder"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	dashboardKind   = "overview"
+	dashboardEvents = 10
+)
+
+// dashboardSections maps the SECTION column to the table Enter opens
+var dashboardSections = map[string]wrapper{
+	"Nodes":       builtinKinds["nodes"],
+	"Namespaces":  builtinKinds["namespaces"],
+	"Deployments": builtinKinds["deployments.apps"],
+	"Pods":        builtinKinds["pods"],
+	"Events":      builtinKinds["events"],
+}
+
+// RefreshDashboard summarizes cluster health into SECTION, ITEM, VALUE rows
+func RefreshDashboard(b *bytes.Buffer) error {
+	clientset, err := newClientset()
+	if err != nil {
+		return err
+	}
+
+	var rows [][]string
+	for _, section := range []func(*kubernetes.Clientset) ([][]string, error){nodeSummary, namespaceSummary, deploymentSummary, podSummary, warningEvents} {
+		r, err := section(clientset)
+		if err != nil {
+			return err
+		}
+		rows = append(rows, r...)
+	}
+	writeTable(b, []string{"SECTION", "ITEM", "VALUE"}, rows)
+	return nil
+}
+
+func nodeSummary(clientset *kubernetes.Clientset) ([][]string, error) {
+	nodes, err := clientset.CoreV1().Nodes().List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	ready := 0
+	for _, n := range nodes.Items {
+		for _, c := range n.Status.Conditions {
+			if c.Type == v1.NodeReady && c.Status == v1.ConditionTrue {
+				ready++
+			}
+		}
+	}
+	return [][]string{
+		{"Nodes", "Ready", fmt.Sprintf("%d/%d", ready, len(nodes.Items))},
+	}, nil
+}
+
+func namespaceSummary(clientset *kubernetes.Clientset) ([][]string, error) {
+	namespaces, err := clientset.CoreV1().Namespaces().List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return [][]string{
+		{"Namespaces", "Total", fmt.Sprint(len(namespaces.Items))},
+	}, nil
+}
+
+func deploymentSummary(clientset *kubernetes.Clientset) ([][]string, error) {
+	deployments, err := clientset.AppsV1().Deployments(v1.NamespaceAll).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	available := 0
+	for _, d := range deployments.Items {
+		desired := int32(1)
+		if d.Spec.Replicas != nil {
+			desired = *d.Spec.Replicas
+		}
+		if d.Status.AvailableReplicas >= desired {
+			available++
+		}
+	}
+	return [][]string{
+		{"Deployments", "Available", fmt.Sprintf("%d/%d", available, len(deployments.Items))},
+	}, nil
+}
+
+func podSummary(clientset *kubernetes.Clientset) ([][]string, error) {
+	pods, err := clientset.CoreV1().Pods(v1.NamespaceAll).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	phases := map[v1.PodPhase]int{}
+	for _, p := range pods.Items {
+		phases[p.Status.Phase]++
+	}
+	var rows [][]string
+	for _, phase := range []v1.PodPhase{v1.PodRunning, v1.PodPending, v1.PodSucceeded, v1.PodFailed, v1.PodUnknown} {
+		rows = append(rows, []string{"Pods", string(phase), fmt.Sprint(phases[phase])})
+	}
+	return rows, nil
+}
+
+func warningEvents(clientset *kubernetes.Clientset) ([][]string, error) {
+	events, err := clientset.CoreV1().Events(v1.NamespaceAll).List(metav1.ListOptions{
+		FieldSelector: "type=" + v1.EventTypeWarning,
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(events.Items, func(i, j int) bool {
+		return events.Items[j].LastTimestamp.Before(&events.Items[i].LastTimestamp)
+	})
+	var rows [][]string
+	for i, e := range events.Items {
+		if i == dashboardEvents {
+			break
+		}
+		object := fmt.Sprintf("%s/%s %s", e.InvolvedObject.Namespace, e.InvolvedObject.Name, e.InvolvedObject.Kind)
+		rows = append(rows, []string{"Events", object, fmt.Sprintf("%s: %s", e.Reason, e.Message)})
+	}
+	return rows, nil
+}
+
+func dashboardRowColor(header, row datafeeder.Row) tcell.Color {
+	value := columnOrEmpty(header, row, "VALUE")
+	switch columnOrEmpty(header, row, "SECTION") {
+	case "Events":
+		return tcell.ColorRed
+	case "Nodes", "Deployments":
+		if ready, total, ok := readyCount(value); ok && ready != total {
+			return tcell.ColorRed
+		}
+		return tcell.ColorGreen
+	case "Pods":
+		item := columnOrEmpty(header, row, "ITEM")
+		if value != "0" && (item == string(v1.PodFailed) || item == string(v1.PodUnknown)) {
+			return tcell.ColorRed
+		}
+		if value != "0" && item == string(v1.PodPending) {
+			return tcell.ColorYellow
+		}
+	}
+	return tcell.ColorDefault
+}
+
+func viewDashboardSection(t *throwing.TableView) {
+	if w, ok := dashboardSections[selectedColumn(t, "SECTION")]; ok {
+		openResourceTable(t, w, w.kind())
+	}
+}
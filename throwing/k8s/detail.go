@@ -0,0 +1,119 @@
+package k8s
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rivo/tview"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+)
+
+const detailEvents = 5
+
+func headerValue(header, row []string, name string) string {
+	for i, h := range header {
+		if h == name && i < len(row) {
+			return row[i]
+		}
+	}
+	return ""
+}
+
+/*
+rowDetail is the split pane summary of a row: status, key fields, conditions and the latest events of the object.
+Tables that aren't backed by a resource return nothing so the pane falls back to the row itself.
+*/
+func rowDetail(kind string, header, row []string) (string, error) {
+	namespace, name := headerValue(header, row, "NAMESPACE"), headerValue(header, row, "NAME")
+	if name == "" {
+		return "", nil
+	}
+	clientset, client, err := cachedClients()
+	if err != nil {
+		return "", err
+	}
+	gvr, err := cachedResource(kind)
+	if err != nil {
+		return "", nil
+	}
+	obj, err := client.Resource(gvr).Namespace(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+
+	b := &strings.Builder{}
+	field := func(label string, value interface{}) {
+		if s := fmt.Sprint(value); value != nil && s != "" {
+			fmt.Fprintf(b, "[aqua]%s:[white] %s\n", label, tview.Escape(s))
+		}
+	}
+	field("Kind", obj.GetKind())
+	field("Name", obj.GetName())
+	field("Namespace", obj.GetNamespace())
+	field("Age", age(obj.GetCreationTimestamp()))
+	var labels []string
+	for k, v := range obj.GetLabels() {
+		labels = append(labels, k+"="+v)
+	}
+	sort.Strings(labels)
+	field("Labels", strings.Join(labels, ", "))
+
+	nested := func(path ...string) interface{} {
+		v, _, _ := unstructured.NestedFieldNoCopy(obj.Object, path...)
+		return v
+	}
+	field("Phase", nested("status", "phase"))
+	field("Node", nested("spec", "nodeName"))
+	field("Pod IP", nested("status", "podIP"))
+	field("Replicas", nested("spec", "replicas"))
+	field("Ready", nested("status", "readyReplicas"))
+	field("Images", strings.Join(images(obj), ", "))
+
+	fmt.Fprintf(b, "\n[purple]Conditions[white]\n%s\n", conditionsSummary(obj))
+	if obj.GetKind() == "Pod" {
+		fmt.Fprintf(b, "\n[purple]Security[white]\n%s", securityDetail(obj))
+	}
+
+	events, err := clientset.CoreV1().Events(namespace).List(metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("involvedObject.name", name).String(),
+	})
+	if err == nil && len(events.Items) > 0 {
+		items := events.Items
+		sort.Slice(items, func(i, j int) bool {
+			return eventTime(&items[i]).After(eventTime(&items[j]))
+		})
+		if len(items) > detailEvents {
+			items = items[:detailEvents]
+		}
+		fmt.Fprintf(b, "\n[purple]Events[white]\n")
+		for _, e := range items {
+			color := "white"
+			if e.Type == v1.EventTypeWarning {
+				color = "red"
+			}
+			fmt.Fprintf(b, "[%s]%s %s[white] %s\n", color, eventTime(&e).Format("15:04:05"), e.Reason, tview.Escape(e.Message))
+		}
+	}
+	return b.String(), nil
+}
+
+// images collects the container images of pods and of pod templates
+func images(obj *unstructured.Unstructured) []string {
+	var out []string
+	for _, path := range [][]string{
+		{"spec", "containers"},
+		{"spec", "template", "spec", "containers"},
+	} {
+		containers, _, _ := unstructured.NestedSlice(obj.Object, path...)
+		for _, c := range containers {
+			if container, ok := c.(map[string]interface{}); ok {
+				out = append(out, fmt.Sprint(container["image"]))
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,70 @@
+package k8s
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/rancher/axe/throwing"
+)
+
+/*
+diffTool is the external diff command from diffTool in the config, e.g. "vimdiff" or "delta --paging always".
+The two manifests are appended to it as files. A tool that exits right away needs a pager, the UI comes back when it exits.
+*/
+var diffTool []string
+
+/*
+externalDiff shows left and right in the diff tool, handing it the terminal until it exits.
+It returns false when no tool is configured and the builtin diff has to do.
+*/
+func externalDiff(t *throwing.TableView, leftName, left, rightName, right string) bool {
+	if len(diffTool) == 0 {
+		return false
+	}
+	dir, err := ioutil.TempDir("", "axe-diff")
+	if err != nil {
+		t.Notify(err.Error(), throwing.SeverityError)
+		return true
+	}
+	leftFile, err := writeDiffFile(dir, "1", leftName, left)
+	if err == nil {
+		var rightFile string
+		if rightFile, err = writeDiffFile(dir, "2", rightName, right); err == nil {
+			cmd := exec.Command(diffTool[0], append(diffTool[1:], leftFile, rightFile)...)
+			cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+			t.GetApplication().Suspend(func() {
+				clearScreen()
+				err = cmd.Run()
+			})
+		}
+	}
+	os.RemoveAll(dir)
+
+	// diff tools exit with 1 when the files differ
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		if status, ok := exitErr.Sys().(syscall.WaitStatus); ok && status.ExitStatus() == 1 {
+			err = nil
+		}
+	}
+	if err != nil {
+		t.Notify(fmt.Sprintf("%s: %v", diffTool[0], err), throwing.SeverityError)
+	}
+	return true
+}
+
+// writeDiffFile names the file after the object, so the tool shows which side is which
+func writeDiffFile(dir, prefix, name, content string) (string, error) {
+	name = strings.Map(func(r rune) rune {
+		if r == '/' || r == ' ' || r == os.PathSeparator {
+			return '_'
+		}
+		return r
+	}, name)
+	path := filepath.Join(dir, prefix+"-"+name+".yaml")
+	return path, ioutil.WriteFile(path, []byte(content), 0600)
+}
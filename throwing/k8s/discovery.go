@@ -0,0 +1,129 @@
+package k8s
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/rancher/axe/throwing"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/util/homedir"
+)
+
+// discoveryTTL is how long the discovery documents on disk are used before they're fetched again
+const discoveryTTL = 10 * time.Minute
+
+// crdVersions are tried in order, v1beta1 is all older clusters serve and newer ones dropped it
+var crdVersions = []string{"v1", "v1beta1"}
+
+var unsafeHostChars = regexp.MustCompile(`[^\w.]`)
+
+// discoveryCache is shared by every view, so resources are discovered once per TTL rather than on every refresh
+var discoveryCache struct {
+	once   sync.Once
+	client *discovery.CachedDiscoveryClient
+	mapper *restmapper.DeferredDiscoveryRESTMapper
+	err    error
+}
+
+// cachedDiscovery returns the discovery client caching in ~/.axe/cache/discovery, one directory per API server
+func cachedDiscovery() (discovery.CachedDiscoveryInterface, error) {
+	discoveryCache.once.Do(func() {
+		config, err := restConfig()
+		if err != nil {
+			discoveryCache.err = err
+			return
+		}
+		host := config.Host
+		if u, err := url.Parse(host); err == nil && u.Host != "" {
+			host = u.Host
+		}
+		dir := filepath.Join(homedir.HomeDir(), ".axe", "cache", "discovery", unsafeHostChars.ReplaceAllString(host, "_"))
+		discoveryCache.client, discoveryCache.err = discovery.NewCachedDiscoveryClientForConfig(config, dir, "", discoveryTTL)
+		if discoveryCache.err == nil {
+			discoveryCache.mapper = restmapper.NewDeferredDiscoveryRESTMapper(discoveryCache.client)
+		}
+	})
+	if discoveryCache.err != nil {
+		return nil, discoveryCache.err
+	}
+	return discoveryCache.client, nil
+}
+
+// cachedResource resolves a group qualified kind to the preferred version of its group through the cached discovery
+func cachedResource(kind string) (schema.GroupVersionResource, error) {
+	if _, err := cachedDiscovery(); err != nil {
+		return schema.GroupVersionResource{}, err
+	}
+	resource, group := splitKind(kind)
+	return discoveryCache.mapper.ResourceFor(schema.GroupVersionResource{Group: group, Resource: resource})
+}
+
+/*
+watchCRDs drops the discovery cache whenever a CustomResourceDefinition comes, changes or goes, for the lifetime of axe,
+so new kinds show up without waiting out the TTL. The watch is restarted when the server closes it.
+*/
+func watchCRDs(app *throwing.AppView) {
+	for {
+		if err := watchCRDChanges(app); err != nil {
+			logrus.Debugf("crd watch: %v", err)
+		}
+		select {
+		case <-app.Context().Done():
+			return
+		case <-time.After(eventRetryPeriod):
+		}
+	}
+}
+
+func watchCRDChanges(app *throwing.AppView) error {
+	_, err := cachedDiscovery()
+	if err != nil {
+		return err
+	}
+	client, err := newDynamicClient()
+	if err != nil {
+		return err
+	}
+
+	var crds dynamic.NamespaceableResourceInterface
+	var resourceVersion string
+	for _, version := range crdVersions {
+		crds = client.Resource(schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: version, Resource: "customresourcedefinitions"})
+		list, listErr := crds.List(metav1.ListOptions{})
+		if err = listErr; err == nil {
+			resourceVersion = list.GetResourceVersion()
+			break
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	w, err := crds.Watch(metav1.ListOptions{ResourceVersion: resourceVersion})
+	if err != nil {
+		return err
+	}
+	defer w.Stop()
+	defer stopWith(app.Context(), w.Stop)()
+	for event := range w.ResultChan() {
+		switch event.Type {
+		case watch.Added, watch.Modified, watch.Deleted:
+			// Reset invalidates the discovery documents the mapper is built from as well
+			discoveryCache.mapper.Reset()
+			app.Publish(throwing.Event{Kind: k8sKind, Type: throwing.EventChanged})
+		case watch.Error:
+			return fmt.Errorf("watch failed: %v", event.Object)
+		}
+	}
+	return fmt.Errorf("watch closed")
+}
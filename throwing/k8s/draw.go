@@ -1,15 +1,20 @@
 package k8s
 
 import (
+	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/gdamore/tcell"
 	"github.com/rancher/axe/throwing"
 	"github.com/rancher/axe/throwing/datafeeder"
 	"github.com/rancher/axe/throwing/types"
+	"github.com/rancher/axe/version"
 	"github.com/urfave/cli"
+	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/util/homedir"
 )
 
 var (
@@ -22,8 +27,91 @@ var (
 		Kind:  "k8s",
 	}
 
+	helmResourceKind = types.ResourceKind{
+		Title: "Helm",
+		Kind:  helmKind,
+	}
+
+	issuesResourceKind = types.ResourceKind{
+		Title: "Issues",
+		Kind:  issuesKind,
+	}
+
+	dashboardResourceKind = types.ResourceKind{
+		Title: "Overview",
+		Kind:  dashboardKind,
+	}
+
+	eventsResourceKind = types.ResourceKind{
+		Title: "Events",
+		Kind:  eventsKind,
+	}
+
+	bookmarksResourceKind = types.ResourceKind{
+		Title: "Bookmarks",
+		Kind:  bookmarksKind,
+	}
+
+	alertsResourceKind = types.ResourceKind{
+		Title: "Alerts",
+		Kind:  alertsKind,
+	}
+
+	certificatesResourceKind = types.ResourceKind{
+		Title: "Certificates",
+		Kind:  certificatesKind,
+	}
+
+	violationsResourceKind = types.ResourceKind{
+		Title: "Violations",
+		Kind:  violationsKind,
+	}
+
+	canIResourceKind = types.ResourceKind{
+		Title: "Can I",
+		Kind:  canIKind,
+	}
+
+	subjectsResourceKind = types.ResourceKind{
+		Title: "Subjects",
+		Kind:  subjectsKind,
+	}
+
+	imagesResourceKind = types.ResourceKind{
+		Title: "Images",
+		Kind:  imagesKind,
+	}
+
+	capacityResourceKind = types.ResourceKind{
+		Title: "Capacity",
+		Kind:  capacityKind,
+	}
+
+	prometheusResourceKind = types.ResourceKind{
+		Title: "Prometheus",
+		Kind:  prometheusKind,
+	}
+
+	alertmanagerResourceKind = types.ResourceKind{
+		Title: "Alertmanager",
+		Kind:  alertmanagerKind,
+	}
+
+	jobsResourceKind = types.ResourceKind{
+		Title: "Jobs",
+		Kind:  jobsKind,
+	}
+
 	PageNav = map[rune]string{
 		'1': k8sKind,
+		'2': helmKind,
+		'3': issuesKind,
+		'4': dashboardKind,
+		'5': eventsKind,
+		'6': bookmarksKind,
+		'7': alertsKind,
+		'8': certificatesKind,
+		'9': violationsKind,
 	}
 
 	Footers = []types.ResourceView{
@@ -32,17 +120,126 @@ var (
 			Kind:  k8sKind,
 			Index: 1,
 		},
+		{
+			Title: "Helm",
+			Kind:  helmKind,
+			Index: 2,
+		},
+		{
+			Title: "Issues",
+			Kind:  issuesKind,
+			Index: 3,
+		},
+		{
+			Title: "Overview",
+			Kind:  dashboardKind,
+			Index: 4,
+		},
+		{
+			Title: "Events",
+			Kind:  eventsKind,
+			Index: 5,
+		},
+		{
+			Title: "Bookmarks",
+			Kind:  bookmarksKind,
+			Index: 6,
+		},
+		{
+			Title: "Alerts",
+			Kind:  alertsKind,
+			Index: 7,
+		},
+		{
+			Title: "Certificates",
+			Kind:  certificatesKind,
+			Index: 8,
+		},
+		{
+			Title: "Violations",
+			Kind:  violationsKind,
+			Index: 9,
+		},
+		{
+			Title: "Can I",
+			Kind:  canIKind,
+			Index: 10,
+		},
+		{
+			Title: "Subjects",
+			Kind:  subjectsKind,
+			Index: 11,
+		},
+		{
+			Title: "Images",
+			Kind:  imagesKind,
+			Index: 12,
+		},
+		{
+			Title: "Capacity",
+			Kind:  capacityKind,
+			Index: 13,
+		},
+		{
+			Title: "Prometheus",
+			Kind:  prometheusKind,
+			Index: 14,
+		},
+		{
+			Title: "Alertmanager",
+			Kind:  alertmanagerKind,
+			Index: 15,
+		},
+		{
+			Title: "Jobs",
+			Kind:  jobsKind,
+			Index: 16,
+		},
 	}
 
 	Shortcuts = [][]string{
-		{"Key g", "Get"},
+		{"Key g", "Get, Tab to query it with JSONPath"},
+		{"Key D", "Describe"},
 		{"Key e", "Edit"},
 		{"Key d", "Delete"},
-		{"Key l", "Logs"},
+		{"Key l", "Logs, of a journal unit or a file of /var/log on nodes"},
 		{"Key x", "Exec"},
+		{"Key j", "Patch console (json, merge, strategic), not available with the vim keymap"},
+		{"Key W", "Watch a single resource"},
+		{"Key M/C", "Mark an object, compare the selected one with it"},
+		{"Key H", "Revisions seen this session, Enter shows the diff"},
+		{"Key B", "Bookmark the selected object, 6 lists the bookmarks"},
+		{"Key t", "Trigger job (cronjobs), edit taints (nodes)"},
+		{"Key R", "Renew now (cert-manager certificates on the certificates page), run again as a new job with the same spec (jobs)"},
+		{"Key P", "Toggle the SECURITY column of pods: privileged, host namespaces, missing securityContext settings"},
+		{"Key K", "Probe DNS, ports and HTTP of the service or pod from a short-lived debug pod (services, pods)"},
+		{"Key U", "CPU and memory of the pod over the last hour, with prometheus in the config"},
+		{"Key s", "Scan the image for vulnerabilities, trivy or imageScanner in the config (images page)"},
+		{"Key a", "Ask whether a verb on a resource is allowed, for you or another subject (can-i page), add a PromQL query (prometheus page)"},
+		{"Key s/u", "Silence the alert, expire its silences (alertmanager page)"},
+		{"Key v", "View decoded (secrets), browse data (configmaps), values (helm), scale events (hpa), endpoints (services), peers (networkpolicies), permissions (serviceaccounts, subjects), scan result (images), graph (prometheus)"},
+		{"Key h/b/u", "History, rollback, uninstall (helm)"},
+		{"Key o", "X-ray ownership tree (deployments), mounting pods (pvc)"},
+		{"Key p/c", "Bound volume (pvc), bound claim (pv)"},
+		{"Key c", "Containers (pods), then l/x for logs and exec of one container"},
+		{"Key w", "Open in browser (ingresses)"},
+		{"Key u", "Port-forward and curl (services)"},
+		{"Key i", "Set image (deployments, daemonsets, statefulsets)"},
+		{"Key m", "Edit min/max replicas (hpa)"},
+		{"Key f/F", "Follow, filter (events)"},
 		{"key r", "Refresh"},
 		{"Key /", "Search"},
+		{"Key :", "Go to a kind, short names like po, deploy, svc, cm work"},
+		{"Ctrl p", "Find in all loaded tables"},
+		{"Ctrl s", "Toggle the detail pane"},
+		{"Ctrl t/w", "Open, close a tab"},
+		{"Ctrl o", "Page history"},
+		{"Tab", "Next tab, Shift Tab for the previous one"},
 		{"Key q", "quit to root page"},
+		{"Key [/]", "Previous, next page of the footer"},
+		{"~/.axe/config.yaml", "Remap any key under keys:, e.g. get: y or pods.logs: L"},
+		{"alerts:", "Rules in the config raising alerts, 7 lists what matches now"},
+		{"j/k gg/G ^d/^u", "Move, jump and page with the vim keymap"},
 	}
 
 	ViewMap = map[string]types.View{
@@ -50,63 +247,139 @@ var (
 			Actions: []types.Action{
 				{
 					Name:        "get",
-					Shortcut:    'g',
+					Shortcut:    "g",
 					Description: "get a resource",
 				},
 				{
 					Name:        "edit",
-					Shortcut:    'e',
+					Shortcut:    "e",
 					Description: "edit a resource",
 				},
 				{
 					Name:        "delete",
-					Shortcut:    'd',
+					Shortcut:    "d",
 					Description: "delete a resource",
 				},
 			},
 			Kind:   k8sResourceKind,
 			Feeder: datafeeder.NewDataFeeder(RefreshResourceKind),
 		},
+		helmKind: {
+			Actions: actionsForKind(helmKind),
+			Kind:    helmResourceKind,
+			Feeder:  datafeeder.NewDataFeeder(RefreshHelmReleases),
+		},
+		issuesKind: {
+			Kind:   issuesResourceKind,
+			Feeder: datafeeder.NewDataFeeder(RefreshIssues).SetRowColor(issueRowColor),
+		},
+		dashboardKind: {
+			Kind:   dashboardResourceKind,
+			Feeder: datafeeder.NewDataFeeder(RefreshDashboard).SetRowColor(dashboardRowColor),
+		},
+		eventsKind: {
+			Actions: actionsForKind(eventsKind),
+			Kind:    eventsResourceKind,
+			Feeder:  datafeeder.NewDataFeeder(RefreshClusterEvents).SetRowColor(eventRowColor),
+		},
+		bookmarksKind: {
+			Actions: actionsForKind(bookmarksKind),
+			Kind:    bookmarksResourceKind,
+			Feeder:  datafeeder.NewDataFeeder(RefreshBookmarks),
+		},
+		alertsKind: {
+			Kind:   alertsResourceKind,
+			Feeder: datafeeder.NewDataFeeder(RefreshAlerts).SetRowColor(alertRowColor),
+		},
+		certificatesKind: {
+			Actions: actionsForKind(certificatesKind),
+			Kind:    certificatesResourceKind,
+			Feeder:  datafeeder.NewDataFeeder(RefreshCertificates).SetRowColor(certificateRowColor),
+		},
+		violationsKind: {
+			Kind:   violationsResourceKind,
+			Feeder: datafeeder.NewDataFeeder(RefreshViolations).SetRowColor(violationRowColor),
+		},
+		canIKind: {
+			Actions: actionsForKind(canIKind),
+			Kind:    canIResourceKind,
+			Feeder:  datafeeder.NewDataFeeder(RefreshAccessChecks).SetRowColor(accessCheckRowColor),
+		},
+		subjectsKind: {
+			Actions: actionsForKind(subjectsKind),
+			Kind:    subjectsResourceKind,
+			Feeder:  datafeeder.NewDataFeeder(RefreshSubjects),
+		},
+		imagesKind: {
+			Actions: actionsForKind(imagesKind),
+			Kind:    imagesResourceKind,
+			Feeder:  datafeeder.NewDataFeeder(RefreshImages).SetRowColor(imageRowColor),
+		},
+		capacityKind: {
+			Kind:   capacityResourceKind,
+			Feeder: datafeeder.NewDataFeeder(RefreshCapacity).SetRowColor(capacityRowColor),
+		},
+		prometheusKind: {
+			Actions: actionsForKind(prometheusKind),
+			Kind:    prometheusResourceKind,
+			Feeder:  datafeeder.NewDataFeeder(RefreshPrometheus),
+		},
+		alertmanagerKind: {
+			Actions: actionsForKind(alertmanagerKind),
+			Kind:    alertmanagerResourceKind,
+			Feeder:  datafeeder.NewDataFeeder(RefreshAlertmanager).SetRowColor(alertmanagerRowColor),
+		},
+		jobsKind: {
+			Actions: actionsForKind(jobsKind),
+			Kind:    jobsResourceKind,
+			Feeder:  datafeeder.NewDataFeeder(RefreshJobs).SetRowColor(jobRowColor).SetAge(ageForKind(jobsKind)),
+		},
 	}
 
 	tableEventHandler = func(t *throwing.TableView) func(event *tcell.EventKey) *tcell.EventKey {
 		return func(event *tcell.EventKey) *tcell.EventKey {
 			switch event.Key() {
 			case tcell.KeyEnter:
-				if err := resourceView(t); err != nil {
-					t.UpdateStatus(err.Error(), true)
+				switch t.GetResourceKind() {
+				case helmKind:
+					helmHistoryView(t)
+				case issuesKind:
+					viewIssue(t)
+				case dashboardKind:
+					viewDashboardSection(t)
+				case eventsKind:
+					// events have nothing to drill down into
+				case canIKind:
+					askAccess(t)
+				case subjectsKind:
+					subjectPermissions(t)
+				case imagesKind:
+					showImageScan(t)
+				case capacityKind:
+					capacityPods(t)
+				case prometheusKind:
+					graphPromQuery(t)
+				case jobsKind:
+					jobPods(t)
+				case bookmarksKind, alertsKind, certificatesKind, violationsKind, alertmanagerKind:
+					// these pages have the NAMESPACE, NAME and KIND columns of bookmarks
+					openBookmark(t)
+				default:
+					if resourcePages[t.GetResourceKind()] {
+						break
+					}
+					if err := resourceView(t); err != nil {
+						t.Notify(err.Error(), throwing.SeverityError)
+					}
 				}
-			case tcell.KeyRune:
-				switch event.Rune() {
-				case '/':
-					t.ShowSearch()
-				case 'r':
-					t.Refresh()
+			default:
+				actions := rootActions
+				if resourcePages[t.GetResourceKind()] {
+					actions = genericActions
+				}
+				if !runKindAction(t, event) {
+					runAction(t, actions, event)
 				}
-			}
-			return event
-		}
-	}
-
-	itemEventHandler = func(t *throwing.TableView) func(event *tcell.EventKey) *tcell.EventKey {
-		return func(event *tcell.EventKey) *tcell.EventKey {
-			switch event.Rune() {
-			case 'g':
-				get(t)
-			case 'e':
-				edit(t)
-			case 'd':
-				delete(t)
-			case 'x':
-				execute(t)
-			case 'l':
-				logs(t)
-			case 'q':
-				t.RootPage()
-			case 'r':
-				t.Refresh()
-			case '/':
-				t.ShowSearch()
 			}
 			return event
 		}
@@ -118,25 +391,164 @@ var (
 		ViewMap:   ViewMap,
 		PageNav:   PageNav,
 		Footers:   Footers,
+		Detail:    rowDetail,
 	}
 )
 
+func itemEventHandler(t *throwing.TableView) func(event *tcell.EventKey) *tcell.EventKey {
+	return func(event *tcell.EventKey) *tcell.EventKey {
+		if !runKindAction(t, event) {
+			runAction(t, genericActions, event)
+		}
+		return event
+	}
+}
+
+/*
+configure applies the connection flags and the config file and loads the pages, both Start and Kinds need them.
+c is the context of the app, the global flags are its flags.
+*/
+func configure(c *cli.Context) (config, discovery.CachedDiscoveryInterface, error) {
+	// KUBECONFIG may be a list of files, client-go and kubectl read it themselves
+	if kubeconfig := c.String("kubeconfig"); kubeconfig != os.Getenv("KUBECONFIG") {
+		connection.kubeconfig = kubeconfig
+	}
+	connection.context = c.String("context")
+	connection.namespace = c.String("namespace")
+
+	impersonation.UserName = c.String("as")
+	impersonation.Groups = c.StringSlice("as-group")
+	impersonation.uid = c.String("as-uid")
+	if impersonation.UserName == "" && (len(impersonation.Groups) > 0 || impersonation.uid != "") {
+		return config{}, nil, fmt.Errorf("--as-group and --as-uid need --as")
+	}
+
+	rateLimit.qps = float32(c.Float64("qps"))
+	rateLimit.burst = c.Int("burst")
+
+	cfg, err := loadConfig(c.String("config"))
+	if err != nil {
+		return cfg, nil, err
+	}
+	for alias, kind := range cfg.Aliases {
+		kindAliases[strings.ToLower(alias)] = kind
+	}
+	d, err := cachedDiscovery()
+	if err != nil {
+		return cfg, nil, err
+	}
+	return cfg, d, configurePages(d, cfg.Footer)
+}
+
 func Start(c *cli.Context) error {
-	kubeconfig := c.String("kubeconfig")
-	os.Setenv("KUBECONFIG", kubeconfig)
+	cfg, d, err := configure(c)
+	if err != nil {
+		return err
+	}
+	target, err := parseStartTarget(c.Args())
+	if err != nil {
+		return err
+	}
+	if target.namespace != "" {
+		connection.namespace = target.namespace
+	}
 
-	restConfig, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+	config, err := restConfig()
 	if err != nil {
 		return err
 	}
-	clientset := kubernetes.NewForConfigOrDie(restConfig)
+	clientset := kubernetes.NewForConfigOrDie(config)
+	readOnly = c.Bool("read-only") || cfg.ReadOnly
+	nodeSSH = cfg.SSH
+	diffTool = strings.Fields(cfg.DiffTool)
+	imageScanner = strings.Fields(cfg.ImageScanner)
+	prometheusURL = cfg.Prometheus
+	alertmanagerURL = cfg.Alertmanager
+	if cfg.NetDebugImage != "" {
+		netDebugImage = cfg.NetDebugImage
+	}
+	if cfg.PodSecurity {
+		podSecurity = 1
+	}
+	persistCommandHistory = cfg.PersistCommandHistory
+	if cfg.LogLines != nil {
+		maxLogLines = *cfg.LogLines
+	}
+	if err := configureTmux(c.Bool("tmux"), cfg.Tmux); err != nil {
+		return err
+	}
+	if alertRules, err = compileAlertRules(d, cfg.Alerts); err != nil {
+		return err
+	}
+
+	if c.Bool("dashboard") {
+		drawer.RootPage = dashboardKind
+	}
+	var openStartPage func(root *throwing.TableView)
+	if target.kind != "" {
+		if openStartPage, err = startPage(d, target); err != nil {
+			return err
+		}
+	}
 
-	signals := map[string]chan struct{}{
-		k8sKind: make(chan struct{}, 0),
+	app := throwing.NewAppView(clientset, drawer, tableEventHandler)
+	app.Go(func() { streamEvents(clientset, app) })
+	app.Go(func() { watchCRDs(app) })
+	if len(alertRules) > 0 {
+		app.Go(func() { watchAlerts(app, alertRules) })
+	}
+	context, err := currentContext()
+	if err != nil {
+		return err
+	}
+	accent, err := cfg.accent(context)
+	if err != nil {
+		return err
+	}
+	app.SetAccent(accent)
+	app.SetIdentity(identity())
+	app.SetVersion(version.VERSION)
+	keymap := c.String("keymap")
+	if keymap == "" {
+		keymap = cfg.Keymap
+	}
+	if err := app.SetKeymap(keymap); err != nil {
+		return err
+	}
+	app.SetServeAddress(c.String("serve"))
+	app.SetProfileAddress(c.String("pprof"))
+	app.SetRecordFile(c.String("record"))
+	app.SetPlain(c.Bool("plain"))
+	app.SetMaxRefreshes(c.Int("max-refreshes"))
+	if cfg.MaxCellWidth != nil {
+		app.SetMaxCellWidth(*cfg.MaxCellWidth)
+	}
+	app.SetASCII(c.Bool("ascii") || !throwing.UnicodeLocale())
+	app.SetStateFile(filepath.Join(homedir.HomeDir(), ".axe", "state.json"))
+	restoreEventFilter(app.StateValue(eventFilterState))
+	restoreBookmarks(app.StateValue(bookmarksState))
+	restoreCommandHistory(app.StateValue(commandHistoryState))
+	restoreNamespaceScopes(app.StateValue(namespaceScopesState))
+	if openStartPage != nil {
+		app.SetStartPage(openStartPage)
+	}
+	conflicts, err := setKeyBindings(app, cfg.Keys)
+	if err != nil {
+		return err
+	}
+	// the menus of the footer pages were built before the bindings and the access of the user were known
+	checkMenus = true
+	menuApp, menuViews = app, drawer.ViewMap
+	for kind, view := range drawer.ViewMap {
+		if kind != k8sKind {
+			view.Actions = actionsForKind(kind)
+			drawer.ViewMap[kind] = view
+		}
 	}
-	app := throwing.NewAppView(clientset, drawer, tableEventHandler, signals)
 	if err := app.Init(); err != nil {
 		return err
 	}
+	lifetime = app.Context()
+	reportKeyConflicts(app, conflicts)
 	return app.Run()
 }
@@ -0,0 +1,244 @@
+package k8s
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gdamore/tcell"
+	"github.com/rancher/axe/throwing"
+	"github.com/rancher/axe/throwing/datafeeder"
+	"github.com/rivo/tview"
+	"github.com/sirupsen/logrus"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	eventsKind       = "clusterevents"
+	eventBufferSize  = 1000
+	eventRetryPeriod = 5 * time.Second
+)
+
+const eventFilterState = "events.filter"
+
+// eventFilter narrows the event stream, empty fields match everything. It's saved with the session state
+type eventFilter struct {
+	Namespace string `json:"namespace,omitempty"`
+	Kind      string `json:"kind,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+	Type      string `json:"type,omitempty"`
+}
+
+func (f eventFilter) matches(e *v1.Event) bool {
+	return (f.Namespace == "" || e.Namespace == f.Namespace) &&
+		(f.Kind == "" || strings.EqualFold(e.InvolvedObject.Kind, f.Kind)) &&
+		(f.Reason == "" || strings.Contains(strings.ToLower(e.Reason), strings.ToLower(f.Reason))) &&
+		(f.Type == "" || strings.EqualFold(e.Type, f.Type))
+}
+
+// restoreEventFilter applies the filter of the last session
+func restoreEventFilter(saved string) {
+	if saved == "" {
+		return
+	}
+	var filter eventFilter
+	if err := json.Unmarshal([]byte(saved), &filter); err != nil {
+		logrus.Debugf("ignoring saved event filter: %v", err)
+		return
+	}
+	eventStream.Lock()
+	eventStream.filter = filter
+	eventStream.Unlock()
+}
+
+func setEventFilter(t *throwing.TableView, filter eventFilter) {
+	eventStream.Lock()
+	eventStream.filter = filter
+	eventStream.Unlock()
+	if saved, err := json.Marshal(filter); err == nil {
+		t.SetStateValue(eventFilterState, string(saved))
+	}
+	t.SwitchToRootPage()
+	t.Refresh()
+}
+
+// eventStream keeps the latest events seen by the cluster wide watch, keyed by uid so repeated events update in place
+var eventStream = struct {
+	sync.Mutex
+	events map[k8stypes.UID]*v1.Event
+	filter eventFilter
+}{
+	events: map[k8stypes.UID]*v1.Event{},
+}
+
+func eventTime(e *v1.Event) time.Time {
+	if !e.LastTimestamp.IsZero() {
+		return e.LastTimestamp.Time
+	}
+	if !e.EventTime.IsZero() {
+		return e.EventTime.Time
+	}
+	return e.CreationTimestamp.Time
+}
+
+func storeEvent(e *v1.Event, deleted bool) {
+	eventStream.Lock()
+	defer eventStream.Unlock()
+	if deleted {
+		delete(eventStream.events, e.UID)
+		return
+	}
+	eventStream.events[e.UID] = e
+	if len(eventStream.events) <= eventBufferSize {
+		return
+	}
+	var oldest *v1.Event
+	for _, o := range eventStream.events {
+		if oldest == nil || eventTime(o).Before(eventTime(oldest)) {
+			oldest = o
+		}
+	}
+	delete(eventStream.events, oldest.UID)
+}
+
+/*
+streamEvents lists and then watches events in all namespaces for the lifetime of axe.
+Every change is published for the events page to redraw, the watch is restarted when the server closes it.
+*/
+func streamEvents(clientset *kubernetes.Clientset, app *throwing.AppView) {
+	for {
+		if err := watchEvents(clientset, app); err != nil {
+			logrus.Debugf("event stream: %v", err)
+		}
+		select {
+		case <-app.Context().Done():
+			return
+		case <-time.After(eventRetryPeriod):
+		}
+	}
+}
+
+func watchEvents(clientset *kubernetes.Clientset, app *throwing.AppView) error {
+	list, err := clientset.CoreV1().Events(v1.NamespaceAll).List(metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for i := range list.Items {
+		storeEvent(&list.Items[i], false)
+	}
+	app.Publish(throwing.Event{Kind: eventsKind, Type: throwing.EventRefresh})
+
+	w, err := clientset.CoreV1().Events(v1.NamespaceAll).Watch(metav1.ListOptions{
+		ResourceVersion: list.ResourceVersion,
+	})
+	if err != nil {
+		return err
+	}
+	defer w.Stop()
+	defer stopWith(app.Context(), w.Stop)()
+	for event := range w.ResultChan() {
+		e, ok := event.Object.(*v1.Event)
+		if !ok {
+			if event.Type == watch.Error {
+				return fmt.Errorf("watch failed: %v", event.Object)
+			}
+			continue
+		}
+		storeEvent(e, event.Type == watch.Deleted)
+		app.Publish(throwing.Event{Kind: eventsKind, Type: throwing.EventChanged, Namespace: e.Namespace, Name: e.Name})
+	}
+	return fmt.Errorf("watch closed")
+}
+
+// RefreshClusterEvents renders the buffered events oldest first so the newest end up at the bottom
+func RefreshClusterEvents(b *bytes.Buffer) error {
+	eventStream.Lock()
+	var events []*v1.Event
+	for _, e := range eventStream.events {
+		if eventStream.filter.matches(e) {
+			events = append(events, e)
+		}
+	}
+	eventStream.Unlock()
+
+	sort.Slice(events, func(i, j int) bool {
+		return eventTime(events[i]).Before(eventTime(events[j]))
+	})
+
+	var rows [][]string
+	for _, e := range events {
+		rows = append(rows, []string{
+			e.Namespace,
+			e.InvolvedObject.Name,
+			e.InvolvedObject.Kind,
+			e.Type,
+			e.Reason,
+			strconv.Itoa(int(e.Count)),
+			eventTime(e).Format("15:04:05"),
+			e.Message,
+		})
+	}
+	writeTable(b, []string{"NAMESPACE", "NAME", "KIND", "TYPE", "REASON", "COUNT", "LAST SEEN", "MESSAGE"}, rows)
+	return nil
+}
+
+func eventRowColor(header, row datafeeder.Row) tcell.Color {
+	if columnOrEmpty(header, row, "TYPE") == v1.EventTypeWarning {
+		return tcell.ColorRed
+	}
+	return tcell.ColorDefault
+}
+
+func toggleEventFollow(t *throwing.TableView) {
+	t.SetFollow(!t.Following())
+	if t.Following() {
+		t.Notify("following new events", throwing.SeverityInfo)
+	} else {
+		t.Notify("stopped following events", throwing.SeverityInfo)
+	}
+	t.Refresh()
+}
+
+// editEventFilter asks for the namespace, kind, reason and type to narrow the stream to
+func editEventFilter(t *throwing.TableView) {
+	eventStream.Lock()
+	filter := eventStream.filter
+	eventStream.Unlock()
+
+	form := tview.NewForm()
+	form.SetBorder(true)
+	form.SetTitle("Filter events")
+	form.SetBackgroundColor(tcell.ColorBlack)
+	form.SetFieldBackgroundColor(tcell.ColorGray)
+	namespace := tview.NewInputField().SetLabel("namespace").SetText(filter.Namespace).SetFieldWidth(30)
+	kind := tview.NewInputField().SetLabel("kind").SetText(filter.Kind).SetFieldWidth(30)
+	reason := tview.NewInputField().SetLabel("reason").SetText(filter.Reason).SetFieldWidth(30)
+	eventType := tview.NewInputField().SetLabel("type").SetText(filter.Type).SetFieldWidth(30)
+	form.AddFormItem(namespace).AddFormItem(kind).AddFormItem(reason).AddFormItem(eventType)
+
+	form.AddButton("Apply", func() {
+		setEventFilter(t, eventFilter{
+			Namespace: strings.TrimSpace(namespace.GetText()),
+			Kind:      strings.TrimSpace(kind.GetText()),
+			Reason:    strings.TrimSpace(reason.GetText()),
+			Type:      strings.TrimSpace(eventType.GetText()),
+		})
+	})
+	form.AddButton("Clear", func() {
+		setEventFilter(t, eventFilter{})
+	})
+	form.SetCancelFunc(func() {
+		t.BackPage()
+	})
+
+	t.InsertDialog("filter", t.GetCurrentPrimitive(), form)
+}
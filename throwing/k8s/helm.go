@@ -0,0 +1,265 @@
+package k8s
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+	"github.com/rancher/axe/throwing"
+	"github.com/rancher/axe/throwing/datafeeder"
+	"github.com/rancher/axe/throwing/types"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	helmKind          = "helm"
+	helmHistoryKind   = "helmhistory"
+	helmReleaseType   = "helm.sh/release.v1"
+	helmOwnerSelector = "owner=helm"
+)
+
+var gzipMagic = []byte{0x1f, 0x8b, 0x08}
+
+// helmRelease is the part of a helm 3 release record axe needs
+type helmRelease struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace"`
+	Version   int    `json:"version"`
+	Info      struct {
+		Status       string    `json:"status"`
+		Description  string    `json:"description"`
+		LastDeployed time.Time `json:"last_deployed"`
+	} `json:"info"`
+	Chart struct {
+		Metadata struct {
+			Name       string `json:"name"`
+			Version    string `json:"version"`
+			AppVersion string `json:"appVersion"`
+		} `json:"metadata"`
+	} `json:"chart"`
+	Config map[string]interface{} `json:"config"`
+}
+
+func (r helmRelease) chart() string {
+	return fmt.Sprintf("%s-%s", r.Chart.Metadata.Name, r.Chart.Metadata.Version)
+}
+
+// decodeHelmRelease undoes helm's base64 and gzip encoding of the release secret
+func decodeHelmRelease(data []byte) (helmRelease, error) {
+	var release helmRelease
+	raw, err := base64.StdEncoding.DecodeString(string(data))
+	if err != nil {
+		return release, err
+	}
+	if bytes.HasPrefix(raw, gzipMagic) {
+		r, err := gzip.NewReader(bytes.NewReader(raw))
+		if err != nil {
+			return release, err
+		}
+		defer r.Close()
+		if raw, err = ioutil.ReadAll(r); err != nil {
+			return release, err
+		}
+	}
+	return release, json.Unmarshal(raw, &release)
+}
+
+func listHelmReleases(clientset *kubernetes.Clientset, namespace, selector string) ([]helmRelease, error) {
+	secrets, err := clientset.CoreV1().Secrets(namespace).List(metav1.ListOptions{
+		LabelSelector: selector,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var releases []helmRelease
+	for _, secret := range secrets.Items {
+		if secret.Type != helmReleaseType {
+			continue
+		}
+		release, err := decodeHelmRelease(secret.Data["release"])
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode helm release %s/%s: %v", secret.Namespace, secret.Name, err)
+		}
+		releases = append(releases, release)
+	}
+	return releases, nil
+}
+
+func getHelmRelease(clientset *kubernetes.Clientset, namespace, name string, revision int) (helmRelease, error) {
+	secret, err := clientset.CoreV1().Secrets(namespace).Get(fmt.Sprintf("sh.helm.release.v1.%s.v%d", name, revision), metav1.GetOptions{})
+	if err != nil {
+		return helmRelease{}, err
+	}
+	return decodeHelmRelease(secret.Data["release"])
+}
+
+// RefreshHelmReleases lists the latest revision of every helm 3 release
+func RefreshHelmReleases(b *bytes.Buffer) error {
+	clientset, err := newClientset()
+	if err != nil {
+		return err
+	}
+	releases, err := listHelmReleases(clientset, v1.NamespaceAll, helmOwnerSelector)
+	if err != nil {
+		return err
+	}
+
+	latest := map[string]helmRelease{}
+	for _, r := range releases {
+		key := r.Namespace + "/" + r.Name
+		if l, ok := latest[key]; !ok || r.Version > l.Version {
+			latest[key] = r
+		}
+	}
+	var keys []string
+	for k := range latest {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var rows [][]string
+	for _, k := range keys {
+		r := latest[k]
+		rows = append(rows, []string{
+			r.Namespace,
+			r.Name,
+			strconv.Itoa(r.Version),
+			r.chart(),
+			r.Chart.Metadata.AppVersion,
+			r.Info.Status,
+			r.Info.LastDeployed.Format(time.RFC3339),
+		})
+	}
+	writeTable(b, []string{"NAMESPACE", "NAME", "REVISION", "CHART", "APP VERSION", "STATUS", "UPDATED"}, rows)
+	return nil
+}
+
+type helmHistory struct {
+	namespace, name string
+}
+
+func (h helmHistory) refresh(b *bytes.Buffer) error {
+	clientset, err := newClientset()
+	if err != nil {
+		return err
+	}
+	releases, err := listHelmReleases(clientset, h.namespace, helmOwnerSelector+",name="+h.name)
+	if err != nil {
+		return err
+	}
+	sort.Slice(releases, func(i, j int) bool {
+		return releases[i].Version > releases[j].Version
+	})
+
+	var rows [][]string
+	for _, r := range releases {
+		rows = append(rows, []string{
+			r.Namespace,
+			r.Name,
+			strconv.Itoa(r.Version),
+			r.Info.LastDeployed.Format(time.RFC3339),
+			r.Info.Status,
+			r.chart(),
+			r.Chart.Metadata.AppVersion,
+			r.Info.Description,
+		})
+	}
+	writeTable(b, []string{"NAMESPACE", "NAME", "REVISION", "UPDATED", "STATUS", "CHART", "APP VERSION", "DESCRIPTION"}, rows)
+	return nil
+}
+
+// selectedRevision reads the REVISION column both helm tables have
+func selectedRevision(t *throwing.TableView) (int, error) {
+	return strconv.Atoi(selectedColumn(t, "REVISION"))
+}
+
+func helmHistoryView(t *throwing.TableView) {
+	namespace, name := getNamespaceAndName(t)
+	h := helmHistory{namespace: namespace, name: name}
+	openTable(t, types.ResourceKind{
+		Title: fmt.Sprintf("helm history - (%s/%s)", namespace, name),
+		Kind:  helmHistoryKind,
+	}, datafeeder.NewDataFeeder(h.refresh))
+}
+
+func helmValues(t *throwing.TableView) {
+	namespace, name := getNamespaceAndName(t)
+	revision, err := selectedRevision(t)
+	if err != nil {
+		t.Notify(err.Error(), throwing.SeverityError)
+		return
+	}
+	release, err := getHelmRelease(t.GetClientSet(), namespace, name, revision)
+	if err != nil {
+		t.Notify(err.Error(), throwing.SeverityError)
+		return
+	}
+	values, err := yaml.Marshal(release.Config)
+	if err != nil {
+		t.Notify(err.Error(), throwing.SeverityError)
+		return
+	}
+
+	showText(t, "values", fmt.Sprintf("values - (%s/%s revision %d)", namespace, name, revision), string(values), languageYAML)
+}
+
+// helmRollback rolls back to the selected revision in the history view, or to the previous one in the release list
+func helmRollback(t *throwing.TableView) {
+	namespace, name := getNamespaceAndName(t)
+	revision, err := selectedRevision(t)
+	if err != nil {
+		t.Notify(err.Error(), throwing.SeverityError)
+		return
+	}
+	if t.GetResourceKind() == helmKind {
+		revision--
+	}
+	if revision < 1 {
+		t.Notify(fmt.Sprintf("release %s has no previous revision", name), throwing.SeverityWarning)
+		return
+	}
+	if !canI(t, "create", "secrets", "", namespace) {
+		return
+	}
+	t.Confirm(fmt.Sprintf("Do you want to roll back %s to revision %d?", name, revision), "rollback", func() {
+		runHelm(t, fmt.Sprintf("%s rolled back to revision %d", name, revision), "rollback", name, strconv.Itoa(revision), "-n", namespace)
+	})
+}
+
+func helmUninstall(t *throwing.TableView) {
+	namespace, name := getNamespaceAndName(t)
+	if !canI(t, "delete", "secrets", "", namespace) {
+		return
+	}
+	t.Confirm(fmt.Sprintf("Do you want to uninstall %s?", name), "uninstall", func() {
+		runHelm(t, fmt.Sprintf("%s uninstalled", name), "uninstall", name, "-n", namespace)
+	})
+}
+
+func runHelm(t *throwing.TableView, done string, args ...string) {
+	cmd := helm(args...)
+	errB := &strings.Builder{}
+	cmd.Stderr = errB
+	t.Go(func() {
+		if err := cmd.Run(); err != nil {
+			msg := errB.String()
+			if msg == "" {
+				msg = err.Error()
+			}
+			t.Notify(msg, throwing.SeverityError)
+			return
+		}
+		t.Notify(done, throwing.SeverityInfo)
+		t.Refresh()
+	})
+}
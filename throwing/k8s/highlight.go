@@ -0,0 +1,141 @@
+package k8s
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/alecthomas/chroma"
+	"github.com/alecthomas/chroma/lexers/d"
+	"github.com/alecthomas/chroma/lexers/i"
+	"github.com/alecthomas/chroma/lexers/j"
+	"github.com/alecthomas/chroma/lexers/y"
+	"github.com/gdamore/tcell"
+	"github.com/rancher/axe/throwing"
+	"github.com/rivo/tview"
+)
+
+const (
+	languageYAML       = "yaml"
+	languageJSON       = "json"
+	languageProperties = "properties"
+	languageDiff       = "diff"
+)
+
+var languageLexers = map[string]chroma.Lexer{
+	languageYAML:       y.YAML,
+	languageJSON:       j.JSON,
+	languageProperties: i.Ini,
+	languageDiff:       d.Diff,
+}
+
+// detectLanguage guesses the format of a value from its file name first and its content second
+func detectLanguage(name, content string) string {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".yaml", ".yml":
+		return languageYAML
+	case ".json":
+		return languageJSON
+	case ".properties", ".ini", ".conf", ".cfg", ".env":
+		return languageProperties
+	case ".diff", ".patch":
+		return languageDiff
+	}
+	trimmed := strings.TrimSpace(content)
+	switch {
+	case strings.HasPrefix(trimmed, "{"), strings.HasPrefix(trimmed, "["):
+		return languageJSON
+	case strings.HasPrefix(trimmed, "---"), strings.Contains(trimmed, ": "):
+		return languageYAML
+	case strings.Contains(trimmed, "="):
+		return languageProperties
+	}
+	return ""
+}
+
+func tokenColor(tokenType chroma.TokenType) string {
+	switch {
+	case tokenType.InCategory(chroma.Comment):
+		return "gray"
+	case tokenType == chroma.NameTag, tokenType == chroma.NameAttribute, tokenType.InSubCategory(chroma.NameVariable):
+		return "aqua"
+	case tokenType.InSubCategory(chroma.LiteralString):
+		return "green"
+	case tokenType.InSubCategory(chroma.LiteralNumber):
+		return "purple"
+	case tokenType.InCategory(chroma.Keyword):
+		return "orange"
+	case tokenType == chroma.GenericInserted:
+		return "green"
+	case tokenType == chroma.GenericDeleted:
+		return "red"
+	case tokenType == chroma.GenericSubheading, tokenType == chroma.GenericHeading:
+		return "aqua"
+	}
+	return ""
+}
+
+// highlight returns content with tview color tags, unknown languages are only escaped
+func highlight(content, language string) string {
+	lexer, ok := languageLexers[language]
+	if !ok {
+		return tview.Escape(content)
+	}
+	it, err := lexer.Tokenise(nil, content)
+	if err != nil {
+		return tview.Escape(content)
+	}
+
+	b := &strings.Builder{}
+	for token := it(); token != chroma.EOF; token = it() {
+		color := tokenColor(token.Type)
+		if color == "" {
+			b.WriteString(tview.Escape(token.Value))
+			continue
+		}
+		// tag every line on its own so line numbers can be put in front of them
+		lines := strings.Split(token.Value, "\n")
+		for i, line := range lines {
+			if i > 0 {
+				b.WriteString("\n")
+			}
+			if line != "" {
+				fmt.Fprintf(b, "[%s]%s[white]", color, tview.Escape(line))
+			}
+		}
+	}
+	return b.String()
+}
+
+// withLineNumbers prefixes every line of text with its number in gray
+func withLineNumbers(text string) string {
+	lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+	width := len(fmt.Sprint(len(lines)))
+	b := &strings.Builder{}
+	for i, line := range lines {
+		fmt.Fprintf(b, "[gray]%*d[white] %s\n", width, i+1, line)
+	}
+	return b.String()
+}
+
+// textBox is the bordered, highlighted and numbered view behind showText
+func textBox(t *throwing.TableView, title, content, language string) *tview.TextView {
+	box := tview.NewTextView()
+	box.SetBorder(true)
+	box.SetTitle(title)
+	box.SetTitleColor(tcell.ColorPurple)
+	box.SetDynamicColors(true).SetBackgroundColor(tcell.ColorBlack)
+	box.SetText(withLineNumbers(highlight(content, language)))
+	box.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEscape {
+			t.SwitchToRootPage()
+		}
+	})
+	return box
+}
+
+// showText opens a page with content highlighted as language and numbered, it's shared by get, describe and diff
+func showText(t *throwing.TableView, page, title, content, language string) {
+	newpage := tview.NewPages().AddPage(page, textBox(t, title, content, language), true, true)
+	t.SwitchPage(t.GetCurrentPage(), newpage)
+}
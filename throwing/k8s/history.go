@@ -0,0 +1,170 @@
+package k8s
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gdamore/tcell"
+	"github.com/ghodss/yaml"
+	"github.com/rancher/axe/throwing"
+	"github.com/rivo/tview"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const historySize = 500
+
+// revision is one resourceVersion of an object seen during the session
+type revision struct {
+	ref             objectRef
+	resourceVersion string
+	observed        time.Time
+	content         string
+}
+
+// history is a ring buffer of the revisions seen by get, watch, compare and the table refreshes, the oldest are dropped first
+var history = struct {
+	sync.Mutex
+	revisions []revision
+	next      int
+	// latest is the last resourceVersion recorded per object, it outlives the ring so an unchanged object isn't recorded again
+	latest map[objectRef]string
+}{
+	latest: map[objectRef]string{},
+}
+
+// recordRevision remembers content as a revision of ref unless its resourceVersion was already seen last
+func recordRevision(ref objectRef, content string) {
+	var meta struct {
+		Metadata struct {
+			ResourceVersion string `json:"resourceVersion"`
+		} `json:"metadata"`
+	}
+	if err := yaml.Unmarshal([]byte(content), &meta); err != nil || meta.Metadata.ResourceVersion == "" {
+		return
+	}
+	storeRevision(ref, meta.Metadata.ResourceVersion, func() (string, error) {
+		return content, nil
+	})
+}
+
+// recordObject remembers obj as a revision of ref, it's only marshalled when its resourceVersion is new
+func recordObject(ref objectRef, obj *unstructured.Unstructured) {
+	if obj.GetResourceVersion() == "" {
+		return
+	}
+	storeRevision(ref, obj.GetResourceVersion(), func() (string, error) {
+		out, err := yaml.Marshal(obj.Object)
+		return string(out), err
+	})
+}
+
+func storeRevision(ref objectRef, resourceVersion string, content func() (string, error)) {
+	history.Lock()
+	defer history.Unlock()
+	if history.latest[ref] == resourceVersion {
+		return
+	}
+	out, err := content()
+	if err != nil {
+		return
+	}
+	history.latest[ref] = resourceVersion
+
+	r := revision{
+		ref:             ref,
+		resourceVersion: resourceVersion,
+		observed:        time.Now(),
+		content:         out,
+	}
+	if len(history.revisions) < historySize {
+		history.revisions = append(history.revisions, r)
+		return
+	}
+	history.revisions[history.next] = r
+	history.next = (history.next + 1) % historySize
+}
+
+// revisionsOf returns the recorded revisions of ref, oldest first
+func revisionsOf(ref objectRef) []revision {
+	history.Lock()
+	defer history.Unlock()
+	var revisions []revision
+	for i := range history.revisions {
+		r := history.revisions[(history.next+i)%len(history.revisions)]
+		if r.ref == ref {
+			revisions = append(revisions, r)
+		}
+	}
+	return revisions
+}
+
+func selectedRef(t *throwing.TableView) objectRef {
+	namespace, name := getNamespaceAndName(t)
+	return objectRef{kind: t.GetResourceKind(), namespace: namespace, name: name}
+}
+
+// showHistory lists the revisions of the selected object seen so far, Enter diffs a revision against the one before it
+func showHistory(t *throwing.TableView) {
+	ref := selectedRef(t)
+	revisions := revisionsOf(ref)
+	if len(revisions) == 0 {
+		t.Notify(fmt.Sprintf("no revisions of %s seen yet, get or watch it first", ref), throwing.SeverityWarning)
+		return
+	}
+
+	table := tview.NewTable()
+	table.SetBorder(true)
+	table.SetTitle(fmt.Sprintf("history - (%s)", ref))
+	table.SetTitleColor(tcell.ColorPurple)
+	table.SetBackgroundColor(tcell.ColorBlack)
+	table.SetSelectable(true, false)
+	table.SetFixed(1, 0)
+	for col, h := range []string{"RESOURCE VERSION", "OBSERVED", "CHANGED LINES"} {
+		table.SetCell(0, col, tview.NewTableCell(h).SetTextColor(tcell.ColorPurple).SetSelectable(false).SetExpansion(1))
+	}
+
+	lines := func(r revision) []string {
+		return strings.Split(strings.TrimSuffix(r.content, "\n"), "\n")
+	}
+	// newest first
+	for i := range revisions {
+		r := revisions[len(revisions)-1-i]
+		changed := "-"
+		if prev := len(revisions) - 2 - i; prev >= 0 {
+			n := 0
+			for _, d := range diffLines(lines(revisions[prev]), lines(r)) {
+				if d.op != diffEqual {
+					n++
+				}
+			}
+			changed = fmt.Sprint(n)
+		}
+		table.SetCell(i+1, 0, tview.NewTableCell(r.resourceVersion).SetTextColor(tcell.ColorAntiqueWhite))
+		table.SetCell(i+1, 1, tview.NewTableCell(r.observed.Format("15:04:05")).SetTextColor(tcell.ColorAntiqueWhite))
+		table.SetCell(i+1, 2, tview.NewTableCell(changed).SetTextColor(tcell.ColorAntiqueWhite))
+	}
+
+	table.SetSelectedFunc(func(row, column int) {
+		i := len(revisions) - row
+		if i <= 0 {
+			t.Notify("the oldest revision has nothing to diff against", throwing.SeverityInfo)
+			return
+		}
+		from, to := revisions[i-1], revisions[i]
+		if externalDiff(t, fmt.Sprintf("%s %s", ref, from.resourceVersion), from.content, fmt.Sprintf("%s %s", ref, to.resourceVersion), to.content) {
+			return
+		}
+		diff := unifiedDiff(lines(from), lines(to), "resourceVersion "+from.resourceVersion, "resourceVersion "+to.resourceVersion)
+		showText(t, "diff", fmt.Sprintf("diff - (%s %s..%s)", ref, from.resourceVersion, to.resourceVersion), diff, languageDiff)
+	})
+	table.SetDoneFunc(func(key tcell.Key) {
+		if key == tcell.KeyEscape {
+			t.SwitchToRootPage()
+		}
+	})
+
+	newpage := tview.NewPages().AddPage("history", table, true, true)
+	t.SwitchPage(t.GetCurrentPage(), newpage)
+}
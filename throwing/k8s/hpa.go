@@ -0,0 +1,233 @@
+package k8s
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell"
+	"github.com/rancher/axe/throwing"
+	"github.com/rivo/tview"
+	autoscalingv2 "k8s.io/api/autoscaling/v2beta2"
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+const hpaKind = "horizontalpodautoscalers.autoscaling"
+
+func age(t metav1.Time) string {
+	if t.IsZero() {
+		return "<none>"
+	}
+	return duration.HumanDuration(time.Since(t.Time))
+}
+
+func metricName(metricType autoscalingv2.MetricSourceType, resourceName v1.ResourceName, identifier autoscalingv2.MetricIdentifier) string {
+	if metricType == autoscalingv2.ResourceMetricSourceType {
+		return string(resourceName)
+	}
+	return identifier.Name
+}
+
+func formatQuantity(q *resource.Quantity) string {
+	if q == nil {
+		return ""
+	}
+	return q.String()
+}
+
+func formatTarget(target autoscalingv2.MetricTarget) string {
+	if target.AverageUtilization != nil {
+		return fmt.Sprintf("%d%%", *target.AverageUtilization)
+	}
+	if target.AverageValue != nil {
+		return formatQuantity(target.AverageValue)
+	}
+	return formatQuantity(target.Value)
+}
+
+func formatCurrent(current autoscalingv2.MetricValueStatus) string {
+	if current.AverageUtilization != nil {
+		return fmt.Sprintf("%d%%", *current.AverageUtilization)
+	}
+	if current.AverageValue != nil {
+		return formatQuantity(current.AverageValue)
+	}
+	if current.Value != nil {
+		return formatQuantity(current.Value)
+	}
+	return "<unknown>"
+}
+
+// specMetric returns the name and target of a metric spec
+func specMetric(m autoscalingv2.MetricSpec) (string, string) {
+	switch {
+	case m.Resource != nil:
+		return metricName(m.Type, m.Resource.Name, autoscalingv2.MetricIdentifier{}), formatTarget(m.Resource.Target)
+	case m.Pods != nil:
+		return metricName(m.Type, "", m.Pods.Metric), formatTarget(m.Pods.Target)
+	case m.Object != nil:
+		return metricName(m.Type, "", m.Object.Metric), formatTarget(m.Object.Target)
+	case m.External != nil:
+		return metricName(m.Type, "", m.External.Metric), formatTarget(m.External.Target)
+	}
+	return string(m.Type), ""
+}
+
+func statusMetric(m autoscalingv2.MetricStatus) (string, string) {
+	switch {
+	case m.Resource != nil:
+		return metricName(m.Type, m.Resource.Name, autoscalingv2.MetricIdentifier{}), formatCurrent(m.Resource.Current)
+	case m.Pods != nil:
+		return metricName(m.Type, "", m.Pods.Metric), formatCurrent(m.Pods.Current)
+	case m.Object != nil:
+		return metricName(m.Type, "", m.Object.Metric), formatCurrent(m.Object.Current)
+	case m.External != nil:
+		return metricName(m.Type, "", m.External.Metric), formatCurrent(m.External.Current)
+	}
+	return string(m.Type), ""
+}
+
+// formatMetrics renders every metric as name current/target
+func formatMetrics(hpa autoscalingv2.HorizontalPodAutoscaler) string {
+	current := map[string]string{}
+	for _, m := range hpa.Status.CurrentMetrics {
+		name, value := statusMetric(m)
+		current[name] = value
+	}
+	var metrics []string
+	for _, m := range hpa.Spec.Metrics {
+		name, target := specMetric(m)
+		value, ok := current[name]
+		if !ok {
+			value = "<unknown>"
+		}
+		metrics = append(metrics, fmt.Sprintf("%s %s/%s", name, value, target))
+	}
+	if len(metrics) == 0 {
+		return "<none>"
+	}
+	return strings.Join(metrics, ", ")
+}
+
+// refreshHPAs lists autoscalers with their replica counts and metrics against targets
+func (w wrapper) refreshHPAs(b *bytes.Buffer) error {
+	clientset, err := newClientset()
+	if err != nil {
+		return err
+	}
+	namespace, scoped := w.listScope()
+	hpas, err := clientset.AutoscalingV2beta2().HorizontalPodAutoscalers(namespace).List(metav1.ListOptions{LabelSelector: w.labelSelector})
+	if err != nil {
+		return err
+	}
+
+	var rows [][]string
+	for _, hpa := range hpas.Items {
+		min := int32(1)
+		if hpa.Spec.MinReplicas != nil {
+			min = *hpa.Spec.MinReplicas
+		}
+		var lastScale metav1.Time
+		if hpa.Status.LastScaleTime != nil {
+			lastScale = *hpa.Status.LastScaleTime
+		}
+		rows = append(rows, []string{
+			hpa.Namespace,
+			hpa.Name,
+			fmt.Sprintf("%s/%s", hpa.Spec.ScaleTargetRef.Kind, hpa.Spec.ScaleTargetRef.Name),
+			fmt.Sprint(min),
+			fmt.Sprint(hpa.Spec.MaxReplicas),
+			fmt.Sprint(hpa.Status.CurrentReplicas),
+			fmt.Sprint(hpa.Status.DesiredReplicas),
+			formatMetrics(hpa),
+			age(lastScale),
+		})
+	}
+	writeScopedTable(b, scoped, []string{"NAMESPACE", "NAME", "REFERENCE", "MIN", "MAX", "CURRENT", "DESIRED", "METRICS", "LAST SCALE"}, rows)
+	return nil
+}
+
+// hpaEvents shows the events of the selected autoscaler, newest first
+func hpaEvents(t *throwing.TableView) {
+	namespace, name := getNamespaceAndName(t)
+	events, err := t.GetClientSet().CoreV1().Events(namespace).List(metav1.ListOptions{
+		FieldSelector: fmt.Sprintf("involvedObject.kind=HorizontalPodAutoscaler,involvedObject.name=%s", name),
+	})
+	if err != nil {
+		t.Notify(err.Error(), throwing.SeverityError)
+		return
+	}
+	sort.Slice(events.Items, func(i, j int) bool {
+		return events.Items[j].LastTimestamp.Before(&events.Items[i].LastTimestamp)
+	})
+
+	box := tview.NewTextView()
+	box.SetBorder(true)
+	box.SetTitle(fmt.Sprintf("scale events - (%s/%s)", namespace, name))
+	box.SetTitleColor(tcell.ColorPurple)
+	box.SetDynamicColors(true).SetBackgroundColor(tcell.ColorBlack)
+	if len(events.Items) == 0 {
+		fmt.Fprint(box, "[gray]no events")
+	}
+	for _, e := range events.Items {
+		color := "white"
+		if e.Type == v1.EventTypeWarning {
+			color = "red"
+		}
+		fmt.Fprintf(box, "[gray]%s ago[%s] %s: %s\n", age(e.LastTimestamp), color, e.Reason, tview.Escape(e.Message))
+	}
+
+	newpage := tview.NewPages().AddPage("events", box, true, true)
+	t.SwitchPage(t.GetCurrentPage(), newpage)
+}
+
+// editHPABounds patches minReplicas and maxReplicas of the selected autoscaler
+func editHPABounds(t *throwing.TableView) {
+	namespace, name := getNamespaceAndName(t)
+	if !canI(t, "patch", hpaKind, "", namespace) {
+		return
+	}
+	hpa, err := t.GetClientSet().AutoscalingV2beta2().HorizontalPodAutoscalers(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		t.Notify(err.Error(), throwing.SeverityError)
+		return
+	}
+	min := int32(1)
+	if hpa.Spec.MinReplicas != nil {
+		min = *hpa.Spec.MinReplicas
+	}
+
+	fields := []throwing.FormField{
+		{Name: "min", Label: "min replicas", Default: fmt.Sprint(min), Width: 10, Numeric: true, Validate: throwing.IntBetween(1, math.MaxInt32)},
+		{Name: "max", Label: "max replicas", Default: fmt.Sprint(hpa.Spec.MaxReplicas), Width: 10, Numeric: true, Validate: throwing.IntBetween(1, math.MaxInt32)},
+	}
+	t.ShowForm(fmt.Sprintf("Scale bounds - (%s/%s)", namespace, name), "Save", fields, func(values throwing.FormValues) error {
+		min, max := values.Int("min"), values.Int("max")
+		if max < min {
+			return fmt.Errorf("min replicas can't be above max replicas")
+		}
+		patch, err := json.Marshal(map[string]interface{}{
+			"spec": map[string]interface{}{
+				"minReplicas": min,
+				"maxReplicas": max,
+			},
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := t.GetClientSet().AutoscalingV2beta2().HorizontalPodAutoscalers(namespace).Patch(name, k8stypes.MergePatchType, patch); err != nil {
+			return err
+		}
+		t.Notify(fmt.Sprintf("%s now scales between %d and %d replicas", name, min, max), throwing.SeverityInfo)
+		t.Refresh()
+		return nil
+	})
+}
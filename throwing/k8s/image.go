@@ -0,0 +1,129 @@
+package k8s
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/rancher/axe/throwing"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+const initContainerPrefix = "init:"
+
+func workloadTemplate(clientset *kubernetes.Clientset, kind, namespace, name string) (*v1.PodTemplateSpec, error) {
+	switch kind {
+	case "deployments.apps":
+		d, err := clientset.AppsV1().Deployments(namespace).Get(name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &d.Spec.Template, nil
+	case "daemonsets.apps":
+		d, err := clientset.AppsV1().DaemonSets(namespace).Get(name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &d.Spec.Template, nil
+	case "statefulsets.apps":
+		s, err := clientset.AppsV1().StatefulSets(namespace).Get(name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &s.Spec.Template, nil
+	}
+	return nil, fmt.Errorf("%s has no pod template", kind)
+}
+
+func patchWorkload(clientset *kubernetes.Clientset, kind, namespace, name string, patch []byte) error {
+	var err error
+	switch kind {
+	case "deployments.apps":
+		_, err = clientset.AppsV1().Deployments(namespace).Patch(name, k8stypes.StrategicMergePatchType, patch)
+	case "daemonsets.apps":
+		_, err = clientset.AppsV1().DaemonSets(namespace).Patch(name, k8stypes.StrategicMergePatchType, patch)
+	case "statefulsets.apps":
+		_, err = clientset.AppsV1().StatefulSets(namespace).Patch(name, k8stypes.StrategicMergePatchType, patch)
+	default:
+		err = fmt.Errorf("%s has no pod template", kind)
+	}
+	return err
+}
+
+// imagePatch only lists the containers whose image changed, the strategic merge keeps the others
+func imagePatch(images map[string]string) ([]byte, error) {
+	var containers, initContainers []map[string]string
+	for name, image := range images {
+		if strings.HasPrefix(name, initContainerPrefix) {
+			initContainers = append(initContainers, map[string]string{"name": strings.TrimPrefix(name, initContainerPrefix), "image": image})
+			continue
+		}
+		containers = append(containers, map[string]string{"name": name, "image": image})
+	}
+	podSpec := map[string]interface{}{}
+	if len(containers) > 0 {
+		podSpec["containers"] = containers
+	}
+	if len(initContainers) > 0 {
+		podSpec["initContainers"] = initContainers
+	}
+	return json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": podSpec,
+			},
+		},
+	})
+}
+
+// setImage edits the container images of a deployment, daemonset or statefulset
+func setImage(t *throwing.TableView) {
+	namespace, name := getNamespaceAndName(t)
+	kind := t.GetResourceKind()
+	if !canI(t, "patch", kind, "", namespace) {
+		return
+	}
+	template, err := workloadTemplate(t.GetClientSet(), kind, namespace, name)
+	if err != nil {
+		t.Notify(err.Error(), throwing.SeverityError)
+		return
+	}
+
+	original := map[string]string{}
+	var fields []throwing.FormField
+	addField := func(label string, c v1.Container) {
+		original[label] = c.Image
+		fields = append(fields, throwing.FormField{Label: label, Default: c.Image, Width: 60, Validate: throwing.Required})
+	}
+	for _, c := range template.Spec.InitContainers {
+		addField(initContainerPrefix+c.Name, c)
+	}
+	for _, c := range template.Spec.Containers {
+		addField(c.Name, c)
+	}
+
+	t.ShowForm(fmt.Sprintf("Set image - (%s/%s)", namespace, name), "Save", fields, func(values throwing.FormValues) error {
+		changed := map[string]string{}
+		for label, image := range values {
+			if image = strings.TrimSpace(image); image != original[label] {
+				changed[label] = image
+			}
+		}
+		if len(changed) == 0 {
+			return nil
+		}
+		patch, err := imagePatch(changed)
+		if err != nil {
+			return err
+		}
+		if err := patchWorkload(t.GetClientSet(), kind, namespace, name, patch); err != nil {
+			return err
+		}
+		t.Notify(fmt.Sprintf("updated %d image(s) of %s", len(changed), name), throwing.SeverityInfo)
+		publishChange(t, throwing.EventChanged, kind, namespace, name)
+		return nil
+	})
+}
@@ -0,0 +1,220 @@
+package k8s
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gdamore/tcell"
+	"github.com/rancher/axe/throwing"
+	"github.com/rancher/axe/throwing/datafeeder"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const imagesKind = "images"
+
+// defaultImageScanner runs when imageScanner isn't in the config and trivy is installed
+var defaultImageScanner = []string{"trivy", "image", "--quiet", "--no-progress"}
+
+/*
+imageScanner is the command from imageScanner in the config, e.g. "grype" or "trivy image --severity HIGH,CRITICAL".
+The image is appended to it, whatever it prints is the result. Counts like "HIGH: 3" in the output make the SCAN column.
+*/
+var imageScanner []string
+
+// severityCount picks the counts out of a scanner summary, like trivy's "Total: 3 (HIGH: 2, CRITICAL: 1)"
+var severityCount = regexp.MustCompile(`\b(CRITICAL|HIGH|MEDIUM|LOW|UNKNOWN): (\d+)`)
+
+var scanSeverities = []string{"CRITICAL", "HIGH", "MEDIUM", "LOW", "UNKNOWN"}
+
+type imageScan struct {
+	running bool
+	summary string
+	output  string
+	failed  bool
+	at      time.Time
+}
+
+// imageScans are the scans run this session, keyed by image
+var imageScans = struct {
+	sync.Mutex
+	byImage map[string]*imageScan
+}{
+	byImage: map[string]*imageScan{},
+}
+
+// RefreshImages lists the images of the containers of every running pod, the most used first
+func RefreshImages(b *bytes.Buffer) error {
+	clientset, err := newClientset()
+	if err != nil {
+		return err
+	}
+	pods, err := clientset.CoreV1().Pods(v1.NamespaceAll).List(metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	type usage struct {
+		pods       map[string]bool
+		containers int
+		namespaces map[string]bool
+	}
+	images := map[string]*usage{}
+	for _, pod := range pods.Items {
+		if pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+			continue
+		}
+		for _, c := range append(pod.Spec.InitContainers, pod.Spec.Containers...) {
+			u, ok := images[c.Image]
+			if !ok {
+				u = &usage{pods: map[string]bool{}, namespaces: map[string]bool{}}
+				images[c.Image] = u
+			}
+			u.pods[pod.Namespace+"/"+pod.Name] = true
+			u.containers++
+			u.namespaces[pod.Namespace] = true
+		}
+	}
+
+	var names []string
+	for image := range images {
+		names = append(names, image)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		a, b := images[names[i]], images[names[j]]
+		if len(a.pods) != len(b.pods) {
+			return len(a.pods) > len(b.pods)
+		}
+		return names[i] < names[j]
+	})
+
+	imageScans.Lock()
+	defer imageScans.Unlock()
+	var rows [][]string
+	for _, image := range names {
+		u := images[image]
+		var namespaces []string
+		for ns := range u.namespaces {
+			namespaces = append(namespaces, ns)
+		}
+		sort.Strings(namespaces)
+		scan := "-"
+		if s, ok := imageScans.byImage[image]; ok {
+			scan = s.summary
+		}
+		rows = append(rows, []string{image, strconv.Itoa(len(u.pods)), strconv.Itoa(u.containers), summarize(namespaces, "namespaces"), scan})
+	}
+	writeTable(b, []string{"IMAGE", "PODS", "CONTAINERS", "NAMESPACES", "SCAN"}, rows)
+	return nil
+}
+
+// scanSummary sums up the counts in the output, or just says the scan is done when there are none
+func scanSummary(output string) string {
+	counts := map[string]int{}
+	for _, m := range severityCount.FindAllStringSubmatch(output, -1) {
+		n, _ := strconv.Atoi(m[2])
+		counts[m[1]] += n
+	}
+	var parts []string
+	for _, severity := range scanSeverities {
+		if counts[severity] > 0 {
+			parts = append(parts, fmt.Sprintf("%s %d", severity, counts[severity]))
+		}
+	}
+	if len(parts) == 0 {
+		return "done"
+	}
+	return strings.Join(parts, " ")
+}
+
+func scannerCommand() ([]string, error) {
+	if len(imageScanner) > 0 {
+		return imageScanner, nil
+	}
+	if _, err := exec.LookPath(defaultImageScanner[0]); err != nil {
+		return nil, fmt.Errorf("no image scanner, install trivy or set imageScanner in the config")
+	}
+	return defaultImageScanner, nil
+}
+
+// scanImage runs the scanner on the selected image in the background, the SCAN column shows how it went
+func scanImage(t *throwing.TableView) {
+	image := selectedColumn(t, "IMAGE")
+	if image == "" {
+		return
+	}
+	scanner, err := scannerCommand()
+	if err != nil {
+		t.Notify(err.Error(), throwing.SeverityError)
+		return
+	}
+
+	imageScans.Lock()
+	if s, ok := imageScans.byImage[image]; ok && s.running {
+		imageScans.Unlock()
+		t.Notify(fmt.Sprintf("%s is being scanned already", image), throwing.SeverityInfo)
+		return
+	}
+	imageScans.byImage[image] = &imageScan{running: true, summary: "scanning"}
+	imageScans.Unlock()
+	t.Notify(fmt.Sprintf("scanning %s with %s", image, scanner[0]), throwing.SeverityProgress)
+	t.Refresh()
+
+	ctx := t.Context()
+	t.Go(func() {
+		cmd := exec.CommandContext(ctx, scanner[0], append(scanner[1:], image)...)
+		output, err := cmd.CombinedOutput()
+		scan := &imageScan{output: string(output), summary: scanSummary(string(output)), at: time.Now()}
+		if err != nil {
+			scan.failed = true
+			scan.summary = "failed"
+			scan.output = fmt.Sprintf("%s: %v\n\n%s", scanner[0], err, output)
+		}
+		imageScans.Lock()
+		imageScans.byImage[image] = scan
+		imageScans.Unlock()
+
+		severity := throwing.SeverityInfo
+		if scan.failed || strings.Contains(scan.summary, "CRITICAL") || strings.Contains(scan.summary, "HIGH") {
+			severity = throwing.SeverityWarning
+		}
+		t.Notify(fmt.Sprintf("%s: %s, v shows the result", image, scan.summary), severity)
+		t.Refresh()
+	})
+}
+
+// showImageScan shows what the scanner printed for the selected image
+func showImageScan(t *throwing.TableView) {
+	image := selectedColumn(t, "IMAGE")
+	imageScans.Lock()
+	s, ok := imageScans.byImage[image]
+	imageScans.Unlock()
+	switch {
+	case !ok:
+		t.Notify(fmt.Sprintf("%s wasn't scanned yet, s scans it", image), throwing.SeverityInfo)
+		return
+	case s.running:
+		t.Notify(fmt.Sprintf("%s is being scanned", image), throwing.SeverityInfo)
+		return
+	}
+	showText(t, "scan", fmt.Sprintf("scan - %s (%s)", image, s.at.Format("15:04:05")), s.output, "")
+}
+
+func imageRowColor(header, row datafeeder.Row) tcell.Color {
+	scan := columnOrEmpty(header, row, "SCAN")
+	switch {
+	case strings.Contains(scan, "CRITICAL"), scan == "failed":
+		return tcell.ColorRed
+	case strings.Contains(scan, "HIGH"):
+		return tcell.ColorYellow
+	case scan == "done":
+		return tcell.ColorGreen
+	}
+	return tcell.ColorDefault
+}
@@ -0,0 +1,139 @@
+package k8s
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"time"
+
+	"github.com/rancher/axe/throwing"
+	extensionsv1beta1 "k8s.io/api/extensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const urlNotifyTimeout = 15 * time.Second
+
+func ingressTLS(ingress extensionsv1beta1.Ingress, host string) bool {
+	for _, tls := range ingress.Spec.TLS {
+		if len(tls.Hosts) == 0 {
+			return true
+		}
+		for _, h := range tls.Hosts {
+			if h == host {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ingressAddress is used for rules without a host
+func ingressAddress(ingress extensionsv1beta1.Ingress) string {
+	for _, lb := range ingress.Status.LoadBalancer.Ingress {
+		if lb.Hostname != "" {
+			return lb.Hostname
+		}
+		if lb.IP != "" {
+			return lb.IP
+		}
+	}
+	return ""
+}
+
+func formatBackend(backend extensionsv1beta1.IngressBackend) string {
+	return fmt.Sprintf("%s:%s", backend.ServiceName, backend.ServicePort.String())
+}
+
+// refreshIngresses lists one row per host and path with the URL it is reachable at
+func (w wrapper) refreshIngresses(b *bytes.Buffer) error {
+	clientset, err := newClientset()
+	if err != nil {
+		return err
+	}
+	namespace, scoped := w.listScope()
+	ingresses, err := clientset.ExtensionsV1beta1().Ingresses(namespace).List(metav1.ListOptions{LabelSelector: w.labelSelector})
+	if err != nil {
+		return err
+	}
+
+	var rows [][]string
+	for _, ing := range ingresses.Items {
+		row := func(host, path string, backend extensionsv1beta1.IngressBackend) []string {
+			tls := ingressTLS(ing, host)
+			address := host
+			if address == "" {
+				address = ingressAddress(ing)
+			}
+			url := ""
+			if address != "" {
+				scheme := "http"
+				if tls {
+					scheme = "https"
+				}
+				url = fmt.Sprintf("%s://%s%s", scheme, address, path)
+			}
+			tlsColumn := "no"
+			if tls {
+				tlsColumn = "yes"
+			}
+			displayHost := host
+			if displayHost == "" {
+				displayHost = "*"
+			}
+			return []string{ing.Namespace, ing.Name, displayHost, path, formatBackend(backend), tlsColumn, url}
+		}
+
+		if ing.Spec.Backend != nil {
+			rows = append(rows, row("", "/", *ing.Spec.Backend))
+		}
+		for _, rule := range ing.Spec.Rules {
+			if rule.HTTP == nil {
+				continue
+			}
+			for _, p := range rule.HTTP.Paths {
+				path := p.Path
+				if path == "" {
+					path = "/"
+				}
+				rows = append(rows, row(rule.Host, path, p.Backend))
+			}
+		}
+	}
+	writeScopedTable(b, scoped, []string{"NAMESPACE", "NAME", "HOST", "PATH", "BACKEND", "TLS", "URL"}, rows)
+	return nil
+}
+
+func browserCommand() (string, bool) {
+	if runtime.GOOS == "darwin" {
+		return "open", true
+	}
+	if os.Getenv("DISPLAY") == "" && os.Getenv("WAYLAND_DISPLAY") == "" {
+		return "", false
+	}
+	if _, err := exec.LookPath("xdg-open"); err != nil {
+		return "", false
+	}
+	return "xdg-open", true
+}
+
+// openIngress opens the URL of the selected row in the browser, or shows it when there is no display
+func openIngress(t *throwing.TableView) {
+	url := selectedColumn(t, "URL")
+	if url == "" {
+		t.Notify("ingress has no host or address yet", throwing.SeverityWarning)
+		return
+	}
+
+	command, ok := browserCommand()
+	if !ok {
+		t.NotifyWithTimeout(url, throwing.SeverityInfo, urlNotifyTimeout)
+		return
+	}
+	if err := exec.Command(command, url).Start(); err != nil {
+		t.NotifyWithTimeout(fmt.Sprintf("failed to open browser (%v): %s", err, url), throwing.SeverityWarning, urlNotifyTimeout)
+		return
+	}
+	t.Notify("opened "+url, throwing.SeverityInfo)
+}
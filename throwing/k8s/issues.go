@@ -0,0 +1,174 @@
+package k8s
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/gdamore/tcell"
+	"github.com/rancher/axe/throwing"
+	"github.com/rancher/axe/throwing/datafeeder"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const issuesKind = "issues"
+
+var (
+	badWaitingReasons = map[string]bool{
+		"CrashLoopBackOff":           true,
+		"ImagePullBackOff":           true,
+		"ErrImagePull":               true,
+		"CreateContainerConfigError": true,
+		"InvalidImageName":           true,
+	}
+)
+
+type issue struct {
+	namespace, name, kind, reason, message string
+}
+
+func podIssues(clientset *kubernetes.Clientset) ([]issue, error) {
+	pods, err := clientset.CoreV1().Pods(v1.NamespaceAll).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var issues []issue
+	for _, pod := range pods.Items {
+		if pod.Status.Phase == v1.PodSucceeded {
+			continue
+		}
+		found := false
+		for _, cs := range append(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses...) {
+			if cs.State.Waiting != nil && badWaitingReasons[cs.State.Waiting.Reason] {
+				issues = append(issues, issue{pod.Namespace, pod.Name, "pods", cs.State.Waiting.Reason, fmt.Sprintf("container %s: %s", cs.Name, cs.State.Waiting.Message)})
+				found = true
+				break
+			}
+		}
+		if found {
+			continue
+		}
+		switch pod.Status.Phase {
+		case v1.PodPending:
+			issues = append(issues, issue{pod.Namespace, pod.Name, "pods", "Pending", pod.Status.Message})
+		case v1.PodFailed:
+			issues = append(issues, issue{pod.Namespace, pod.Name, "pods", "Failed", pod.Status.Message})
+		}
+	}
+	return issues, nil
+}
+
+func deploymentIssues(clientset *kubernetes.Clientset) ([]issue, error) {
+	deployments, err := clientset.AppsV1().Deployments(v1.NamespaceAll).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var issues []issue
+	for _, d := range deployments.Items {
+		desired := int32(1)
+		if d.Spec.Replicas != nil {
+			desired = *d.Spec.Replicas
+		}
+		if d.Status.AvailableReplicas >= desired {
+			continue
+		}
+		message := fmt.Sprintf("%d/%d available", d.Status.AvailableReplicas, desired)
+		for _, c := range d.Status.Conditions {
+			if c.Type == appsv1.DeploymentAvailable && c.Status != v1.ConditionTrue {
+				message += ": " + c.Message
+			}
+		}
+		issues = append(issues, issue{d.Namespace, d.Name, "deployments.apps", "Unavailable", message})
+	}
+	return issues, nil
+}
+
+func nodeIssues(clientset *kubernetes.Clientset) ([]issue, error) {
+	nodes, err := clientset.CoreV1().Nodes().List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var issues []issue
+	for _, n := range nodes.Items {
+		for _, c := range n.Status.Conditions {
+			if c.Type == v1.NodeReady && c.Status != v1.ConditionTrue {
+				issues = append(issues, issue{"", n.Name, "nodes", "NotReady", c.Message})
+			}
+		}
+	}
+	return issues, nil
+}
+
+func jobIssues(clientset *kubernetes.Clientset) ([]issue, error) {
+	jobs, err := clientset.BatchV1().Jobs(v1.NamespaceAll).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var issues []issue
+	for _, j := range jobs.Items {
+		for _, c := range j.Status.Conditions {
+			if c.Type == batchv1.JobFailed && c.Status == v1.ConditionTrue {
+				issues = append(issues, issue{j.Namespace, j.Name, "jobs.batch", "Failed", c.Message})
+			}
+		}
+	}
+	return issues, nil
+}
+
+// RefreshIssues lists unhealthy objects of every kind axe knows how to judge
+func RefreshIssues(b *bytes.Buffer) error {
+	clientset, err := newClientset()
+	if err != nil {
+		return err
+	}
+
+	var issues []issue
+	for _, list := range []func(*kubernetes.Clientset) ([]issue, error){podIssues, deploymentIssues, nodeIssues, jobIssues} {
+		found, err := list(clientset)
+		if err != nil {
+			return err
+		}
+		issues = append(issues, found...)
+	}
+	sort.Slice(issues, func(i, j int) bool {
+		if issues[i].kind != issues[j].kind {
+			return issues[i].kind < issues[j].kind
+		}
+		if issues[i].namespace != issues[j].namespace {
+			return issues[i].namespace < issues[j].namespace
+		}
+		return issues[i].name < issues[j].name
+	})
+
+	var rows [][]string
+	for _, i := range issues {
+		rows = append(rows, []string{i.namespace, i.name, i.kind, i.reason, i.message})
+	}
+	writeTable(b, []string{"NAMESPACE", "NAME", "KIND", "REASON", "MESSAGE"}, rows)
+	return nil
+}
+
+func issueRowColor(header, row datafeeder.Row) tcell.Color {
+	switch columnOrEmpty(header, row, "REASON") {
+	case "Pending", "Unavailable":
+		return tcell.ColorYellow
+	}
+	return tcell.ColorRed
+}
+
+// viewIssue opens the table the selected issue comes from with the object selected
+func viewIssue(t *throwing.TableView) {
+	namespace, name := getNamespaceAndName(t)
+	w, ok := builtinKinds[selectedColumn(t, "KIND")]
+	if !ok {
+		return
+	}
+	openResourceTable(t, w, w.kind())
+	if nt := t.GetNestedTable(w.kind()); nt != nil {
+		nt.SelectRow(namespace, name)
+	}
+}
@@ -0,0 +1,236 @@
+package k8s
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell"
+	"github.com/rancher/axe/throwing"
+	"github.com/rancher/axe/throwing/datafeeder"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+const (
+	jobsKind = "jobs"
+	// maxJobName keeps the name of a rerun a valid value of the job-name label
+	maxJobName = 63
+)
+
+// jobControllerLabels are set by the job controller on the job and its pods, a clone gets its own
+var jobControllerLabels = []string{"controller-uid", "job-name", "batch.kubernetes.io/controller-uid", "batch.kubernetes.io/job-name"}
+
+func jobCondition(job batchv1.Job, condition batchv1.JobConditionType) (batchv1.JobCondition, bool) {
+	for _, c := range job.Status.Conditions {
+		if c.Type == condition && c.Status == v1.ConditionTrue {
+			return c, true
+		}
+	}
+	return batchv1.JobCondition{}, false
+}
+
+// jobStatus is Complete or Failed once the job is done, Running while it has active pods and Pending before
+func jobStatus(job batchv1.Job) string {
+	if _, ok := jobCondition(job, batchv1.JobComplete); ok {
+		return string(batchv1.JobComplete)
+	}
+	if _, ok := jobCondition(job, batchv1.JobFailed); ok {
+		return string(batchv1.JobFailed)
+	}
+	if job.Status.Active > 0 {
+		return "Running"
+	}
+	return "Pending"
+}
+
+/*
+jobEnd is when the job completed or failed, zero while it runs.
+A failed job has no completionTime, the transition of its Failed condition is its end.
+*/
+func jobEnd(job batchv1.Job) time.Time {
+	if job.Status.CompletionTime != nil {
+		return job.Status.CompletionTime.Time
+	}
+	if c, ok := jobCondition(job, batchv1.JobFailed); ok {
+		return c.LastTransitionTime.Time
+	}
+	return time.Time{}
+}
+
+// jobCompletions is succeeded out of the completions the job wants, a work queue without completions shows succeeded/1 like kubectl
+func jobCompletions(job batchv1.Job) string {
+	completions := int32(1)
+	if job.Spec.Completions != nil {
+		completions = *job.Spec.Completions
+	}
+	return fmt.Sprintf("%d/%d", job.Status.Succeeded, completions)
+}
+
+// jobFailures is the failed pods out of the backoff limit, the job fails once they're past it
+func jobFailures(job batchv1.Job) string {
+	limit := int32(6)
+	if job.Spec.BackoffLimit != nil {
+		limit = *job.Spec.BackoffLimit
+	}
+	return fmt.Sprintf("%d/%d", job.Status.Failed, limit)
+}
+
+/*
+RefreshJobs lists the jobs of all namespaces with their completions, failed and active pods, the newest first.
+DURATION runs from the start to the end of the job, it keeps counting between refreshes while the job runs.
+*/
+func RefreshJobs(b *bytes.Buffer) error {
+	clientset, err := newClientset()
+	if err != nil {
+		return err
+	}
+	jobs, err := clientset.BatchV1().Jobs(v1.NamespaceAll).List(metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	sort.SliceStable(jobs.Items, func(i, j int) bool {
+		return jobs.Items[j].CreationTimestamp.Before(&jobs.Items[i].CreationTimestamp)
+	})
+
+	var rows [][]string
+	times := map[string]cellTimes{}
+	now := time.Now()
+	for _, job := range jobs.Items {
+		times[job.Namespace+"/"+job.Name] = cellTimes{created: job.CreationTimestamp.Time}
+		jobDuration := ""
+		if job.Status.StartTime != nil {
+			end := jobEnd(job)
+			if end.IsZero() {
+				times[job.Namespace+"/"+job.Name] = cellTimes{created: job.CreationTimestamp.Time, started: job.Status.StartTime.Time}
+				end = now
+			}
+			jobDuration = duration.HumanDuration(end.Sub(job.Status.StartTime.Time))
+		}
+		rows = append(rows, []string{
+			job.Namespace,
+			job.Name,
+			jobCompletions(job),
+			jobFailures(job),
+			fmt.Sprint(job.Status.Active),
+			jobDuration,
+			age(job.CreationTimestamp),
+			jobStatus(job),
+		})
+	}
+	recordTimes(jobsKind, times)
+	writeTable(b, []string{"NAMESPACE", "NAME", "COMPLETIONS", "FAILED", "ACTIVE", "DURATION", "AGE", "STATUS"}, rows)
+	return nil
+}
+
+func jobRowColor(header, row datafeeder.Row) tcell.Color {
+	switch columnOrEmpty(header, row, "STATUS") {
+	case string(batchv1.JobFailed):
+		return tcell.ColorRed
+	case string(batchv1.JobComplete):
+		return tcell.ColorGreen
+	}
+	if failed, _, ok := readyCount(columnOrEmpty(header, row, "FAILED")); ok && failed != "0" {
+		return tcell.ColorYellow
+	}
+	return tcell.ColorDefault
+}
+
+// openJobPods opens the pods the job controller created for job
+func openJobPods(t *throwing.TableView, namespace, job string) {
+	openResourceTable(t, wrapper{
+		version:       "v1",
+		name:          "pods",
+		namespace:     namespace,
+		labelSelector: "job-name=" + job,
+	}, fmt.Sprintf("pods (job %s)", job))
+}
+
+// jobPods opens the pods of the selected job, finished ones as well
+func jobPods(t *throwing.TableView) {
+	namespace, name := getNamespaceAndName(t)
+	if name == "" {
+		return
+	}
+	openJobPods(t, namespace, name)
+}
+
+func withoutJobControllerLabels(labels map[string]string) map[string]string {
+	if labels == nil {
+		return nil
+	}
+	cleaned := map[string]string{}
+	for k, v := range labels {
+		cleaned[k] = v
+	}
+	for _, label := range jobControllerLabels {
+		delete(cleaned, label)
+	}
+	return cleaned
+}
+
+/*
+cloneJob is a new job with the spec of job. The selector and the labels of the controller are left out
+so the API server generates them for the clone, unless the job picked its pods with a manual selector.
+The clone isn't owned by the cronjob of the job, the cronjob would count it in its history otherwise.
+*/
+func cloneJob(job *batchv1.Job, now time.Time) *batchv1.Job {
+	suffix := fmt.Sprintf("-rerun-%d", now.Unix())
+	base := job.Name
+	if i := strings.Index(base, "-rerun-"); i > 0 {
+		base = base[:i]
+	}
+	if len(base)+len(suffix) > maxJobName {
+		base = base[:maxJobName-len(suffix)]
+	}
+
+	spec := *job.Spec.DeepCopy()
+	if spec.ManualSelector == nil || !*spec.ManualSelector {
+		spec.Selector = nil
+		spec.ManualSelector = nil
+		spec.Template.Labels = withoutJobControllerLabels(spec.Template.Labels)
+	}
+	annotations := map[string]string{}
+	for k, v := range job.Annotations {
+		if k != "kubectl.kubernetes.io/last-applied-configuration" {
+			annotations[k] = v
+		}
+	}
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        strings.TrimSuffix(base, "-") + suffix,
+			Namespace:   job.Namespace,
+			Labels:      withoutJobControllerLabels(job.Labels),
+			Annotations: annotations,
+		},
+		Spec: spec,
+	}
+}
+
+// rerunJob creates a job with the spec of the selected one and opens its pods
+func rerunJob(t *throwing.TableView) {
+	namespace, name := getNamespaceAndName(t)
+	if name == "" || !canI(t, "create", "jobs.batch", "", namespace) {
+		return
+	}
+	client := t.GetClientSet()
+
+	job, err := client.BatchV1().Jobs(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		t.Notify(err.Error(), throwing.SeverityError)
+		return
+	}
+	clone, err := client.BatchV1().Jobs(namespace).Create(cloneJob(job, time.Now()))
+	if err != nil {
+		t.Notify(err.Error(), throwing.SeverityError)
+		return
+	}
+	t.Notify(fmt.Sprintf("job %s created from %s", clone.Name, name), throwing.SeverityInfo)
+	publishChange(t, throwing.EventChanged, "jobs.batch", namespace, clone.Name)
+
+	openJobPods(t, namespace, clone.Name)
+}
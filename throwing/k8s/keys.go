@@ -0,0 +1,200 @@
+package k8s
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gdamore/tcell"
+	"github.com/rancher/axe/throwing"
+	"github.com/rancher/axe/throwing/types"
+	"github.com/sirupsen/logrus"
+)
+
+func action(name, shortcut, description string, run func(t *throwing.TableView)) kindAction {
+	return kindAction{
+		Action: types.Action{
+			Name:        name,
+			Shortcut:    shortcut,
+			Description: description,
+		},
+		run: run,
+	}
+}
+
+// rootActions are offered on the pages in the footer
+var rootActions = []kindAction{
+	action("refresh", "r", "refresh the table", func(t *throwing.TableView) { t.Refresh() }),
+	action("search", "/", "search the table", func(t *throwing.TableView) { t.ShowSearch() }),
+	action("markdown", "m", "copy the table as Markdown", copyMarkdown),
+	action("next", "n", "next row matching the last search", func(t *throwing.TableView) { t.NextMatch() }),
+	action("previous", "N", "previous row matching the last search", func(t *throwing.TableView) { t.PreviousMatch() }),
+	action("cell", "z", "show the whole cell, Left and Right pick the column", func(t *throwing.TableView) { t.ShowCell() }),
+	action("expand", "E", "every column of the row in full", func(t *throwing.TableView) { t.ExpandRow() }),
+}
+
+// genericActions are offered on every resource table, a kind action with the same key wins
+var genericActions = append([]kindAction{
+	action("get", "g", "get a resource", get),
+	action("describe", "D", "describe a resource", describe),
+	action("copy", "y", "copy a kubectl command for the resource", copyCommand),
+	action("edit", "e", "edit a resource", edit),
+	action("delete", "d", "delete a resource", deleteResource),
+	action("exec", "x", "open a shell in the pod", execute),
+	action("logs", "l", "follow the logs of the pod", logs),
+	action("patch", "j", "apply a json, merge or strategic patch", patchConsole),
+	action("watch", "W", "watch the resource", watchResource),
+	action("track", "T", "ring the bell when the status of the resource changes, T again stops", trackResource),
+	action("mark", "M", "mark the resource to compare with", markForCompare),
+	action("compare", "C", "compare the resource with the marked one", compareWithMark),
+	action("history", "H", "revisions seen this session", showHistory),
+	action("bookmark", "B", "bookmark the resource", toggleBookmark),
+	action("namespaces", "A", "switch between all namespaces and the current one", toggleNamespaces),
+	action("root", "q", "back to the root page", func(t *throwing.TableView) { t.RootPage() }),
+}, rootActions...)
+
+// actionKeys are the shortcuts remapped in the config, keyed by name or by kind.name
+var actionKeys = map[string]throwing.Keys{}
+
+// kindsWithActions lists the kinds of kindActions for the conflict check
+var kindsWithActions = []string{
+	bookmarksKind, certificatesKind, canIKind, subjectsKind, imagesKind, prometheusKind, alertmanagerKind, "serviceaccounts", "pods", podContainersKind, eventsKind, jobsKind, "jobs.batch", "cronjobs.batch", "nodes", "secrets", "configmaps",
+	"deployments.apps", "daemonsets.apps", "statefulsets.apps", hpaKind, "persistentvolumeclaims",
+	"persistentvolumes", "ingresses.extensions", "ingresses.networking.k8s.io",
+	"networkpolicies.networking.k8s.io", "networkpolicies.extensions", "services", helmKind, helmHistoryKind,
+}
+
+// keysFor returns the keys of an action on a table of kind, a kind.name binding beats a name binding beats the default
+func keysFor(kind string, a kindAction) throwing.Keys {
+	if keys, ok := actionKeys[kind+"."+a.Name]; ok {
+		return keys
+	}
+	if keys, ok := actionKeys[a.Name]; ok {
+		return keys
+	}
+	keys, _ := throwing.ParseKeys(a.Shortcut)
+	return keys
+}
+
+// bound returns the actions with their shortcut replaced by the configured one, for the menu
+func bound(kind string, actions []kindAction) []kindAction {
+	var out []kindAction
+	for _, a := range actions {
+		if !allowed(a) {
+			continue
+		}
+		a.Shortcut = keysFor(kind, a).String()
+		out = append(out, a)
+	}
+	return out
+}
+
+// runAction runs the first action bound to event, it reports whether there was one
+func runAction(t *throwing.TableView, actions []kindAction, event *tcell.EventKey) bool {
+	for _, a := range actions {
+		if allowed(a) && keysFor(t.GetResourceKind(), a).Matches(event) {
+			a.run(t)
+			return true
+		}
+	}
+	return false
+}
+
+func knownAction(name string) bool {
+	for _, a := range genericActions {
+		// generic actions can be remapped on one kind too, e.g. pods.logs
+		if a.Name == name || strings.HasSuffix(name, "."+a.Name) {
+			return true
+		}
+	}
+	for _, kind := range kindsWithActions {
+		for _, a := range kindActions(kind) {
+			if name == a.Name || name == kind+"."+a.Name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+/*
+setKeyBindings splits the configured keys between the app and the actions and checks them for conflicts.
+Conflicts don't stop axe, they're returned so they can be reported at startup.
+*/
+func setKeyBindings(app *throwing.AppView, bindings map[string]string) ([]string, error) {
+	global := app.GlobalKeys()
+	appBindings := map[string]string{}
+	for name, spec := range bindings {
+		if _, ok := global[name]; ok {
+			appBindings[name] = spec
+			continue
+		}
+		if !knownAction(name) {
+			return nil, fmt.Errorf("unknown key binding %q", name)
+		}
+		keys, err := throwing.ParseKeys(spec)
+		if err != nil {
+			return nil, fmt.Errorf("key binding %s: %v", name, err)
+		}
+		actionKeys[name] = keys
+	}
+	conflicts, err := app.SetKeyBindings(appBindings)
+	if err != nil {
+		return nil, err
+	}
+	// leaving a dialog and leaving a nested table share q on purpose
+	global = app.GlobalKeys()
+	delete(global, throwing.KeyBack)
+
+	// conflicts between global keys only are already in conflicts, the rest is collected per kind
+	globalOnly := map[string]bool{}
+	for _, c := range throwing.KeyConflicts(global) {
+		globalOnly[c] = true
+	}
+	kinds := map[string][]string{}
+	check := func(kind string, actions []kindAction) {
+		keys := map[string]throwing.Keys{}
+		for name, k := range global {
+			keys[name] = k
+		}
+		for _, a := range actions {
+			// a kind action replaces the generic action of the same name
+			if _, ok := keys[a.Name]; !ok {
+				keys[a.Name] = keysFor(kind, a)
+			}
+		}
+		for _, c := range throwing.KeyConflicts(keys) {
+			if !globalOnly[c] {
+				kinds[c] = append(kinds[c], kind)
+			}
+		}
+	}
+	pages := map[string]bool{}
+	for _, kind := range PageNav {
+		pages[kind] = true
+	}
+	check(k8sKind, rootActions)
+	for _, kind := range kindsWithActions {
+		if pages[kind] && !resourcePages[kind] {
+			check(kind, append(kindActions(kind), rootActions...))
+		} else {
+			check(kind, append(kindActions(kind), genericActions...))
+		}
+	}
+	for c, on := range kinds {
+		conflicts = append(conflicts, fmt.Sprintf("%s on %s", c, strings.Join(on, ", ")))
+	}
+	sort.Strings(conflicts)
+	return conflicts, nil
+}
+
+// reportKeyConflicts logs every conflict and shows a summary in the status bar
+func reportKeyConflicts(app *throwing.AppView, conflicts []string) {
+	if len(conflicts) == 0 {
+		return
+	}
+	for _, c := range conflicts {
+		logrus.Warnf("key binding conflict: %s", c)
+	}
+	app.Notify(fmt.Sprintf("%d key binding conflicts: %s", len(conflicts), strings.Join(conflicts, "; ")), throwing.SeverityWarning)
+}
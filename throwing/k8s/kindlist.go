@@ -0,0 +1,126 @@
+package k8s
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/urfave/cli"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+)
+
+/*
+Kinds prints what can be navigated to, for axe kinds: the pages of ViewMap with their PageNav key first,
+then every listable resource with the names the goto action and the command line take for it.
+c is the context of the app, the global flags are its flags.
+*/
+func Kinds(c *cli.Context) error {
+	_, d, err := configure(c)
+	if err != nil {
+		return err
+	}
+	keys := map[string]string{}
+	for key, kind := range PageNav {
+		keys[kind] = string(key)
+	}
+
+	resources, err := listableResources(d)
+	if err != nil {
+		return err
+	}
+	namespaced := map[string]string{}
+	for _, r := range resources {
+		namespaced[kindOf(r.gvr)] = fmt.Sprint(r.namespaced)
+	}
+
+	w := tabwriter.NewWriter(c.App.Writer, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "VIEW\tTITLE\tKEY\tNAMESPACED\tALIASES")
+	// built in pages like helm or events aren't resources, they have no namespaced column to speak of
+	for _, kind := range pageKinds() {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t-\n", kind, ViewMap[kind].Kind.Title, orDash(keys[kind]), orDash(namespaced[kind]))
+	}
+	for _, r := range resources {
+		kind := kindOf(r.gvr)
+		fmt.Fprintf(w, "%s\t%s\t%s\t%t\t%s\n", kind, r.title, orDash(keys[kind]), r.namespaced, orDash(strings.Join(r.aliases, ",")))
+	}
+	return w.Flush()
+}
+
+// pageKinds are the kinds of ViewMap, the footer pages in their order and then the others by name
+func pageKinds() []string {
+	var kinds, rest []string
+	listed := map[string]bool{}
+	for _, f := range Footers {
+		kinds = append(kinds, f.Kind)
+		listed[f.Kind] = true
+	}
+	for kind := range ViewMap {
+		if !listed[kind] {
+			rest = append(rest, kind)
+		}
+	}
+	sort.Strings(rest)
+	return append(kinds, rest...)
+}
+
+type listableResource struct {
+	gvr        schema.GroupVersionResource
+	title      string
+	namespaced bool
+	aliases    []string
+}
+
+// listableResources are the preferred versions of the resources that can be listed, with short names and configured aliases
+func listableResources(d discovery.DiscoveryInterface) ([]listableResource, error) {
+	// discovery fails as a whole when one aggregated API is down, the other lists are still usable
+	lists, err := d.ServerPreferredResources()
+	if len(lists) == 0 && err != nil {
+		return nil, err
+	}
+	configured := map[string][]string{}
+	for alias, typed := range kindAliases {
+		if gvr, err := resolveKind(d, typed); err == nil {
+			configured[kindOf(gvr)] = append(configured[kindOf(gvr)], alias)
+		}
+	}
+
+	var resources []listableResource
+	for _, list := range lists {
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil {
+			continue
+		}
+		for _, r := range list.APIResources {
+			// subresources like pods/log can't be listed
+			if strings.Contains(r.Name, "/") || !hasVerb(r.Verbs, "list") {
+				continue
+			}
+			gvr := gv.WithResource(r.Name)
+			aliases := append(append([]string(nil), r.ShortNames...), configured[kindOf(gvr)]...)
+			sort.Strings(aliases)
+			resources = append(resources, listableResource{gvr: gvr, title: r.Kind, namespaced: r.Namespaced, aliases: aliases})
+		}
+	}
+	sort.Slice(resources, func(i, j int) bool {
+		return kindOf(resources[i].gvr) < kindOf(resources[j].gvr)
+	})
+	return resources, nil
+}
+
+func hasVerb(verbs []string, verb string) bool {
+	for _, v := range verbs {
+		if v == verb {
+			return true
+		}
+	}
+	return false
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,435 @@
+package k8s
+
+import (
+	"bytes"
+
+	"github.com/gdamore/tcell"
+	"github.com/rancher/axe/throwing"
+	"github.com/rancher/axe/throwing/types"
+)
+
+// builtinKinds are the kinds axe links to from its own pages, keyed like kindActions
+var builtinKinds = map[string]wrapper{
+	"pods":             {version: "v1", name: "pods"},
+	"nodes":            {version: "v1", name: "nodes"},
+	"namespaces":       {version: "v1", name: "namespaces"},
+	"events":           {version: "v1", name: "events"},
+	"deployments.apps": {group: "apps", version: "v1", name: "deployments"},
+	"replicasets.apps": {group: "apps", version: "v1", name: "replicasets"},
+	"jobs.batch":       {group: "batch", version: "v1", name: "jobs"},
+}
+
+// relatedKinds are the kinds whose tables show something of a change to the key kind, e.g. the ready count of a deployment
+var relatedKinds = map[string][]string{
+	"pods":              {"deployments.apps", "replicasets.apps", "statefulsets.apps", "daemonsets.apps", "jobs.batch", jobsKind, podContainersKind},
+	"replicasets.apps":  {"deployments.apps", "pods"},
+	"deployments.apps":  {"replicasets.apps", "pods"},
+	"statefulsets.apps": {"pods"},
+	"daemonsets.apps":   {"pods"},
+	"jobs.batch":        {"cronjobs.batch", jobsKind, "pods"},
+	"cronjobs.batch":    {"jobs.batch"},
+}
+
+// publishChange tells the tables of kind and of its related kinds that an object changed
+func publishChange(t *throwing.TableView, eventType, kind, namespace, name string) {
+	t.Publish(throwing.Event{Kind: kind, Type: eventType, Namespace: namespace, Name: name})
+	for _, related := range relatedKinds[kind] {
+		t.Publish(throwing.Event{Kind: related, Type: throwing.EventChanged})
+	}
+}
+
+// kindAction is an action only offered on tables of one resource kind
+type kindAction struct {
+	types.Action
+	run func(t *throwing.TableView)
+}
+
+// refresherForKind returns the refresher of kinds axe renders itself, the server side table otherwise
+func refresherForKind(w wrapper) func(b *bytes.Buffer) error {
+	switch w.kind() {
+	case "resourcequotas":
+		return w.refreshQuotas
+	case "limitranges":
+		return w.refreshLimitRanges
+	case hpaKind:
+		return w.refreshHPAs
+	case "persistentvolumeclaims":
+		return w.refreshClaims
+	case "persistentvolumes":
+		return w.refreshVolumes
+	case "ingresses.extensions", "ingresses.networking.k8s.io":
+		return w.refreshIngresses
+	}
+	return w.refreshResource
+}
+
+var setImageAction = kindAction{
+	Action: types.Action{
+		Name:        "set image",
+		Shortcut:    "i",
+		Description: "change container images",
+	},
+	run: setImage,
+}
+
+// kindActions is keyed by the group qualified resource name, e.g. cronjobs.batch
+func kindActions(kind string) []kindAction {
+	switch kind {
+	case bookmarksKind:
+		return []kindAction{
+			{
+				Action: types.Action{
+					Name:        "remove",
+					Shortcut:    "d",
+					Description: "remove the bookmark",
+				},
+				run: toggleBookmark,
+			},
+		}
+	case "networkpolicies.networking.k8s.io", "networkpolicies.extensions":
+		return []kindAction{
+			action("inspect", "v", "the pods the policy applies to and the peers it allows", inspectNetworkPolicy),
+		}
+	case imagesKind:
+		return []kindAction{
+			action("scan", "s", "scan the image for vulnerabilities", scanImage),
+			action("result", "v", "the result of the last scan", showImageScan),
+		}
+	case subjectsKind:
+		return []kindAction{
+			action("permissions", "v", "the permission matrix of the subject", subjectPermissions),
+		}
+	case "serviceaccounts":
+		return []kindAction{
+			action("permissions", "v", "the permission matrix of the service account", serviceAccountPermissions),
+		}
+	case alertmanagerKind:
+		return []kindAction{
+			action("silence", "s", "silence alerts with the same labels", silenceAlert),
+			action("unsilence", "u", "expire the silences of the alert", expireSilences),
+		}
+	case prometheusKind:
+		return []kindAction{
+			action("query", "a", "add a PromQL query to the page", askPromQuery),
+			action("remove", "d", "remove the query", removePromQuery),
+			action("graph", "v", "chart the query over the last hour", graphPromQuery),
+		}
+	case canIKind:
+		return []kindAction{
+			action("check", "a", "check whether a verb on a resource is allowed", askAccess),
+			action("remove", "d", "remove the check", removeAccessCheck),
+		}
+	case certificatesKind:
+		return []kindAction{
+			action("renew", "R", "have cert-manager issue the certificate again now", renewCertificate),
+		}
+	case "pods":
+		return []kindAction{
+			action("security", "P", "show or hide the SECURITY column", togglePodSecurity),
+			action("usage", "U", "chart CPU and memory of the last hour from Prometheus", podUsageGraphs),
+			action("probe", "K", "reach the pod from a debug pod: DNS, its ports, HTTP", probePod),
+			{
+				Action: types.Action{
+					Name:        "containers",
+					Shortcut:    "c",
+					Description: "containers with state, restarts and probes",
+				},
+				run: viewContainers,
+			},
+			{
+				Action: types.Action{
+					Name:        "split logs",
+					Shortcut:    "L",
+					Description: "follow the logs of the containers in a pane each",
+				},
+				run: splitLogs,
+			},
+			{
+				Action: types.Action{
+					Name:        "command",
+					Shortcut:    "X",
+					Description: "run a command in the pod, Up and Down go through earlier ones",
+				},
+				run: podCommand,
+			},
+		}
+	case podContainersKind:
+		return []kindAction{
+			{
+				Action: types.Action{
+					Name:        "logs",
+					Shortcut:    "l",
+					Description: "follow the logs of the container",
+				},
+				run: containerLogs,
+			},
+			{
+				Action: types.Action{
+					Name:        "exec",
+					Shortcut:    "x",
+					Description: "open a shell in the container",
+				},
+				run: containerExec,
+			},
+			{
+				Action: types.Action{
+					Name:        "command",
+					Shortcut:    "X",
+					Description: "run a command in the container, Up and Down go through earlier ones",
+				},
+				run: containerCommand,
+			},
+		}
+	case eventsKind:
+		return []kindAction{
+			{
+				Action: types.Action{
+					Name:        "follow",
+					Shortcut:    "f",
+					Description: "keep the newest event selected",
+				},
+				run: toggleEventFollow,
+			},
+			{
+				Action: types.Action{
+					Name:        "filter",
+					Shortcut:    "F",
+					Description: "filter by namespace, kind, reason or type",
+				},
+				run: editEventFilter,
+			},
+		}
+	case jobsKind, "jobs.batch":
+		return []kindAction{
+			action("rerun", "R", "create a job with the same spec and follow its pods", rerunJob),
+		}
+	case "cronjobs.batch":
+		return []kindAction{
+			{
+				Action: types.Action{
+					Name:        "trigger",
+					Shortcut:    "t",
+					Description: "create a job from the cronjob",
+				},
+				run: triggerCronJob,
+			},
+		}
+	case "nodes":
+		return []kindAction{
+			{
+				Action: types.Action{
+					Name:        "drain",
+					Shortcut:    "v",
+					Description: "cordon the node and evict its pods",
+				},
+				run: drainNode,
+			},
+			{
+				Action: types.Action{
+					Name:        "ssh",
+					Shortcut:    "x",
+					Description: "open an ssh session on the node",
+				},
+				run: sshNode,
+			},
+			{
+				Action: types.Action{
+					Name:        "taint",
+					Shortcut:    "t",
+					Description: "edit the node taints",
+				},
+				run: editTaints,
+			},
+			action("logs", "l", "follow a journal unit or a file of /var/log through the API server", nodeLog),
+		}
+	case "secrets":
+		return []kindAction{
+			{
+				Action: types.Action{
+					Name:        "decode",
+					Shortcut:    "v",
+					Description: "view decoded secret data",
+				},
+				run: viewSecret,
+			},
+		}
+	case "configmaps":
+		return []kindAction{
+			{
+				Action: types.Action{
+					Name:        "browse",
+					Shortcut:    "v",
+					Description: "browse configmap data by key",
+				},
+				run: viewConfigMap,
+			},
+		}
+	case "deployments.apps":
+		return []kindAction{
+			{
+				Action: types.Action{
+					Name:        "x-ray",
+					Shortcut:    "o",
+					Description: "show replicasets, pods and containers as a tree",
+				},
+				run: xrayDeployment,
+			},
+			setImageAction,
+		}
+	case "daemonsets.apps", "statefulsets.apps":
+		return []kindAction{
+			setImageAction,
+		}
+	case hpaKind:
+		return []kindAction{
+			{
+				Action: types.Action{
+					Name:        "events",
+					Shortcut:    "v",
+					Description: "show the scale events",
+				},
+				run: hpaEvents,
+			},
+			{
+				Action: types.Action{
+					Name:        "bounds",
+					Shortcut:    "m",
+					Description: "edit min and max replicas",
+				},
+				run: editHPABounds,
+			},
+		}
+	case "persistentvolumeclaims":
+		return []kindAction{
+			{
+				Action: types.Action{
+					Name:        "volume",
+					Shortcut:    "p",
+					Description: "show the bound persistent volume",
+				},
+				run: claimVolume,
+			},
+			{
+				Action: types.Action{
+					Name:        "pods",
+					Shortcut:    "o",
+					Description: "show the pods mounting the claim",
+				},
+				run: claimPods,
+			},
+		}
+	case "persistentvolumes":
+		return []kindAction{
+			{
+				Action: types.Action{
+					Name:        "claim",
+					Shortcut:    "c",
+					Description: "show the claim bound to the volume",
+				},
+				run: volumeClaim,
+			},
+		}
+	case "ingresses.extensions", "ingresses.networking.k8s.io":
+		return []kindAction{
+			{
+				Action: types.Action{
+					Name:        "browse",
+					Shortcut:    "w",
+					Description: "open the url in the browser",
+				},
+				run: openIngress,
+			},
+		}
+	case "services":
+		return []kindAction{
+			{
+				Action: types.Action{
+					Name:        "endpoints",
+					Shortcut:    "v",
+					Description: "show ports and endpoints",
+				},
+				run: viewService,
+			},
+			{
+				Action: types.Action{
+					Name:        "curl",
+					Shortcut:    "u",
+					Description: "port-forward and send a test request",
+				},
+				run: curlService,
+			},
+			action("probe", "K", "resolve and reach the service from a debug pod in its namespace", probeService),
+		}
+	case helmKind:
+		return []kindAction{
+			{
+				Action: types.Action{
+					Name:        "history",
+					Shortcut:    "h",
+					Description: "show the release history",
+				},
+				run: helmHistoryView,
+			},
+			{
+				Action: types.Action{
+					Name:        "values",
+					Shortcut:    "v",
+					Description: "show the release values",
+				},
+				run: helmValues,
+			},
+			{
+				Action: types.Action{
+					Name:        "rollback",
+					Shortcut:    "b",
+					Description: "roll back to the previous revision",
+				},
+				run: helmRollback,
+			},
+			{
+				Action: types.Action{
+					Name:        "uninstall",
+					Shortcut:    "u",
+					Description: "uninstall the release",
+				},
+				run: helmUninstall,
+			},
+		}
+	case helmHistoryKind:
+		return []kindAction{
+			{
+				Action: types.Action{
+					Name:        "values",
+					Shortcut:    "v",
+					Description: "show the values of this revision",
+				},
+				run: helmValues,
+			},
+			{
+				Action: types.Action{
+					Name:        "rollback",
+					Shortcut:    "b",
+					Description: "roll back to this revision",
+				},
+				run: helmRollback,
+			},
+		}
+	}
+	return nil
+}
+
+func actionsForKind(kind string) []types.Action {
+	var actions []types.Action
+	for _, a := range bound(kind, kindActions(kind)) {
+		if !permitted(kind, a) {
+			continue
+		}
+		actions = append(actions, a.Action)
+	}
+	return actions
+}
+
+// runKindAction runs the kind specific action bound to event, it reports whether one was found
+func runKindAction(t *throwing.TableView, event *tcell.EventKey) bool {
+	return runAction(t, kindActions(t.GetResourceKind()), event)
+}
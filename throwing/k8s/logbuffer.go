@@ -0,0 +1,61 @@
+package k8s
+
+import (
+	"strings"
+	"sync"
+)
+
+// defaultLogLines is how many lines a log view keeps unless logLines in the config says otherwise
+const defaultLogLines = 10000
+
+// maxLogLines is set from logLines in the config, zero keeps every line
+var maxLogLines = defaultLogLines
+
+/*
+logBuffer is a ring of the last lines written to a log view.
+The view itself only gets appended to, once it holds a tenth more than max lines it's reset to the ring,
+so trimming the top doesn't cost a redraw of the whole log on every line.
+*/
+type logBuffer struct {
+	lock    sync.Mutex
+	lines   []string
+	next    int
+	full    bool
+	shown   int
+	partial string
+}
+
+func newLogBuffer(max int) *logBuffer {
+	return &logBuffer{lines: make([]string, max)}
+}
+
+// add takes what was written and reports whether the view has grown enough to be reset to text, b has to be locked
+func (b *logBuffer) add(p []byte) (text string, trim bool) {
+	data := b.partial + string(p)
+	end := strings.LastIndexByte(data, '\n')
+	b.partial = data[end+1:]
+	if end < 0 {
+		return "", false
+	}
+	for _, line := range strings.Split(data[:end], "\n") {
+		b.lines[b.next] = line
+		b.next = (b.next + 1) % len(b.lines)
+		b.full = b.full || b.next == 0
+		b.shown++
+	}
+	if b.shown <= len(b.lines)+len(b.lines)/10 {
+		return "", false
+	}
+	b.shown = len(b.lines)
+	return b.text(), true
+}
+
+// text has to be called with b locked, the lines oldest first and what's still missing its newline
+func (b *logBuffer) text() string {
+	var lines []string
+	if b.full {
+		lines = append(lines, b.lines[b.next:]...)
+	}
+	lines = append(lines, b.lines[:b.next]...)
+	return strings.Join(lines, "\n") + "\n" + b.partial
+}
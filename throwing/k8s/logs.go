@@ -0,0 +1,237 @@
+package k8s
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gdamore/tcell"
+	"github.com/rancher/axe/throwing"
+	"github.com/rivo/tview"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// logColors tell the containers of a pod apart when their logs are interleaved
+var logColors = []string{"aqua", "yellow", "fuchsia", "lime", "orange", "skyblue", "pink", "gold"}
+
+// podLogs follows the logs of one container of the pod, of all of them when container is empty
+func podLogs(t *throwing.TableView, namespace, name, container string) {
+	args := []string{"logs", "-f", "-n", namespace, name}
+	if container != "" {
+		args = append(args, "-c", container)
+	} else {
+		args = append(args, "--all-containers")
+	}
+	if inTmux(t, "logs", name, kubectl(args...)) {
+		return
+	}
+
+	containers := []string{container}
+	if container == "" {
+		pod, err := t.GetClientSet().CoreV1().Pods(namespace).Get(name, metav1.GetOptions{})
+		if err != nil {
+			t.Notify(err.Error(), throwing.SeverityError)
+			return
+		}
+		containers = podContainerNames(pod)
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	logbox := newLogView(t, fmt.Sprintf("logs - (%s)", name))
+
+	// a single container needs no prefix to tell its lines apart
+	for i, c := range containers {
+		prefix := ""
+		if len(containers) > 1 {
+			prefix = fmt.Sprintf("[%s]%s[-] ", logColors[i%len(logColors)], c)
+		}
+		c := c
+		t.Go(func() { streamLogs(ctx, t.GetClientSet(), namespace, name, c, prefix, logbox) })
+	}
+
+	newpage := tview.NewPages().AddPage("logs", logbox, true, true)
+	t.SwitchPage(t.GetCurrentPage(), newpage)
+	t.OnLeave(newpage, cancel)
+}
+
+/*
+logView shows a log as it grows and keeps its end in sight.
+Scrolling up pauses that so earlier output can be read while the pod keeps logging, f or End resumes it.
+*/
+type logView struct {
+	*tview.TextView
+	title  string
+	follow int32
+	buffer *logBuffer
+}
+
+func newLogView(t *throwing.TableView, title string) *logView {
+	l := &logView{TextView: tview.NewTextView(), title: title, follow: 1}
+	if maxLogLines > 0 {
+		l.buffer = newLogBuffer(maxLogLines)
+	}
+	l.SetTitle(title)
+	l.SetBorder(true)
+	l.SetTitleColor(tcell.ColorPurple)
+	l.SetDynamicColors(true)
+	l.SetBackgroundColor(tcell.ColorBlack)
+	// called from the goroutines writing the log
+	l.SetChangedFunc(func() {
+		if atomic.LoadInt32(&l.follow) == 1 {
+			l.ScrollToEnd()
+		}
+		t.GetApplication().Draw()
+	})
+	l.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch {
+		case event.Key() == tcell.KeyUp, event.Key() == tcell.KeyPgUp, event.Key() == tcell.KeyHome, event.Key() == tcell.KeyCtrlU,
+			event.Key() == tcell.KeyRune && (event.Rune() == 'k' || event.Rune() == 'g'):
+			l.setFollow(false)
+		case event.Key() == tcell.KeyEnd, event.Key() == tcell.KeyRune && event.Rune() == 'G':
+			l.setFollow(true)
+		case event.Key() == tcell.KeyRune && event.Rune() == 'f':
+			l.setFollow(atomic.LoadInt32(&l.follow) == 0)
+			return nil
+		}
+		return event
+	})
+	return l
+}
+
+// Write appends to the view, keeping no more than the last maxLogLines lines and a tenth
+func (l *logView) Write(p []byte) (int, error) {
+	if l.buffer == nil {
+		return l.TextView.Write(p)
+	}
+	// the containers of a pod write from goroutines of their own, a reset must not lose or repeat their lines
+	l.buffer.lock.Lock()
+	defer l.buffer.lock.Unlock()
+	if text, trim := l.buffer.add(p); trim {
+		l.TextView.SetText(text)
+		return len(p), nil
+	}
+	return l.TextView.Write(p)
+}
+
+// setFollow pauses or resumes following the end of the log, the title says when it's paused
+func (l *logView) setFollow(follow bool) {
+	if follow {
+		atomic.StoreInt32(&l.follow, 1)
+		l.SetTitle(l.title)
+		l.ScrollToEnd()
+		return
+	}
+	atomic.StoreInt32(&l.follow, 0)
+	l.SetTitle(l.title + " (paused, f to follow)")
+}
+
+// podContainerNames lists the init containers and then the containers, like kubectl logs --all-containers
+func podContainerNames(pod *v1.Pod) []string {
+	var names []string
+	for _, c := range pod.Spec.InitContainers {
+		names = append(names, c.Name)
+	}
+	for _, c := range pod.Spec.Containers {
+		names = append(names, c.Name)
+	}
+	return names
+}
+
+// streamLogs follows the log of one container into out until ctx is done, every line gets prefix
+func streamLogs(ctx context.Context, clientset kubernetes.Interface, namespace, pod, container, prefix string, out io.Writer) {
+	stream, err := clientset.CoreV1().Pods(namespace).GetLogs(pod, &v1.PodLogOptions{Container: container, Follow: true}).Stream()
+	if err != nil {
+		fmt.Fprintf(out, "%s[red]%s[-]\n", prefix, tview.Escape(err.Error()))
+		return
+	}
+	// the stream has no context of its own, closing it ends the read
+	defer stopWith(ctx, func() { stream.Close() })()
+	defer stream.Close()
+
+	reader := bufio.NewReader(stream)
+	for {
+		line, err := reader.ReadString('\n')
+		if line != "" {
+			fmt.Fprintf(out, "%s%s\n", prefix, tview.TranslateANSI(tview.Escape(strings.TrimSuffix(line, "\n"))))
+		}
+		if err != nil {
+			if err != io.EOF && ctx.Err() == nil {
+				fmt.Fprintf(out, "%s[red]%s[-]\n", prefix, tview.Escape(err.Error()))
+			}
+			return
+		}
+	}
+}
+
+// splitLogs asks which containers of the pod to follow and gives each of them a pane of its own
+func splitLogs(t *throwing.TableView) {
+	namespace, name := getNamespaceAndName(t)
+	pod, err := t.GetClientSet().CoreV1().Pods(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		t.Notify(err.Error(), throwing.SeverityError)
+		return
+	}
+
+	// init containers are done by the time anyone debugs the sidecars
+	var fields []throwing.FormField
+	for _, c := range pod.Spec.InitContainers {
+		fields = append(fields, throwing.FormField{Label: c.Name + " (init)", Name: c.Name, Checkbox: true, Default: "false"})
+	}
+	for _, c := range pod.Spec.Containers {
+		fields = append(fields, throwing.FormField{Label: c.Name, Checkbox: true, Default: "true"})
+	}
+	t.ShowForm(fmt.Sprintf("Split logs - (%s)", name), "Open", fields, func(values throwing.FormValues) error {
+		var containers []string
+		for _, c := range podContainerNames(pod) {
+			if values.Bool(c) {
+				containers = append(containers, c)
+			}
+		}
+		if len(containers) == 0 {
+			return fmt.Errorf("pick at least one container")
+		}
+		// the form gives the focus back when it closes, the panes take it after that
+		go t.GetApplication().QueueUpdateDraw(func() {
+			showSplitLogs(t, namespace, name, containers)
+		})
+		return nil
+	})
+}
+
+// showSplitLogs stacks a log pane per container, Tab and Shift+Tab move between them
+func showSplitLogs(t *throwing.TableView, namespace, name string, containers []string) {
+	ctx, cancel := context.WithCancel(t.Context())
+	panes := tview.NewFlex().SetDirection(tview.FlexRow)
+	var views []*logView
+	for i, c := range containers {
+		pane := newLogView(t, fmt.Sprintf("%s - (%s)", c, name))
+		pane.SetTitleColor(tcell.GetColor(logColors[i%len(logColors)]))
+		views = append(views, pane)
+		panes.AddItem(pane, 0, 1, i == 0)
+		c := c
+		t.Go(func() { streamLogs(ctx, t.GetClientSet(), namespace, name, c, "", pane) })
+	}
+
+	focused := 0
+	panes.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Key() {
+		case tcell.KeyTab:
+			focused = (focused + 1) % len(views)
+		case tcell.KeyBacktab:
+			focused = (focused + len(views) - 1) % len(views)
+		default:
+			return event
+		}
+		t.GetApplication().SetFocus(views[focused])
+		return nil
+	})
+
+	newpage := tview.NewPages().AddPage("logs", panes, true, true)
+	t.SwitchPage(t.GetCurrentPage(), newpage)
+	t.OnLeave(newpage, cancel)
+}
@@ -0,0 +1,140 @@
+package k8s
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/rancher/axe/throwing"
+	"github.com/sirupsen/logrus"
+)
+
+const namespaceScopesState = "namespaceScopes"
+
+/*
+namespaceScopes tracks which kinds list one namespace rather than all of them.
+Every table starts out the way --namespace says, the scope action flips a kind between the two.
+listed remembers the namespace a kind was last listed in without its NAMESPACE column.
+*/
+var namespaceScopes = struct {
+	sync.Mutex
+	scoped map[string]bool
+	listed map[string]string
+}{
+	scoped: map[string]bool{},
+	listed: map[string]string{},
+}
+
+// currentNamespace is the one of --namespace, else the one of the kubeconfig context
+func currentNamespace() string {
+	if connection.namespace != "" {
+		return connection.namespace
+	}
+	namespace, _, err := clientConfig().Namespace()
+	if err != nil || namespace == "" {
+		return "default"
+	}
+	return namespace
+}
+
+// scopedNamespace is the namespace kind lists, empty for all namespaces
+func scopedNamespace(kind string) string {
+	namespaceScopes.Lock()
+	scoped, ok := namespaceScopes.scoped[kind]
+	namespaceScopes.Unlock()
+	if !ok {
+		scoped = connection.namespace != ""
+	}
+	if !scoped {
+		return ""
+	}
+	return currentNamespace()
+}
+
+// setListedNamespace records that kind was listed in namespace without a NAMESPACE column, empty when it has one
+func setListedNamespace(kind, namespace string) {
+	namespaceScopes.Lock()
+	defer namespaceScopes.Unlock()
+	namespaceScopes.listed[kind] = namespace
+}
+
+func listedNamespace(kind string) string {
+	namespaceScopes.Lock()
+	defer namespaceScopes.Unlock()
+	return namespaceScopes.listed[kind]
+}
+
+// restoreNamespaceScopes loads the scopes saved with the session state
+func restoreNamespaceScopes(saved string) {
+	if saved == "" {
+		return
+	}
+	scoped := map[string]bool{}
+	if err := json.Unmarshal([]byte(saved), &scoped); err != nil {
+		logrus.Debugf("ignoring saved namespace scopes: %v", err)
+		return
+	}
+	namespaceScopes.Lock()
+	defer namespaceScopes.Unlock()
+	for kind, s := range scoped {
+		namespaceScopes.scoped[kind] = s
+	}
+}
+
+/*
+listScope is the namespace the refresher of w lists and whether the namespaces action scoped it there.
+A scoped table leaves its NAMESPACE column out like the server side tables, see writeScopedTable.
+*/
+func (w wrapper) listScope() (string, bool) {
+	if w.namespace != "" {
+		setListedNamespace(w.kind(), "")
+		return w.namespace, false
+	}
+	namespace := scopedNamespace(w.kind())
+	setListedNamespace(w.kind(), namespace)
+	return namespace, namespace != ""
+}
+
+// writeScopedTable writes rows starting with their namespace, without that column when the table is scoped
+func writeScopedTable(b *bytes.Buffer, scoped bool, header []string, rows [][]string) {
+	if scoped {
+		header = header[1:]
+		for i := range rows {
+			rows[i] = rows[i][1:]
+		}
+	}
+	writeTable(b, header, rows)
+}
+
+// toggleNamespaces flips the table between all namespaces and the current one
+func toggleNamespaces(t *throwing.TableView) {
+	kind := t.GetResourceKind()
+	scoped := scopedNamespace(kind) == ""
+	namespaceScopes.Lock()
+	namespaceScopes.scoped[kind] = scoped
+	saved, err := json.Marshal(namespaceScopes.scoped)
+	namespaceScopes.Unlock()
+	if err == nil {
+		t.SetStateValue(namespaceScopesState, string(saved))
+	}
+
+	if scoped {
+		t.Notify(fmt.Sprintf("%s in namespace %s", kind, currentNamespace()), throwing.SeverityInfo)
+	} else {
+		t.Notify(fmt.Sprintf("%s in all namespaces", kind), throwing.SeverityInfo)
+	}
+	t.Refresh()
+}
+
+// columnIndex finds the column with the given header, -1 when the table has none
+func columnIndex(t *throwing.TableView, header string) int {
+	table := t.GetTable()
+	for col := 0; col < table.GetColumnCount(); col++ {
+		if strings.TrimPrefix(table.GetCell(0, col).Text, "[white]") == header {
+			return col
+		}
+	}
+	return -1
+}
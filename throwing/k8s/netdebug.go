@@ -0,0 +1,260 @@
+package k8s
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rancher/axe/throwing"
+	"github.com/rivo/tview"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/rand"
+)
+
+const (
+	defaultNetDebugImage = "nicolaka/netshoot"
+	// netDebugDeadline ends the debug pod on its own should axe not get to delete it
+	netDebugDeadline = 120
+	// probeMarker starts the lines the probe script separates its probes with
+	probeMarker = "### "
+	// probeOutputLines is how much of what a probe printed the report keeps
+	probeOutputLines = 4
+)
+
+// netDebugImage is netDebugImage from the config, any image with sh, nslookup, nc and curl does
+var netDebugImage = defaultNetDebugImage
+
+// netProbe is a command the debug pod runs, it passes when the command exits with 0
+type netProbe struct {
+	name, command string
+}
+
+type probeResult struct {
+	probe  netProbe
+	passed bool
+	output []string
+}
+
+// httpPort guesses whether a port speaks HTTP from its name and number, curl only runs against those
+func httpPort(name string, port int32) (bool, string) {
+	name = strings.ToLower(name)
+	switch {
+	case strings.HasPrefix(name, "https"), port == 443, port == 8443:
+		return true, "https"
+	case strings.HasPrefix(name, "http"), strings.HasPrefix(name, "web"), port == 80, port == 8080:
+		return true, "http"
+	}
+	return false, ""
+}
+
+// portProbes connect to host on port, and send a request when it looks like HTTP
+func portProbes(host, name string, port int32, protocol v1.Protocol) []netProbe {
+	if protocol == v1.ProtocolUDP {
+		return []netProbe{{fmt.Sprintf("udp %d", port), fmt.Sprintf("nc -z -u -v -w 3 %s %d", host, port)}}
+	}
+	probes := []netProbe{{fmt.Sprintf("tcp %d", port), fmt.Sprintf("nc -z -v -w 3 %s %d", host, port)}}
+	if ok, scheme := httpPort(name, port); ok {
+		probes = append(probes, netProbe{
+			fmt.Sprintf("%s %d", scheme, port),
+			fmt.Sprintf(`curl -sS -k -o /dev/null -m 5 -w "HTTP %%{http_code} in %%{time_total}s\n" %s://%s:%d/`, scheme, host, port),
+		})
+	}
+	return probes
+}
+
+/*
+serviceProbes resolve the service by the short name its clients use, from a pod in its namespace,
+then connect to every port of it. An ExternalName service only gets the lookup.
+*/
+func serviceProbes(svc *v1.Service) []netProbe {
+	host := svc.Name + "." + svc.Namespace
+	probes := []netProbe{{"dns", "nslookup " + host}}
+	if svc.Spec.Type == v1.ServiceTypeExternalName {
+		return probes
+	}
+	for _, p := range svc.Spec.Ports {
+		probes = append(probes, portProbes(host, p.Name, p.Port, p.Protocol)...)
+	}
+	return probes
+}
+
+// podProbes check the cluster DNS, then connect to the declared ports of the pod or ping it when there are none
+func podProbes(pod *v1.Pod) []netProbe {
+	probes := []netProbe{{"dns", "nslookup kubernetes.default"}}
+	ip := pod.Status.PodIP
+	if ip == "" {
+		return probes
+	}
+	ports := 0
+	for _, c := range pod.Spec.Containers {
+		for _, p := range c.Ports {
+			probes = append(probes, portProbes(ip, p.Name, p.ContainerPort, p.Protocol)...)
+			ports++
+		}
+	}
+	if ports == 0 {
+		probes = append(probes, netProbe{"ping", "ping -c 2 -W 2 " + ip})
+	}
+	return probes
+}
+
+// probeScript runs every probe whatever the others did, with markers around them for parseProbes
+func probeScript(probes []netProbe) string {
+	b := &strings.Builder{}
+	for i, p := range probes {
+		fmt.Fprintf(b, "echo '%sprobe %d'; %s 2>&1; echo \"%sexit $?\"; ", probeMarker, i, p.command, probeMarker)
+	}
+	return b.String()
+}
+
+// parseProbes reads what probeScript printed, a probe without an exit line didn't finish and failed
+func parseProbes(probes []netProbe, output string) []probeResult {
+	results := make([]probeResult, len(probes))
+	for i, p := range probes {
+		results[i].probe = p
+	}
+	current := -1
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, probeMarker+"probe "):
+			current, _ = strconv.Atoi(strings.TrimPrefix(line, probeMarker+"probe "))
+			if current < 0 || current >= len(results) {
+				current = -1
+			}
+		case strings.HasPrefix(line, probeMarker+"exit "):
+			if current >= 0 {
+				results[current].passed = strings.TrimPrefix(line, probeMarker+"exit ") == "0"
+			}
+			current = -1
+		case current >= 0 && strings.TrimSpace(line) != "":
+			results[current].output = append(results[current].output, line)
+		}
+	}
+	return results
+}
+
+// probeReport is the dialog text, a line per probe and the tail of what it printed
+func probeReport(results []probeResult) string {
+	b := &strings.Builder{}
+	for _, r := range results {
+		icon, color := iconOK, "green"
+		if !r.passed {
+			icon, color = iconFailed, "red"
+		}
+		fmt.Fprintf(b, "[%s]%s %s[white]  [gray]%s[white]\n", color, icon, tview.Escape(r.probe.name), tview.Escape(r.probe.command))
+		output := r.output
+		if len(output) > probeOutputLines {
+			output = output[len(output)-probeOutputLines:]
+		}
+		for _, line := range output {
+			fmt.Fprintf(b, "    %s\n", tview.Escape(line))
+		}
+	}
+	return b.String()
+}
+
+/*
+runProbes starts a pod of netDebugImage in namespace, runs the probes in it and deletes it again.
+The pod ends itself after netDebugDeadline seconds should the delete not get through.
+*/
+func runProbes(ctx context.Context, namespace string, probes []netProbe) (string, error) {
+	name := "axe-netdebug-" + rand.String(5)
+	overrides := fmt.Sprintf(`{"spec":{"activeDeadlineSeconds":%d,"terminationGracePeriodSeconds":0}}`, netDebugDeadline)
+	cmd := kubectl("run", name, "-n", namespace, "--image", netDebugImage, "--restart", "Never",
+		"--labels", "app.kubernetes.io/created-by=axe", "--overrides", overrides,
+		"--rm", "-i", "--quiet", "--pod-running-timeout", "1m", "--command", "--", "sh", "-c", probeScript(probes))
+	out := &strings.Builder{}
+	errB := &strings.Builder{}
+	cmd.Stdout, cmd.Stderr = out, errB
+	if err := cmd.Start(); err != nil {
+		return "", err
+	}
+	defer kubectl("delete", "pod", name, "-n", namespace, "--ignore-not-found", "--wait=false").Run()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+	select {
+	case err := <-done:
+		// the probes failing fails the script, it's the report that tells
+		if err != nil && !strings.Contains(out.String(), probeMarker) {
+			return "", fmt.Errorf("%s", strings.TrimSpace(errB.String()))
+		}
+		return out.String(), nil
+	case <-ctx.Done():
+		cmd.Process.Kill()
+		<-done
+		return "", ctx.Err()
+	}
+}
+
+// probeTarget probes from a debug pod in the background and shows the report once it's back
+func probeTarget(t *throwing.TableView, namespace, target string, probes []netProbe) {
+	t.Notify(fmt.Sprintf("probing %s from a %s pod, %d probes", target, netDebugImage, len(probes)), throwing.SeverityProgress)
+	ctx := t.Context()
+	t.Go(func() {
+		output, err := runProbes(ctx, namespace, probes)
+		if err != nil {
+			t.Notify(fmt.Sprintf("probe %s: %v", target, err), throwing.SeverityError)
+			return
+		}
+		results := parseProbes(probes, output)
+		failed := 0
+		for _, r := range results {
+			if !r.passed {
+				failed++
+			}
+		}
+		severity := throwing.SeverityInfo
+		if failed > 0 {
+			severity = throwing.SeverityWarning
+		}
+		t.Notify(fmt.Sprintf("probe %s: %d of %d passed", target, len(results)-failed, len(results)), severity)
+		report := probeReport(results)
+		t.GetApplication().QueueUpdateDraw(func() {
+			t.ShowMessage(fmt.Sprintf("probe %s", target), report)
+		})
+	})
+}
+
+// probeService checks that the selected service resolves and answers on its ports
+func probeService(t *throwing.TableView) {
+	namespace, name := getNamespaceAndName(t)
+	clientset, err := newClientset()
+	if err != nil {
+		t.Notify(err.Error(), throwing.SeverityError)
+		return
+	}
+	svc, err := clientset.CoreV1().Services(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		t.Notify(err.Error(), throwing.SeverityError)
+		return
+	}
+	probeTarget(t, namespace, "svc/"+name, serviceProbes(svc))
+}
+
+// probePod checks the cluster DNS and that the selected pod answers on its ports
+func probePod(t *throwing.TableView) {
+	namespace, name := getNamespaceAndName(t)
+	clientset, err := newClientset()
+	if err != nil {
+		t.Notify(err.Error(), throwing.SeverityError)
+		return
+	}
+	pod, err := clientset.CoreV1().Pods(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		t.Notify(err.Error(), throwing.SeverityError)
+		return
+	}
+	if pod.Status.PodIP == "" {
+		t.Notify(fmt.Sprintf("%s has no IP yet", name), throwing.SeverityWarning)
+		return
+	}
+	probeTarget(t, namespace, "pod/"+name, podProbes(pod))
+}
@@ -0,0 +1,264 @@
+package k8s
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gdamore/tcell"
+	"github.com/rancher/axe/throwing"
+	"github.com/rivo/tview"
+	"k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+// maxPeerMatches is how many matching pods a peer lists before it only counts the rest
+const maxPeerMatches = 5
+
+// policyPeers resolves the selectors of a policy, pods and namespaces are listed once and only when a selector needs them
+type policyPeers struct {
+	client     kubernetes.Interface
+	namespace  string
+	pods       map[string][]v1.Pod
+	namespaces []v1.Namespace
+}
+
+func (p *policyPeers) podsIn(namespace string) ([]v1.Pod, error) {
+	if pods, ok := p.pods[namespace]; ok {
+		return pods, nil
+	}
+	list, err := p.client.CoreV1().Pods(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if p.pods == nil {
+		p.pods = map[string][]v1.Pod{}
+	}
+	p.pods[namespace] = list.Items
+	return list.Items, nil
+}
+
+// selectedPods are the pods of namespaces matching podSelector, a nil selector matches every pod
+func (p *policyPeers) selectedPods(podSelector *metav1.LabelSelector, namespaces []string) ([]string, error) {
+	selector := labels.Everything()
+	if podSelector != nil {
+		var err error
+		if selector, err = metav1.LabelSelectorAsSelector(podSelector); err != nil {
+			return nil, err
+		}
+	}
+	var names []string
+	for _, namespace := range namespaces {
+		pods, err := p.podsIn(namespace)
+		if err != nil {
+			return nil, err
+		}
+		for _, pod := range pods {
+			if !selector.Matches(labels.Set(pod.Labels)) {
+				continue
+			}
+			name := pod.Name
+			if pod.Namespace != p.namespace {
+				name = pod.Namespace + "/" + pod.Name
+			}
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (p *policyPeers) selectedNamespaces(namespaceSelector *metav1.LabelSelector) ([]string, error) {
+	if p.namespaces == nil {
+		list, err := p.client.CoreV1().Namespaces().List(metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		p.namespaces = list.Items
+	}
+	selector, err := metav1.LabelSelectorAsSelector(namespaceSelector)
+	if err != nil {
+		return nil, err
+	}
+	var selected []string
+	for _, ns := range p.namespaces {
+		if selector.Matches(labels.Set(ns.Labels)) {
+			selected = append(selected, ns.Name)
+		}
+	}
+	return selected, nil
+}
+
+// describe puts a peer in words and lists what it matches
+func (p *policyPeers) describe(peer networkingv1.NetworkPolicyPeer) (string, string, error) {
+	if peer.IPBlock != nil {
+		text := "cidr " + peer.IPBlock.CIDR
+		if len(peer.IPBlock.Except) > 0 {
+			text += " except " + strings.Join(peer.IPBlock.Except, ", ")
+		}
+		return text, "-", nil
+	}
+
+	namespaces := []string{p.namespace}
+	var parts []string
+	if peer.NamespaceSelector != nil {
+		var err error
+		if namespaces, err = p.selectedNamespaces(peer.NamespaceSelector); err != nil {
+			return "", "", err
+		}
+		parts = append(parts, "namespaces "+selectorText(peer.NamespaceSelector))
+	}
+	if peer.PodSelector != nil || peer.NamespaceSelector == nil {
+		parts = append(parts, "pods "+selectorText(peer.PodSelector))
+	}
+	pods, err := p.selectedPods(peer.PodSelector, namespaces)
+	if err != nil {
+		return "", "", err
+	}
+	return strings.Join(parts, " in "), summarize(pods, "pods"), nil
+}
+
+func selectorText(selector *metav1.LabelSelector) string {
+	if selector == nil || (len(selector.MatchLabels) == 0 && len(selector.MatchExpressions) == 0) {
+		return "(all)"
+	}
+	return metav1.FormatLabelSelector(selector)
+}
+
+// summarize lists the first maxPeerMatches names and counts the rest
+func summarize(names []string, what string) string {
+	if len(names) == 0 {
+		return "no " + what
+	}
+	if len(names) <= maxPeerMatches {
+		return strings.Join(names, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(names[:maxPeerMatches], ", "), len(names)-maxPeerMatches)
+}
+
+func policyPorts(ports []networkingv1.NetworkPolicyPort) string {
+	if len(ports) == 0 {
+		return "all ports"
+	}
+	var list []string
+	for _, port := range ports {
+		protocol := v1.ProtocolTCP
+		if port.Protocol != nil {
+			protocol = *port.Protocol
+		}
+		if port.Port == nil {
+			list = append(list, "all/"+string(protocol))
+			continue
+		}
+		list = append(list, port.Port.String()+"/"+string(protocol))
+	}
+	return strings.Join(list, ", ")
+}
+
+// policyTypes defaults the types like the API server does: Ingress always, Egress once there are egress rules
+func policyTypes(policy *networkingv1.NetworkPolicy) (ingress, egress bool) {
+	if len(policy.Spec.PolicyTypes) == 0 {
+		return true, len(policy.Spec.Egress) > 0
+	}
+	for _, pt := range policy.Spec.PolicyTypes {
+		switch pt {
+		case networkingv1.PolicyTypeIngress:
+			ingress = true
+		case networkingv1.PolicyTypeEgress:
+			egress = true
+		}
+	}
+	return ingress, egress
+}
+
+/*
+inspectNetworkPolicy shows the pods the selected policy applies to, then each ingress and egress rule
+as the peers it allows, the ports and the pods those peers match right now.
+*/
+func inspectNetworkPolicy(t *throwing.TableView) {
+	namespace, name := getNamespaceAndName(t)
+	client := t.GetClientSet()
+	policy, err := client.NetworkingV1().NetworkPolicies(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		t.Notify(err.Error(), throwing.SeverityError)
+		return
+	}
+	peers := &policyPeers{client: client, namespace: namespace}
+
+	table := tview.NewTable()
+	table.SetBorder(true)
+	table.SetTitle(fmt.Sprintf("networkpolicy - (%s/%s)", namespace, name))
+	table.SetTitleColor(tcell.ColorPurple)
+	table.SetBackgroundColor(tcell.ColorBlack)
+	table.SetSelectable(true, false)
+	table.SetFixed(1, 0)
+
+	for col, h := range []string{"RULE", "PEER", "PORTS", "MATCHES"} {
+		table.SetCell(0, col, tview.NewTableCell(h).SetSelectable(false).SetAttributes(tcell.AttrBold).SetExpansion(1))
+	}
+	row := 1
+	addRow := func(color tcell.Color, values ...string) {
+		for col, value := range values {
+			table.SetCell(row, col, tview.NewTableCell(value).SetTextColor(color).SetExpansion(1))
+		}
+		row++
+	}
+
+	selected, err := peers.selectedPods(&policy.Spec.PodSelector, []string{namespace})
+	if err != nil {
+		t.Notify(err.Error(), throwing.SeverityError)
+		return
+	}
+	addRow(tcell.ColorAqua, "applies to", "pods "+selectorText(&policy.Spec.PodSelector), "-", summarize(selected, "pods"))
+
+	ingress, egress := policyTypes(policy)
+	sections := []struct {
+		enabled bool
+		rule    string
+		peers   [][]networkingv1.NetworkPolicyPeer
+		ports   [][]networkingv1.NetworkPolicyPort
+	}{
+		{enabled: ingress, rule: "ingress"},
+		{enabled: egress, rule: "egress"},
+	}
+	for _, r := range policy.Spec.Ingress {
+		sections[0].peers = append(sections[0].peers, r.From)
+		sections[0].ports = append(sections[0].ports, r.Ports)
+	}
+	for _, r := range policy.Spec.Egress {
+		sections[1].peers = append(sections[1].peers, r.To)
+		sections[1].ports = append(sections[1].ports, r.Ports)
+	}
+	for _, s := range sections {
+		switch {
+		case !s.enabled:
+			addRow(tcell.ColorDefault, s.rule, "not restricted by this policy", "-", "-")
+			continue
+		case len(s.peers) == 0:
+			addRow(tcell.ColorRed, s.rule, "nothing allowed", "-", "-")
+			continue
+		}
+		for i, rulePeers := range s.peers {
+			rule := fmt.Sprintf("%s #%d", s.rule, i+1)
+			ports := policyPorts(s.ports[i])
+			if len(rulePeers) == 0 {
+				addRow(tcell.ColorGreen, rule, "anywhere", ports, "-")
+				continue
+			}
+			for _, peer := range rulePeers {
+				text, matches, err := peers.describe(peer)
+				if err != nil {
+					t.Notify(err.Error(), throwing.SeverityError)
+					return
+				}
+				addRow(tcell.ColorGreen, rule, text, ports, matches)
+			}
+		}
+	}
+
+	newpage := tview.NewPages().AddPage("networkpolicy", table, true, true)
+	t.SwitchPage(t.GetCurrentPage(), newpage)
+}
@@ -0,0 +1,220 @@
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell"
+	"github.com/rancher/axe/throwing"
+	"github.com/rivo/tview"
+	"k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+// editTaints shows the node taints as key=value:Effect fields, clearing a field removes the taint
+func editTaints(t *throwing.TableView) {
+	_, name := getNamespaceAndName(t)
+	if !canI(t, "patch", "nodes", "", "") {
+		return
+	}
+	node, err := t.GetClientSet().CoreV1().Nodes().Get(name, metav1.GetOptions{})
+	if err != nil {
+		t.Notify(err.Error(), throwing.SeverityError)
+		return
+	}
+
+	form := tview.NewForm()
+	form.SetBorder(true)
+	form.SetTitle(fmt.Sprintf("Taints - (%s)", name))
+	form.SetBackgroundColor(tcell.ColorBlack)
+	form.SetFieldBackgroundColor(tcell.ColorGray)
+	var fields []*tview.InputField
+	addField := func(label, value string) {
+		field := tview.NewInputField().SetLabel(label).SetText(value).SetFieldWidth(40)
+		fields = append(fields, field)
+		form.AddFormItem(field)
+	}
+	for i, taint := range node.Spec.Taints {
+		addField(fmt.Sprintf("taint %d", i+1), formatTaint(taint))
+	}
+	addField("new taint", "")
+
+	form.AddButton("Add", func() {
+		addField("new taint", "")
+	})
+	form.AddButton("Save", func() {
+		taints := []v1.Taint{}
+		for _, field := range fields {
+			text := strings.TrimSpace(field.GetText())
+			if text == "" {
+				continue
+			}
+			taint, err := parseTaint(text)
+			if err != nil {
+				t.Notify(err.Error(), throwing.SeverityError)
+				return
+			}
+			taints = append(taints, taint)
+		}
+		if err := patchTaints(t, name, taints); err != nil {
+			t.Notify(err.Error(), throwing.SeverityError)
+			return
+		}
+		t.Notify(fmt.Sprintf("taints of node %s updated", name), throwing.SeverityInfo)
+		t.Refresh()
+		t.SwitchToRootPage()
+	})
+	form.AddButton("Cancel", func() {
+		t.BackPage()
+	})
+	form.SetCancelFunc(func() {
+		t.BackPage()
+	})
+
+	t.InsertDialog("taints", t.GetCurrentPrimitive(), form)
+}
+
+func patchTaints(t *throwing.TableView, name string, taints []v1.Taint) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"taints": taints,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = t.GetClientSet().CoreV1().Nodes().Patch(name, k8stypes.MergePatchType, patch)
+	return err
+}
+
+func formatTaint(taint v1.Taint) string {
+	if taint.Value == "" {
+		return fmt.Sprintf("%s:%s", taint.Key, taint.Effect)
+	}
+	return fmt.Sprintf("%s=%s:%s", taint.Key, taint.Value, taint.Effect)
+}
+
+// parseTaint accepts the same key=value:Effect format as kubectl taint
+func parseTaint(text string) (v1.Taint, error) {
+	var taint v1.Taint
+	i := strings.LastIndex(text, ":")
+	if i < 0 {
+		return taint, fmt.Errorf("invalid taint %q, expected key=value:Effect", text)
+	}
+	keyValue, effect := text[:i], v1.TaintEffect(text[i+1:])
+	switch effect {
+	case v1.TaintEffectNoSchedule, v1.TaintEffectPreferNoSchedule, v1.TaintEffectNoExecute:
+	default:
+		return taint, fmt.Errorf("invalid taint effect %q, expected one of NoSchedule, PreferNoSchedule, NoExecute", effect)
+	}
+	parts := strings.SplitN(keyValue, "=", 2)
+	if parts[0] == "" {
+		return taint, fmt.Errorf("invalid taint %q, key is empty", text)
+	}
+	taint.Key = parts[0]
+	if len(parts) == 2 {
+		taint.Value = parts[1]
+	}
+	taint.Effect = effect
+	return taint, nil
+}
+
+// drainNode cordons the node and evicts its pods, the way kubectl drain does
+func drainNode(t *throwing.TableView) {
+	_, name := getNamespaceAndName(t)
+	if !canI(t, "patch", "nodes", "", "") || !canI(t, "create", "pods", "eviction", "") {
+		return
+	}
+	steps := []throwing.WizardStep{
+		{
+			Title: "Options",
+			Fields: []throwing.FormField{
+				{Name: "grace period", Label: "grace period (s, -1 for the pod's own)", Default: "-1", Width: 10, Numeric: true, Validate: throwing.IntBetween(-1, 3600)},
+				{Name: "local data", Label: "evict pods with emptyDir data", Default: "no", Options: []string{"no", "yes"}},
+			},
+		},
+	}
+	t.ShowWizard(fmt.Sprintf("Drain %s", name), steps, func(values throwing.FormValues) error {
+		grace := int64(values.Int("grace period"))
+		deleteLocalData := values["local data"] == "yes"
+		t.RunTask(fmt.Sprintf("drain %s", name), func(ctx context.Context, progress throwing.Progress) error {
+			return drain(ctx, t.GetClientSet(), name, grace, deleteLocalData, progress)
+		})
+		return nil
+	})
+}
+
+func drain(ctx context.Context, clientset *kubernetes.Clientset, name string, grace int64, deleteLocalData bool, progress throwing.Progress) error {
+	progress(0, 0, "cordoning")
+	patch := []byte(`{"spec":{"unschedulable":true}}`)
+	if _, err := clientset.CoreV1().Nodes().Patch(name, k8stypes.MergePatchType, patch); err != nil {
+		return err
+	}
+
+	pods, err := clientset.CoreV1().Pods(v1.NamespaceAll).List(metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("spec.nodeName", name).String(),
+	})
+	if err != nil {
+		return err
+	}
+	var evict []v1.Pod
+	for _, pod := range pods.Items {
+		if skipDrain(pod) {
+			continue
+		}
+		if !deleteLocalData && hasLocalData(pod) {
+			return fmt.Errorf("pod %s/%s has emptyDir data, drain again with local data evicted", pod.Namespace, pod.Name)
+		}
+		evict = append(evict, pod)
+	}
+
+	for i, pod := range evict {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		progress(i, len(evict), fmt.Sprintf("evicting %s/%s", pod.Namespace, pod.Name))
+		eviction := &policyv1beta1.Eviction{
+			ObjectMeta: metav1.ObjectMeta{Namespace: pod.Namespace, Name: pod.Name},
+		}
+		if grace >= 0 {
+			eviction.DeleteOptions = &metav1.DeleteOptions{GracePeriodSeconds: &grace}
+		}
+		if err := clientset.CoreV1().Pods(pod.Namespace).Evict(eviction); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("evicting %s/%s: %v", pod.Namespace, pod.Name, err)
+		}
+	}
+	progress(len(evict), len(evict), "drained")
+	return nil
+}
+
+// skipDrain leaves out what kubectl drain leaves out: mirror pods, daemonset pods and finished pods
+func skipDrain(pod v1.Pod) bool {
+	if _, ok := pod.Annotations[v1.MirrorPodAnnotationKey]; ok {
+		return true
+	}
+	if pod.Status.Phase == v1.PodSucceeded || pod.Status.Phase == v1.PodFailed {
+		return true
+	}
+	for _, owner := range pod.OwnerReferences {
+		if owner.Controller != nil && *owner.Controller && owner.Kind == "DaemonSet" {
+			return true
+		}
+	}
+	return false
+}
+
+func hasLocalData(pod v1.Pod) bool {
+	for _, volume := range pod.Spec.Volumes {
+		if volume.EmptyDir != nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,251 @@
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rancher/axe/throwing"
+	"github.com/rivo/tview"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	// nodeLogPoll is how often a node log is asked for what it got since, the kubelet can't follow
+	nodeLogPoll = 2 * time.Second
+	// nodeLogTail is how much of the end of a log file is shown first
+	nodeLogTail = 64 << 10
+	// nodeJournalTail is how many lines of the end of a journal are shown first
+	nodeJournalTail = 500
+	// journalSuffix marks the journal units in the list of logs of a node
+	journalSuffix = " (journal)"
+)
+
+/*
+nodeJournalUnits are offered before the files of /var/log, they're read with the node log query
+of kubelets from 1.27 on with the NodeLogQuery feature gate. Older kubelets ignore the query.
+*/
+var nodeJournalUnits = []string{"kubelet", "containerd", "crio", "docker"}
+
+// logLink picks the entries out of the directory listing the kubelet serves for /var/log
+var logLink = regexp.MustCompile(`<a href="([^"?/]+)">`)
+
+// contentRange reads the Content-Range of a 206 or 416, "bytes 100-199/1000" or "bytes */1000"
+var contentRange = regexp.MustCompile(`^bytes (?:(\d+)-(\d+)|\*)/(\d+)$`)
+
+// nodeLogs reads the logs of a node through the API server, with get on nodes/proxy and no ssh
+type nodeLogs struct {
+	clientset *kubernetes.Clientset
+	client    *http.Client
+	node      string
+}
+
+func newNodeLogs(node string) (*nodeLogs, error) {
+	config, err := restConfig()
+	if err != nil {
+		return nil, err
+	}
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	transport, err := rest.TransportFor(config)
+	if err != nil {
+		return nil, err
+	}
+	return &nodeLogs{clientset: clientset, client: &http.Client{Transport: transport}, node: node}, nil
+}
+
+// url is the proxied /logs/ of the kubelet, file empty is the listing
+func (n *nodeLogs) url(file string, params url.Values) string {
+	req := n.clientset.CoreV1().RESTClient().Get().Resource("nodes").Name(n.node).SubResource("proxy").Suffix("logs", file)
+	u := req.URL()
+	if file == "" {
+		u.Path += "/"
+	}
+	u.RawQuery = params.Encode()
+	return u.String()
+}
+
+func (n *nodeLogs) get(ctx context.Context, u string, header http.Header) (*http.Response, []byte, error) {
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	resp, err := n.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err == nil && resp.StatusCode >= 300 && resp.StatusCode != http.StatusRequestedRangeNotSatisfiable {
+		err = fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return resp, body, err
+}
+
+// list offers the journal units and then the files the kubelet serves from /var/log
+func (n *nodeLogs) list(ctx context.Context) ([]string, error) {
+	_, body, err := n.get(ctx, n.url("", nil), nil)
+	if err != nil {
+		return nil, err
+	}
+	var logs []string
+	for _, unit := range nodeJournalUnits {
+		logs = append(logs, unit+journalSuffix)
+	}
+	for _, m := range logLink.FindAllStringSubmatch(string(body), -1) {
+		if name, err := url.PathUnescape(m[1]); err == nil {
+			logs = append(logs, name)
+		}
+	}
+	return logs, nil
+}
+
+// writeLines writes text to out a line at a time, escaped like the logs of pods
+func writeLines(out io.Writer, text string) {
+	for _, line := range strings.Split(strings.TrimSuffix(text, "\n"), "\n") {
+		fmt.Fprintf(out, "%s\n", tview.TranslateANSI(tview.Escape(line)))
+	}
+}
+
+/*
+followFile tails a file of /var/log with range requests, the first shows the last nodeLogTail bytes,
+every later one asks for what's past the end. A file that got shorter was rotated and is read from the start.
+*/
+func (n *nodeLogs) followFile(ctx context.Context, file string, out io.Writer) error {
+	u := n.url(file, nil)
+	offset := int64(-1)
+	for {
+		rangeHeader := fmt.Sprintf("bytes=%d-", offset)
+		if offset < 0 {
+			rangeHeader = fmt.Sprintf("bytes=-%d", nodeLogTail)
+		}
+		resp, body, err := n.get(ctx, u, http.Header{"Range": {rangeHeader}})
+		if err != nil {
+			return err
+		}
+		var start, end, total int64
+		if m := contentRange.FindStringSubmatch(resp.Header.Get("Content-Range")); m != nil {
+			start, _ = strconv.ParseInt(m[1], 10, 64)
+			end, _ = strconv.ParseInt(m[2], 10, 64)
+			total, _ = strconv.ParseInt(m[3], 10, 64)
+		}
+		switch resp.StatusCode {
+		case http.StatusPartialContent:
+			if offset < 0 && start > 0 {
+				// the tail starts in the middle of a line
+				if i := bytes.IndexByte(body, '\n'); i >= 0 {
+					body = body[i+1:]
+				}
+			}
+			offset = end + 1
+		case http.StatusRequestedRangeNotSatisfiable:
+			if total < offset {
+				fmt.Fprintf(out, "[yellow]%s was rotated[-]\n", tview.Escape(file))
+				offset = 0
+			}
+			body = nil
+		default:
+			// no range support, the whole file came
+			offset = int64(len(body))
+		}
+		if len(body) > 0 {
+			writeLines(out, string(body))
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(nodeLogPoll):
+		}
+	}
+}
+
+/*
+followJournal polls the node log query for the lines of unit since the last poll.
+The query goes by the second, the lines up to the last one seen already are dropped.
+*/
+func (n *nodeLogs) followJournal(ctx context.Context, unit string, out io.Writer) error {
+	params := url.Values{"query": {unit}, "tailLines": {strconv.Itoa(nodeJournalTail)}}
+	last := ""
+	for {
+		polled := time.Now()
+		_, body, err := n.get(ctx, n.url("", params), nil)
+		if err != nil {
+			return err
+		}
+		if logLink.Match(body) {
+			// a kubelet without the query ignores it and lists /var/log
+			return fmt.Errorf("the kubelet of %s has no node log query, it needs 1.27 and the NodeLogQuery feature gate", n.node)
+		}
+		lines := strings.Split(strings.TrimSuffix(string(body), "\n"), "\n")
+		if last != "" {
+			for i := len(lines) - 1; i >= 0; i-- {
+				if lines[i] == last {
+					lines = lines[i+1:]
+					break
+				}
+			}
+		}
+		if len(lines) > 0 && lines[len(lines)-1] != "" {
+			last = lines[len(lines)-1]
+			writeLines(out, strings.Join(lines, "\n"))
+		}
+		params = url.Values{"query": {unit}, "sinceTime": {polled.Add(-time.Second).UTC().Format(time.RFC3339)}}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(nodeLogPoll):
+		}
+	}
+}
+
+// nodeLog asks which log of the selected node to follow, the journal of the kubelet comes first
+func nodeLog(t *throwing.TableView) {
+	_, node := getNamespaceAndName(t)
+	if node == "" {
+		return
+	}
+	logs, err := newNodeLogs(node)
+	if err != nil {
+		t.Notify(err.Error(), throwing.SeverityError)
+		return
+	}
+	names, err := logs.list(t.Context())
+	if err != nil {
+		t.Notify(fmt.Sprintf("logs of %s: %v", node, err), throwing.SeverityError)
+		return
+	}
+	t.Choose(fmt.Sprintf("logs of %s", node), names, func(_ int, name string) {
+		ctx, cancel := context.WithCancel(t.Context())
+		logbox := newLogView(t, fmt.Sprintf("logs - %s %s", node, name))
+		t.Go(func() {
+			var err error
+			if strings.HasSuffix(name, journalSuffix) {
+				err = logs.followJournal(ctx, strings.TrimSuffix(name, journalSuffix), logbox)
+			} else {
+				err = logs.followFile(ctx, name, logbox)
+			}
+			if err != nil && ctx.Err() == nil {
+				fmt.Fprintf(logbox, "[red]%s[-]\n", tview.Escape(err.Error()))
+			}
+		})
+		newpage := tview.NewPages().AddPage("logs", logbox, true, true)
+		t.SwitchPage(t.GetCurrentPage(), newpage)
+		t.OnLeave(newpage, cancel)
+	})
+}
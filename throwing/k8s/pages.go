@@ -0,0 +1,68 @@
+package k8s
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rancher/axe/throwing/datafeeder"
+	"github.com/rancher/axe/throwing/types"
+	"k8s.io/client-go/discovery"
+)
+
+// resourcePages are footer pages listing one resource kind, they get the actions of nested resource tables
+var resourcePages = map[string]bool{}
+
+// pageName finds the built in page named by a footer entry, by kind or by title
+func pageName(entry string) (string, bool) {
+	for _, f := range Footers {
+		if strings.EqualFold(f.Kind, entry) || strings.EqualFold(f.Title, entry) {
+			return f.Kind, true
+		}
+	}
+	return "", false
+}
+
+/*
+configurePages replaces the footer with the configured entries, in their order.
+An entry is a built in page like helm or events, or a resource kind like pods or deployments.apps which gets a page of its own.
+The first nine pages are bound to 1-9, the rest are reached with [ and ].
+*/
+func configurePages(d discovery.DiscoveryInterface, entries []string) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	builtin := map[string]types.ResourceView{}
+	for _, f := range Footers {
+		builtin[f.Kind] = f
+	}
+
+	var footers []types.ResourceView
+	nav := map[rune]string{}
+	for i, entry := range entries {
+		kind, ok := pageName(entry)
+		footer := builtin[kind]
+		if !ok {
+			gvr, err := resolveKind(d, entry)
+			if err != nil {
+				return fmt.Errorf("footer entry %s: %v", entry, err)
+			}
+			w := wrapper{group: gvr.Group, version: gvr.Version, name: gvr.Resource}
+			kind = kindOf(gvr)
+			footer = types.ResourceView{Title: gvr.Resource, Kind: kind}
+			ViewMap[kind] = types.View{
+				Actions: actionsForKind(kind),
+				Kind:    types.ResourceKind{Title: gvr.Resource, Kind: kind},
+				Feeder:  datafeeder.NewDataFeeder(refresherForKind(w)).SetRowColor(rowColorForKind(kind)).SetAge(ageForKind(kind)),
+			}
+			resourcePages[kind] = true
+		}
+		footer.Index = i + 1
+		footers = append(footers, footer)
+		if i < 9 {
+			nav[rune('1'+i)] = kind
+		}
+	}
+	Footers, PageNav = footers, nav
+	drawer.Footers, drawer.PageNav = footers, nav
+	return nil
+}
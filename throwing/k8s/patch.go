@@ -0,0 +1,118 @@
+package k8s
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell"
+	"github.com/ghodss/yaml"
+	"github.com/rancher/axe/throwing"
+	"github.com/rivo/tview"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+)
+
+var patchTypes = []k8stypes.PatchType{
+	k8stypes.MergePatchType,
+	k8stypes.JSONPatchType,
+	k8stypes.StrategicMergePatchType,
+}
+
+var patchTypeNames = []string{"merge", "json", "strategic"}
+
+// applyPatch sends patch to the selected object, with dryRun the server only returns the would-be result
+func applyPatch(t *throwing.TableView, pt k8stypes.PatchType, patch string, dryRun bool) (*unstructured.Unstructured, error) {
+	namespace, name := getNamespaceAndName(t)
+	gvr, err := groupVersionResource(t.GetClientSet(), t.GetResourceKind())
+	if err != nil {
+		return nil, err
+	}
+	client, err := newDynamicClient()
+	if err != nil {
+		return nil, err
+	}
+	options := metav1.UpdateOptions{}
+	if dryRun {
+		options.DryRun = []string{metav1.DryRunAll}
+	}
+	return client.Resource(gvr).Namespace(namespace).Patch(name, pt, []byte(patch), options)
+}
+
+/*
+patchConsole applies a JSON patch, JSON merge patch or strategic merge patch to the selected resource.
+
+Preview does a server side dry run and shows the resulting object, Apply sends the patch for real.
+*/
+func patchConsole(t *throwing.TableView) {
+	namespace, name := getNamespaceAndName(t)
+	if !canI(t, "patch", t.GetResourceKind(), "", namespace) {
+		return
+	}
+
+	preview := tview.NewTextView()
+	preview.SetBorder(true)
+	preview.SetTitle("dry run")
+	preview.SetTitleColor(tcell.ColorPurple)
+	preview.SetDynamicColors(true).SetBackgroundColor(tcell.ColorBlack)
+
+	typeIndex := 0
+	patchField := tview.NewInputField().SetLabel("patch").SetFieldWidth(0)
+
+	form := tview.NewForm()
+	form.SetBorder(true)
+	form.SetTitle(fmt.Sprintf("Patch - (%s %s)", t.GetResourceKind(), name))
+	form.SetBackgroundColor(tcell.ColorBlack)
+	form.SetFieldBackgroundColor(tcell.ColorGray)
+	form.AddDropDown("type", patchTypeNames, typeIndex, func(option string, index int) {
+		typeIndex = index
+	})
+	form.AddFormItem(patchField)
+
+	patch := func(dryRun bool) (*unstructured.Unstructured, bool) {
+		text := strings.TrimSpace(patchField.GetText())
+		if text == "" {
+			t.Notify("patch is empty", throwing.SeverityWarning)
+			return nil, false
+		}
+		obj, err := applyPatch(t, patchTypes[typeIndex], text, dryRun)
+		if err != nil {
+			t.Notify(err.Error(), throwing.SeverityError)
+			return nil, false
+		}
+		return obj, true
+	}
+	form.AddButton("Preview", func() {
+		obj, ok := patch(true)
+		if !ok {
+			return
+		}
+		out, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			t.Notify(err.Error(), throwing.SeverityError)
+			return
+		}
+		preview.SetText(highlight(string(out), languageYAML)).ScrollToBeginning()
+	})
+	form.AddButton("Apply", func() {
+		if _, ok := patch(false); !ok {
+			return
+		}
+		t.Notify(fmt.Sprintf("%s %s patched", t.GetResourceKind(), name), throwing.SeverityInfo)
+		publishChange(t, throwing.EventChanged, t.GetResourceKind(), namespace, name)
+		t.SwitchToRootPage()
+	})
+	form.AddButton("Cancel", func() {
+		t.BackPage()
+	})
+	form.SetCancelFunc(func() {
+		t.BackPage()
+	})
+
+	console := tview.NewFlex().SetDirection(tview.FlexRow)
+	console.AddItem(form, 9, 1, true)
+	console.AddItem(preview, 0, 1, false)
+
+	newpage := tview.NewPages().AddPage("patch", console, true, true)
+	t.SwitchPage(t.GetCurrentPage(), newpage)
+}
@@ -0,0 +1,152 @@
+package k8s
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/rancher/axe/throwing"
+	"github.com/rivo/tview"
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// podSecurity is 1 while pod tables have the SECURITY column, set with podSecurity in the config and toggled with P
+var podSecurity int32
+
+func showPodSecurity() bool {
+	return atomic.LoadInt32(&podSecurity) == 1
+}
+
+/*
+podFindings looks at the pod spec the way a reviewer would. risky are settings that open up the node:
+privileged containers, host namespaces, hostPath volumes and added capabilities.
+unset are the hardening settings left out, per container.
+*/
+func podFindings(pod *v1.Pod) (risky, unset []string) {
+	spec := pod.Spec
+	if spec.HostNetwork {
+		risky = append(risky, "hostNetwork")
+	}
+	if spec.HostPID {
+		risky = append(risky, "hostPID")
+	}
+	if spec.HostIPC {
+		risky = append(risky, "hostIPC")
+	}
+	for _, volume := range spec.Volumes {
+		if volume.HostPath != nil {
+			risky = append(risky, "hostPath "+volume.HostPath.Path)
+		}
+	}
+
+	podContext := spec.SecurityContext
+	if podContext == nil {
+		podContext = &v1.PodSecurityContext{}
+	}
+	for _, c := range append(spec.InitContainers, spec.Containers...) {
+		sc := c.SecurityContext
+		if sc == nil {
+			sc = &v1.SecurityContext{}
+		}
+		if sc.Privileged != nil && *sc.Privileged {
+			risky = append(risky, c.Name+" privileged")
+		}
+		if sc.Capabilities != nil && len(sc.Capabilities.Add) > 0 {
+			var added []string
+			for _, capability := range sc.Capabilities.Add {
+				added = append(added, string(capability))
+			}
+			risky = append(risky, fmt.Sprintf("%s adds %s", c.Name, strings.Join(added, ",")))
+		}
+
+		runAsNonRoot := (sc.RunAsNonRoot != nil && *sc.RunAsNonRoot) ||
+			(sc.RunAsNonRoot == nil && podContext.RunAsNonRoot != nil && *podContext.RunAsNonRoot)
+		runAsUser := sc.RunAsUser
+		if runAsUser == nil {
+			runAsUser = podContext.RunAsUser
+		}
+		if !runAsNonRoot && (runAsUser == nil || *runAsUser == 0) {
+			unset = append(unset, c.Name+" runAsNonRoot")
+		}
+		if sc.AllowPrivilegeEscalation == nil || *sc.AllowPrivilegeEscalation {
+			unset = append(unset, c.Name+" allowPrivilegeEscalation: false")
+		}
+		if sc.ReadOnlyRootFilesystem == nil || !*sc.ReadOnlyRootFilesystem {
+			unset = append(unset, c.Name+" readOnlyRootFilesystem")
+		}
+		if sc.Capabilities == nil || !dropsAll(sc.Capabilities.Drop) {
+			unset = append(unset, c.Name+" drop ALL capabilities")
+		}
+	}
+	return risky, unset
+}
+
+func dropsAll(capabilities []v1.Capability) bool {
+	for _, c := range capabilities {
+		if strings.EqualFold(string(c), "ALL") {
+			return true
+		}
+	}
+	return false
+}
+
+// unstructuredPod reads a pod listed by the generic refresher
+func unstructuredPod(obj *unstructured.Unstructured) (*v1.Pod, error) {
+	pod := &v1.Pod{}
+	return pod, runtime.DefaultUnstructuredConverter.FromUnstructured(obj.Object, pod)
+}
+
+// securityColumn names the risky settings, a pod without any gets the count of hardening settings it leaves out
+func securityColumn(obj *unstructured.Unstructured) string {
+	pod, err := unstructuredPod(obj)
+	if err != nil {
+		return "?"
+	}
+	risky, unset := podFindings(pod)
+	switch {
+	case len(risky) > 0:
+		return strings.Join(risky, ", ")
+	case len(unset) > 0:
+		return fmt.Sprintf("%d unset", len(unset))
+	}
+	return "ok"
+}
+
+// riskySecurity tells a SECURITY value naming risky settings from the quiet ones
+func riskySecurity(value string) bool {
+	return value != "" && value != "ok" && value != "?" && !strings.HasSuffix(value, " unset")
+}
+
+// securityDetail is the section of the detail pane listing every finding of a pod
+func securityDetail(obj *unstructured.Unstructured) string {
+	pod, err := unstructuredPod(obj)
+	if err != nil {
+		return ""
+	}
+	risky, unset := podFindings(pod)
+	if len(risky) == 0 && len(unset) == 0 {
+		return "[green]nothing to point out[white]\n"
+	}
+	b := &strings.Builder{}
+	for _, finding := range risky {
+		fmt.Fprintf(b, "[red]%s[white]\n", tview.Escape(finding))
+	}
+	for _, finding := range unset {
+		fmt.Fprintf(b, "[yellow]not set: %s[white]\n", tview.Escape(finding))
+	}
+	return b.String()
+}
+
+// togglePodSecurity shows or hides the SECURITY column of pod tables
+func togglePodSecurity(t *throwing.TableView) {
+	if showPodSecurity() {
+		atomic.StoreInt32(&podSecurity, 0)
+		t.Notify("SECURITY column hidden", throwing.SeverityInfo)
+	} else {
+		atomic.StoreInt32(&podSecurity, 1)
+		t.Notify("pods show the SECURITY column, the detail pane lists the findings", throwing.SeverityInfo)
+	}
+	t.Refresh()
+}
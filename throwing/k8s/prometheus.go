@@ -0,0 +1,334 @@
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rancher/axe/throwing"
+	"github.com/rivo/tview"
+	"k8s.io/api/core/v1"
+)
+
+const (
+	prometheusKind    = "prometheus"
+	prometheusTimeout = 10 * time.Second
+	// graphRange is how far back the graphs go
+	graphRange = time.Hour
+	// maxGraphSeries keeps a query returning many series from drawing a page of charts
+	maxGraphSeries = 8
+)
+
+// prometheusURL is prometheus from the config, e.g. http://prometheus.monitoring:9090, empty turns the integration off
+var prometheusURL string
+
+var prometheusClient = &http.Client{Timeout: prometheusTimeout}
+
+// promResponse is the envelope of every answer of the HTTP API
+type promResponse struct {
+	Status    string `json:"status"`
+	ErrorType string `json:"errorType"`
+	Error     string `json:"error"`
+	Data      struct {
+		ResultType string          `json:"resultType"`
+		Result     json.RawMessage `json:"result"`
+	} `json:"data"`
+}
+
+// promSeries is a series of a result, values has one sample for an instant query and one per step for a range
+type promSeries struct {
+	Metric map[string]string `json:"metric"`
+	Value  []interface{}     `json:"value"`
+	Values [][]interface{}   `json:"values"`
+}
+
+// name prints the labels of s the way Prometheus does, {job="x"} or just the metric name when that's all there is
+func (s promSeries) name() string {
+	var labels []string
+	for k, v := range s.Metric {
+		if k != "__name__" {
+			labels = append(labels, fmt.Sprintf("%s=%q", k, v))
+		}
+	}
+	sort.Strings(labels)
+	if len(labels) == 0 {
+		return s.Metric["__name__"]
+	}
+	return s.Metric["__name__"] + "{" + strings.Join(labels, ", ") + "}"
+}
+
+// promSample reads a [timestamp, "value"] pair
+func promSample(pair []interface{}) (time.Time, float64, error) {
+	if len(pair) != 2 {
+		return time.Time{}, 0, fmt.Errorf("sample %v isn't a timestamp and a value", pair)
+	}
+	ts, ok := pair[0].(float64)
+	value, ok2 := pair[1].(string)
+	if !ok || !ok2 {
+		return time.Time{}, 0, fmt.Errorf("sample %v isn't a timestamp and a value", pair)
+	}
+	v, err := strconv.ParseFloat(value, 64)
+	sec, frac := math.Modf(ts)
+	return time.Unix(int64(sec), int64(frac*1e9)), v, err
+}
+
+// promGet calls an endpoint of the HTTP API and decodes the series of its result, a scalar comes back as one series
+func promGet(ctx context.Context, path string, params url.Values) ([]promSeries, error) {
+	if prometheusURL == "" {
+		return nil, fmt.Errorf("no Prometheus, set prometheus in the config")
+	}
+	req, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(prometheusURL, "/")+path+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := prometheusClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	var answer promResponse
+	if err := json.NewDecoder(resp.Body).Decode(&answer); err != nil {
+		return nil, fmt.Errorf("prometheus answered %s: %v", resp.Status, err)
+	}
+	if answer.Status != "success" {
+		return nil, fmt.Errorf("prometheus: %s: %s", answer.ErrorType, answer.Error)
+	}
+
+	var series []promSeries
+	switch answer.Data.ResultType {
+	case "vector", "matrix":
+		err = json.Unmarshal(answer.Data.Result, &series)
+	case "scalar", "string":
+		var value []interface{}
+		err = json.Unmarshal(answer.Data.Result, &value)
+		series = []promSeries{{Value: value}}
+	default:
+		err = fmt.Errorf("prometheus answered with a %s", answer.Data.ResultType)
+	}
+	return series, err
+}
+
+func promQuery(ctx context.Context, query string) ([]promSeries, error) {
+	return promGet(ctx, "/api/v1/query", url.Values{"query": {query}})
+}
+
+/*
+promQueryRange evaluates query every step from start to end and lines up the samples of each series,
+a step without a sample is NaN so charts show the gap.
+*/
+func promQueryRange(ctx context.Context, query string, start, end time.Time, step time.Duration) ([]promSeries, [][]float64, error) {
+	series, err := promGet(ctx, "/api/v1/query_range", url.Values{
+		"query": {query},
+		"start": {strconv.FormatInt(start.Unix(), 10)},
+		"end":   {strconv.FormatInt(end.Unix(), 10)},
+		"step":  {strconv.FormatFloat(step.Seconds(), 'f', -1, 64)},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	steps := int(end.Sub(start)/step) + 1
+	var values [][]float64
+	for _, s := range series {
+		line := make([]float64, steps)
+		for i := range line {
+			line[i] = math.NaN()
+		}
+		for _, pair := range s.Values {
+			at, v, err := promSample(pair)
+			if err != nil {
+				continue
+			}
+			if i := int(at.Sub(start) / step); i >= 0 && i < steps {
+				line[i] = v
+			}
+		}
+		values = append(values, line)
+	}
+	return series, values, nil
+}
+
+// promQueries are the queries of the prometheus page, in the order they were added
+var promQueries = struct {
+	sync.Mutex
+	list []string
+}{}
+
+// RefreshPrometheus evaluates every query of the page, a row per series of the result
+func RefreshPrometheus(b *bytes.Buffer) error {
+	if prometheusURL == "" {
+		return fmt.Errorf("no Prometheus, set prometheus in the config")
+	}
+	promQueries.Lock()
+	queries := append([]string(nil), promQueries.list...)
+	promQueries.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), prometheusTimeout)
+	defer cancel()
+	var rows [][]string
+	for _, query := range queries {
+		series, err := promQuery(ctx, query)
+		if err != nil {
+			rows = append(rows, []string{query, "", "error: " + err.Error()})
+			continue
+		}
+		if len(series) == 0 {
+			rows = append(rows, []string{query, "", "no data"})
+		}
+		for _, s := range series {
+			value := ""
+			if _, v, err := promSample(s.Value); err == nil {
+				value = formatPlain(v)
+			}
+			rows = append(rows, []string{query, s.name(), value})
+		}
+	}
+	writeTable(b, []string{"QUERY", "SERIES", "VALUE"}, rows)
+	return nil
+}
+
+// askPromQuery adds a query to the prometheus page, starting from the selected one
+func askPromQuery(t *throwing.TableView) {
+	fields := []throwing.FormField{
+		{Name: "query", Label: "PromQL", Default: selectedColumn(t, "QUERY"), Validate: throwing.Required},
+	}
+	t.ShowForm("prometheus", "Add", fields, func(values throwing.FormValues) error {
+		query := strings.TrimSpace(values["query"])
+		promQueries.Lock()
+		defer promQueries.Unlock()
+		for _, q := range promQueries.list {
+			if q == query {
+				t.Refresh()
+				return nil
+			}
+		}
+		promQueries.list = append(promQueries.list, query)
+		t.Refresh()
+		return nil
+	})
+}
+
+// removePromQuery drops the query of the selected row from the page
+func removePromQuery(t *throwing.TableView) {
+	query := selectedColumn(t, "QUERY")
+	promQueries.Lock()
+	for i, q := range promQueries.list {
+		if q == query {
+			promQueries.list = append(promQueries.list[:i], promQueries.list[i+1:]...)
+			break
+		}
+	}
+	promQueries.Unlock()
+	t.Refresh()
+}
+
+// promGraph is a query to chart, format prints its values with their unit
+type promGraph struct {
+	title, query string
+	format       func(float64) string
+	// series only charts the series of that name, empty charts them all
+	series string
+}
+
+/*
+showGraphs charts every graph over the last graphRange in the background, a chart per series,
+and opens them on a page once they're all fetched. The step makes a sample per point of the chart.
+*/
+func showGraphs(t *throwing.TableView, title string, graphs []promGraph) {
+	width := chartWidth(t)
+	end := time.Now()
+	start := end.Add(-graphRange)
+	step := graphRange / time.Duration(width*2)
+	ctx := t.Context()
+	t.Notify(fmt.Sprintf("querying %s", prometheusURL), throwing.SeverityProgress)
+	t.Go(func() {
+		b := &strings.Builder{}
+		for _, g := range graphs {
+			series, values, err := promQueryRange(ctx, g.query, start, end, step)
+			if err != nil {
+				t.Notify(err.Error(), throwing.SeverityError)
+				return
+			}
+			fmt.Fprintf(b, "[purple]%s[white]  [gray]%s[white]\n", tview.Escape(g.title), tview.Escape(g.query))
+			drawn := 0
+			for i, s := range series {
+				if g.series != "" && s.name() != g.series {
+					continue
+				}
+				if drawn == maxGraphSeries {
+					fmt.Fprintf(b, "[gray]%d more series left out[white]\n", len(series)-i)
+					break
+				}
+				if name := s.name(); name != "" {
+					fmt.Fprintf(b, "%s\n", tview.Escape(name))
+				}
+				b.WriteString(brailleChart(values[i], width, g.format))
+				b.WriteString(chartAxis(start, end, width))
+				drawn++
+			}
+			if drawn == 0 {
+				b.WriteString("[gray]no data[white]\n")
+			}
+			b.WriteString("\n")
+		}
+		t.GetApplication().QueueUpdateDraw(func() {
+			showChart(t, title, b.String())
+		})
+	})
+}
+
+// graphPromQuery charts the query of the selected row, only its series when the row names one
+func graphPromQuery(t *throwing.TableView) {
+	query := selectedColumn(t, "QUERY")
+	if query == "" {
+		return
+	}
+	showGraphs(t, fmt.Sprintf("graph - %s", query), []promGraph{{
+		title:  "last hour",
+		query:  query,
+		format: formatPlain,
+		series: selectedColumn(t, "SERIES"),
+	}})
+}
+
+/*
+podQuery sums expr over the containers of a pod, expr has a %s where the label matchers go.
+Older kubelets label the cAdvisor series pod_name and container_name, the query falls back to those.
+*/
+func podQuery(expr, namespace, name string) string {
+	current := fmt.Sprintf(`namespace=%q, pod=%q, container!="", container!="POD"`, namespace, name)
+	legacy := fmt.Sprintf(`namespace=%q, pod_name=%q, container_name!="", container_name!="POD"`, namespace, name)
+	return fmt.Sprintf("sum(%s) or sum(%s)", fmt.Sprintf(expr, current), fmt.Sprintf(expr, legacy))
+}
+
+// podUsageGraphs charts the CPU and memory use of the selected pod
+func podUsageGraphs(t *throwing.TableView) {
+	namespace, name := getNamespaceAndName(t)
+	if name == "" {
+		return
+	}
+	showGraphs(t, fmt.Sprintf("usage - %s/%s", namespace, name), []promGraph{
+		{
+			title: "CPU (cores)",
+			query: podQuery("rate(container_cpu_usage_seconds_total{%s}[5m])", namespace, name),
+			format: func(v float64) string {
+				return formatMilli(v1.ResourceCPU, int64(v*1000))
+			},
+		},
+		{
+			title: "memory (working set)",
+			query: podQuery("container_memory_working_set_bytes{%s}", namespace, name),
+			format: func(v float64) string {
+				return formatMilli(v1.ResourceMemory, int64(v*1000))
+			},
+		},
+	})
+}
@@ -0,0 +1,103 @@
+package k8s
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/gdamore/tcell"
+	"github.com/ghodss/yaml"
+	"github.com/rancher/axe/throwing"
+	"github.com/rivo/tview"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// jsonPathQuery evaluates a kubectl style JSONPath expression, the braces are optional
+func jsonPathQuery(obj interface{}, expr string) (string, error) {
+	expr = strings.TrimSpace(expr)
+	if !strings.HasPrefix(expr, "{") {
+		expr = "{" + expr + "}"
+	}
+	j := jsonpath.New("query").AllowMissingKeys(true)
+	if err := j.Parse(expr); err != nil {
+		return "", err
+	}
+	results, err := j.FindResults(obj)
+	if err != nil {
+		return "", err
+	}
+
+	b := &strings.Builder{}
+	for _, set := range results {
+		for _, v := range set {
+			switch v.Kind() {
+			case reflect.Map, reflect.Slice:
+				out, err := yaml.Marshal(v.Interface())
+				if err != nil {
+					return "", err
+				}
+				b.WriteString(highlight(string(out), languageYAML))
+			default:
+				fmt.Fprintf(b, "%s\n", tview.Escape(fmt.Sprint(v.Interface())))
+			}
+		}
+	}
+	if b.Len() == 0 {
+		return "[gray]no match", nil
+	}
+	return b.String(), nil
+}
+
+/*
+showObject shows the YAML of an object with a JSONPath query box under it.
+
+Tab moves between the object and the query, Enter runs the query, e.g. .spec.containers[*].image
+*/
+func showObject(t *throwing.TableView, title, content string) {
+	var obj interface{}
+	if err := yaml.Unmarshal([]byte(content), &obj); err != nil {
+		t.Notify(err.Error(), throwing.SeverityError)
+		return
+	}
+
+	box := textBox(t, title, content, languageYAML)
+
+	result := tview.NewTextView()
+	result.SetBorder(true)
+	result.SetTitle("result")
+	result.SetTitleColor(tcell.ColorPurple)
+	result.SetDynamicColors(true).SetBackgroundColor(tcell.ColorBlack)
+
+	query := tview.NewInputField()
+	query.SetLabel("jsonpath ")
+	query.SetFieldBackgroundColor(tcell.ColorBlack)
+	query.SetFieldTextColor(tcell.ColorBlue)
+	query.SetDoneFunc(func(key tcell.Key) {
+		switch key {
+		case tcell.KeyEnter:
+			out, err := jsonPathQuery(obj, query.GetText())
+			if err != nil {
+				result.SetText("[red]" + tview.Escape(err.Error()))
+				return
+			}
+			result.SetText(out).ScrollToBeginning()
+		case tcell.KeyTab:
+			t.GetApplication().SetFocus(box)
+		}
+	})
+	box.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyTab {
+			t.GetApplication().SetFocus(query)
+			return nil
+		}
+		return event
+	})
+
+	page := tview.NewFlex().SetDirection(tview.FlexRow)
+	page.AddItem(box, 0, 3, true)
+	page.AddItem(query, 1, 1, false)
+	page.AddItem(result, 0, 1, false)
+
+	newpage := tview.NewPages().AddPage("get", page, true, true)
+	t.SwitchPage(t.GetCurrentPage(), newpage)
+}
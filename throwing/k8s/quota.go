@@ -0,0 +1,138 @@
+package k8s
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gdamore/tcell"
+	"github.com/rancher/axe/throwing/datafeeder"
+	"k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	quotaWarnPercent  = 75
+	quotaAlertPercent = 90
+)
+
+func sortedResourceNames(list v1.ResourceList) []string {
+	var names []string
+	for name := range list {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// quantityFloat reads q through its decimal form, in milli units a hundred times a quota of 100Ti is past int64
+func quantityFloat(q resource.Quantity) float64 {
+	f, _ := strconv.ParseFloat(q.AsDec().String(), 64)
+	return f
+}
+
+// usagePercent is used/hard in percent, -1 when hard is zero
+func usagePercent(used, hard resource.Quantity) int64 {
+	if hard.IsZero() {
+		return -1
+	}
+	return int64(quantityFloat(used) * 100 / quantityFloat(hard))
+}
+
+// refreshQuotas lists one row per quota and resource with the usage in percent
+func (w wrapper) refreshQuotas(b *bytes.Buffer) error {
+	clientset, err := newClientset()
+	if err != nil {
+		return err
+	}
+	namespace, scoped := w.listScope()
+	quotas, err := clientset.CoreV1().ResourceQuotas(namespace).List(metav1.ListOptions{LabelSelector: w.labelSelector})
+	if err != nil {
+		return err
+	}
+
+	var rows [][]string
+	for _, q := range quotas.Items {
+		for _, name := range sortedResourceNames(q.Status.Hard) {
+			hard := q.Status.Hard[v1.ResourceName(name)]
+			used := q.Status.Used[v1.ResourceName(name)]
+			percent := "-"
+			if p := usagePercent(used, hard); p >= 0 {
+				percent = fmt.Sprintf("%d%%", p)
+			}
+			rows = append(rows, []string{q.Namespace, q.Name, name, used.String(), hard.String(), percent})
+		}
+	}
+	writeScopedTable(b, scoped, []string{"NAMESPACE", "NAME", "RESOURCE", "USED", "HARD", "PERCENT"}, rows)
+	return nil
+}
+
+func quantityOrDash(list v1.ResourceList, name v1.ResourceName) string {
+	if q, ok := list[name]; ok {
+		return q.String()
+	}
+	return "-"
+}
+
+// refreshLimitRanges lists one row per limit range item and resource
+func (w wrapper) refreshLimitRanges(b *bytes.Buffer) error {
+	clientset, err := newClientset()
+	if err != nil {
+		return err
+	}
+	namespace, scoped := w.listScope()
+	limitRanges, err := clientset.CoreV1().LimitRanges(namespace).List(metav1.ListOptions{LabelSelector: w.labelSelector})
+	if err != nil {
+		return err
+	}
+
+	var rows [][]string
+	for _, lr := range limitRanges.Items {
+		for _, item := range lr.Spec.Limits {
+			names := map[string]bool{}
+			for _, list := range []v1.ResourceList{item.Min, item.Max, item.Default, item.DefaultRequest, item.MaxLimitRequestRatio} {
+				for _, name := range sortedResourceNames(list) {
+					names[name] = true
+				}
+			}
+			var sorted []string
+			for name := range names {
+				sorted = append(sorted, name)
+			}
+			sort.Strings(sorted)
+			for _, name := range sorted {
+				n := v1.ResourceName(name)
+				rows = append(rows, []string{
+					lr.Namespace,
+					lr.Name,
+					string(item.Type),
+					name,
+					quantityOrDash(item.Min, n),
+					quantityOrDash(item.Max, n),
+					quantityOrDash(item.DefaultRequest, n),
+					quantityOrDash(item.Default, n),
+					quantityOrDash(item.MaxLimitRequestRatio, n),
+				})
+			}
+		}
+	}
+	writeScopedTable(b, scoped, []string{"NAMESPACE", "NAME", "TYPE", "RESOURCE", "MIN", "MAX", "DEFAULT REQUEST", "DEFAULT LIMIT", "MAX RATIO"}, rows)
+	return nil
+}
+
+func quotaRowColor(header, row datafeeder.Row) tcell.Color {
+	percent, err := strconv.Atoi(strings.TrimSuffix(columnOrEmpty(header, row, "PERCENT"), "%"))
+	if err != nil {
+		return tcell.ColorDefault
+	}
+	switch {
+	case percent >= quotaAlertPercent:
+		return tcell.ColorRed
+	case percent >= quotaWarnPercent:
+		return tcell.ColorYellow
+	}
+	return tcell.ColorGreen
+}
@@ -0,0 +1,322 @@
+package k8s
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gdamore/tcell"
+	"github.com/rancher/axe/throwing"
+	"github.com/rancher/axe/throwing/datafeeder"
+	"github.com/rancher/axe/throwing/types"
+	"k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	subjectsKind    = "subjects"
+	permissionsKind = "permissions"
+	// clusterScope is the scope of what a ClusterRoleBinding grants
+	clusterScope = "(cluster)"
+)
+
+// matrixVerbs get a column each in the permission matrix, any other verb goes to OTHER
+var matrixVerbs = []string{"get", "list", "watch", "create", "update", "patch", "delete", "deletecollection"}
+
+// rbacSubject is who a binding grants to, namespace is only set for service accounts
+type rbacSubject struct {
+	kind, name, namespace string
+}
+
+func (s rbacSubject) String() string {
+	if s.namespace != "" {
+		return fmt.Sprintf("%s %s/%s", s.kind, s.namespace, s.name)
+	}
+	return fmt.Sprintf("%s %s", s.kind, s.name)
+}
+
+// groups are the groups the API server puts s in on its own, what's bound to them applies to s as well
+func (s rbacSubject) groups() []string {
+	switch s.kind {
+	case rbacv1.ServiceAccountKind:
+		return []string{"system:serviceaccounts", "system:serviceaccounts:" + s.namespace, "system:authenticated"}
+	case rbacv1.UserKind:
+		return []string{"system:authenticated"}
+	}
+	return nil
+}
+
+// binds reports whether subject of a binding in namespace names s, or a group s is implicitly in
+func (s rbacSubject) binds(subject rbacv1.Subject, namespace string) bool {
+	switch subject.Kind {
+	case rbacv1.ServiceAccountKind:
+		if subject.Namespace != "" {
+			namespace = subject.Namespace
+		}
+		return s.kind == rbacv1.ServiceAccountKind && s.name == subject.Name && s.namespace == namespace
+	case rbacv1.GroupKind:
+		if s.kind == rbacv1.GroupKind && s.name == subject.Name {
+			return true
+		}
+		for _, g := range s.groups() {
+			if g == subject.Name {
+				return true
+			}
+		}
+		return false
+	}
+	return s.kind == subject.Kind && s.name == subject.Name
+}
+
+// rbacBinding is a RoleBinding or a ClusterRoleBinding, they look the same from here
+type rbacBinding struct {
+	kind, namespace, name string
+	roleRef               rbacv1.RoleRef
+	subjects              []rbacv1.Subject
+}
+
+func listBindings(clientset kubernetes.Interface) ([]rbacBinding, error) {
+	clusterBindings, err := clientset.RbacV1().ClusterRoleBindings().List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	bindings, err := clientset.RbacV1().RoleBindings(v1.NamespaceAll).List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var list []rbacBinding
+	for _, b := range clusterBindings.Items {
+		list = append(list, rbacBinding{kind: "ClusterRoleBinding", name: b.Name, roleRef: b.RoleRef, subjects: b.Subjects})
+	}
+	for _, b := range bindings.Items {
+		list = append(list, rbacBinding{kind: "RoleBinding", namespace: b.Namespace, name: b.Name, roleRef: b.RoleRef, subjects: b.Subjects})
+	}
+	return list, nil
+}
+
+// RefreshSubjects lists everyone named in a binding, with the roles bound to them
+func RefreshSubjects(b *bytes.Buffer) error {
+	clientset, err := newClientset()
+	if err != nil {
+		return err
+	}
+	bindings, err := listBindings(clientset)
+	if err != nil {
+		return err
+	}
+	roles := map[rbacSubject]map[string]bool{}
+	for _, binding := range bindings {
+		for _, s := range binding.subjects {
+			subject := rbacSubject{kind: s.Kind, name: s.Name}
+			if s.Kind == rbacv1.ServiceAccountKind {
+				subject.namespace = s.Namespace
+				if subject.namespace == "" {
+					subject.namespace = binding.namespace
+				}
+			}
+			if roles[subject] == nil {
+				roles[subject] = map[string]bool{}
+			}
+			roles[subject][binding.roleRef.Kind+"/"+binding.roleRef.Name] = true
+		}
+	}
+
+	var subjects []rbacSubject
+	for s := range roles {
+		subjects = append(subjects, s)
+	}
+	sort.Slice(subjects, func(i, j int) bool {
+		a, b := subjects[i], subjects[j]
+		if a.kind != b.kind {
+			return a.kind < b.kind
+		}
+		if a.namespace != b.namespace {
+			return a.namespace < b.namespace
+		}
+		return a.name < b.name
+	})
+	var rows [][]string
+	for _, s := range subjects {
+		var names []string
+		for role := range roles[s] {
+			names = append(names, role)
+		}
+		sort.Strings(names)
+		rows = append(rows, []string{s.namespace, s.name, s.kind, summarize(names, "roles")})
+	}
+	writeTable(b, []string{"NAMESPACE", "NAME", "KIND", "ROLES"}, rows)
+	return nil
+}
+
+// permission is a row of the matrix, the verbs the rules of every binding grant on one resource in one scope
+type permission struct {
+	scope, resource, names string
+	verbs                  map[string]bool
+	via                    map[string]bool
+}
+
+// rules fetches the rules of the role a binding refers to
+func rules(clientset kubernetes.Interface, binding rbacBinding) ([]rbacv1.PolicyRule, error) {
+	if binding.roleRef.Kind == "ClusterRole" {
+		role, err := clientset.RbacV1().ClusterRoles().Get(binding.roleRef.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return role.Rules, nil
+	}
+	role, err := clientset.RbacV1().Roles(binding.namespace).Get(binding.roleRef.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return role.Rules, nil
+}
+
+/*
+refreshPermissions renders the effective permission matrix of s: every rule of every role bound to s,
+directly or through a group s is in, merged by scope and resource. VIA names the roles granting a row.
+A binding to a role that's gone is left out, like the authorizer does.
+*/
+func (s rbacSubject) refreshPermissions(b *bytes.Buffer) error {
+	clientset, err := newClientset()
+	if err != nil {
+		return err
+	}
+	bindings, err := listBindings(clientset)
+	if err != nil {
+		return err
+	}
+	permissions := map[string]*permission{}
+	for _, binding := range bindings {
+		bound := false
+		for _, subject := range binding.subjects {
+			if s.binds(subject, binding.namespace) {
+				bound = true
+				break
+			}
+		}
+		if !bound {
+			continue
+		}
+		roleRules, err := rules(clientset, binding)
+		if err != nil {
+			continue
+		}
+		scope := binding.namespace
+		if scope == "" {
+			scope = clusterScope
+		}
+		via := fmt.Sprintf("%s/%s", binding.roleRef.Kind, binding.roleRef.Name)
+		for _, rule := range roleRules {
+			var resources []string
+			for _, group := range rule.APIGroups {
+				for _, resource := range rule.Resources {
+					if group != "" {
+						resource += "." + group
+					}
+					resources = append(resources, resource)
+				}
+			}
+			resources = append(resources, rule.NonResourceURLs...)
+			names := strings.Join(rule.ResourceNames, ",")
+			for _, resource := range resources {
+				key := scope + "\t" + resource + "\t" + names
+				p, ok := permissions[key]
+				if !ok {
+					p = &permission{scope: scope, resource: resource, names: names, verbs: map[string]bool{}, via: map[string]bool{}}
+					permissions[key] = p
+				}
+				for _, verb := range rule.Verbs {
+					p.verbs[verb] = true
+				}
+				p.via[via] = true
+			}
+		}
+	}
+
+	var list []*permission
+	for _, p := range permissions {
+		list = append(list, p)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		a, b := list[i], list[j]
+		// what's granted cluster wide comes first
+		if (a.scope == clusterScope) != (b.scope == clusterScope) {
+			return a.scope == clusterScope
+		}
+		if a.scope != b.scope {
+			return a.scope < b.scope
+		}
+		if a.resource != b.resource {
+			return a.resource < b.resource
+		}
+		return a.names < b.names
+	})
+
+	header := []string{"SCOPE", "RESOURCE", "NAMES"}
+	for _, verb := range matrixVerbs {
+		header = append(header, strings.ToUpper(verb))
+	}
+	header = append(header, "OTHER", "VIA")
+	var rows [][]string
+	for _, p := range list {
+		row := []string{p.scope, p.resource, p.names}
+		known := map[string]bool{}
+		for _, verb := range matrixVerbs {
+			known[verb] = true
+			mark := ""
+			if p.verbs[verb] || p.verbs[rbacv1.VerbAll] {
+				mark = iconOK
+			}
+			row = append(row, mark)
+		}
+		var other, via []string
+		for verb := range p.verbs {
+			if !known[verb] {
+				other = append(other, verb)
+			}
+		}
+		for v := range p.via {
+			via = append(via, v)
+		}
+		sort.Strings(other)
+		sort.Strings(via)
+		rows = append(rows, append(row, strings.Join(other, ","), strings.Join(via, ", ")))
+	}
+	writeTable(b, header, rows)
+	return nil
+}
+
+// permissionRowColor makes rows granting everything stand out
+func permissionRowColor(header, row datafeeder.Row) tcell.Color {
+	if columnOrEmpty(header, row, "RESOURCE") == "*" || strings.HasPrefix(columnOrEmpty(header, row, "RESOURCE"), "*.") ||
+		strings.Contains(columnOrEmpty(header, row, "OTHER"), rbacv1.VerbAll) {
+		return tcell.ColorRed
+	}
+	return tcell.ColorDefault
+}
+
+// showPermissions opens the permission matrix of s
+func showPermissions(t *throwing.TableView, s rbacSubject) {
+	openTable(t, types.ResourceKind{
+		Title: fmt.Sprintf("permissions - %s", s),
+		Kind:  permissionsKind,
+	}, datafeeder.NewDataFeeder(s.refreshPermissions).SetRowColor(permissionRowColor))
+}
+
+// subjectPermissions opens the matrix of the subject selected on the subjects page
+func subjectPermissions(t *throwing.TableView) {
+	namespace, name := getNamespaceAndName(t)
+	if name == "" {
+		return
+	}
+	showPermissions(t, rbacSubject{kind: selectedColumn(t, "KIND"), name: name, namespace: namespace})
+}
+
+// serviceAccountPermissions opens the matrix of the selected service account
+func serviceAccountPermissions(t *throwing.TableView) {
+	namespace, name := getNamespaceAndName(t)
+	showPermissions(t, rbacSubject{kind: rbacv1.ServiceAccountKind, name: name, namespace: namespace})
+}
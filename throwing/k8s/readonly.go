@@ -0,0 +1,47 @@
+package k8s
+
+import (
+	"github.com/rancher/axe/throwing"
+)
+
+// readOnly hides every action that changes the cluster, set with --read-only or readOnly in the config
+var readOnly bool
+
+// mutatingActions are the names of the actions that write to the cluster or run something in it
+var mutatingActions = map[string]bool{
+	"edit":      true,
+	"delete":    true,
+	"exec":      true,
+	"ssh":       true,
+	"command":   true,
+	"patch":     true,
+	"set image": true,
+	"trigger":   true,
+	"rerun":     true,
+	"taint":     true,
+	"drain":     true,
+	"bounds":    true,
+	"rollback":  true,
+	"uninstall": true,
+	"curl":      true,
+	"renew":     true,
+	"silence":   true,
+	"unsilence": true,
+	"probe":     true,
+}
+
+func allowed(a kindAction) bool {
+	return !readOnly || !mutatingActions[a.Name]
+}
+
+// deniedReadOnly stops any verb but reads in read-only mode, in case an action slipped through the menus
+func deniedReadOnly(t *throwing.TableView, verb string) bool {
+	switch verb {
+	case "get", "list", "watch":
+		return false
+	}
+	if readOnly {
+		t.Notify("axe runs in read-only mode, "+verb+" is disabled", throwing.SeverityWarning)
+	}
+	return readOnly
+}
@@ -3,29 +3,33 @@ package k8s
 import (
 	"bytes"
 	"fmt"
-	"os"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/rancher/norman/types/convert"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/apis/meta/v1beta1"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/tools/clientcmd"
 )
 
 type wrapper struct {
 	group, version, name string
+	// namespace and labelSelector narrow the list down, empty means everything
+	namespace, labelSelector string
 }
 
-func (w wrapper) refreshResource(b *bytes.Buffer) error {
-	restConfig, err := clientcmd.BuildConfigFromFlags("", os.Getenv("KUBECONFIG"))
-	if err != nil {
-		return err
+// kind is the resource name qualified by its group, which is also what kubectl accepts
+func (w wrapper) kind() string {
+	if w.group == "" {
+		return w.name
 	}
-	clientset, err := kubernetes.NewForConfig(restConfig)
+	return w.name + "." + w.group
+}
+
+func (w wrapper) refreshResource(b *bytes.Buffer) error {
+	clientset, err := newClientset()
 	if err != nil {
 		return err
 	}
@@ -38,17 +42,13 @@ func (w wrapper) refreshResource(b *bytes.Buffer) error {
 	if w.version == "" {
 		w.version = "v1"
 	}
-	req := restClient.Get().Prefix(apiPrefix, w.group, w.version).Resource(w.name).Param("includeObject", "Object")
-	header := "application/json;as=Table;g=meta.k8s.io;v=v1beta1, application/json"
-	req.SetHeader("Accept", header)
-	table := &v1beta1.Table{}
-	if err := req.Do().Into(table); err != nil {
-		return err
-	}
-
 	namespaced := true
 	groupVersion := strings.Trim(fmt.Sprintf("%s/%s", w.group, w.version), "/")
-	resourceList, err := clientset.ServerResourcesForGroupVersion(groupVersion)
+	d, err := cachedDiscovery()
+	if err != nil {
+		return err
+	}
+	resourceList, err := d.ServerResourcesForGroupVersion(groupVersion)
 	if err != nil {
 		return err
 	}
@@ -58,8 +58,26 @@ func (w wrapper) refreshResource(b *bytes.Buffer) error {
 		}
 	}
 
+	// a table listing one namespace by its scope leaves the NAMESPACE column out
+	listed := ""
+	if namespaced && w.namespace == "" {
+		w.namespace = scopedNamespace(w.kind())
+		listed = w.namespace
+	}
+	setListedNamespace(w.kind(), listed)
+	req := restClient.Get().Prefix(apiPrefix, w.group, w.version).Namespace(w.namespace).Resource(w.name).Param("includeObject", "Object")
+	if w.labelSelector != "" {
+		req.Param("labelSelector", w.labelSelector)
+	}
+	header := "application/json;as=Table;g=meta.k8s.io;v=v1beta1, application/json"
+	req.SetHeader("Accept", header)
+	table := &v1beta1.Table{}
+	if err := req.Do().Into(table); err != nil {
+		return err
+	}
+
 	// insert namespace
-	if namespaced {
+	if namespaced && listed == "" {
 		table.ColumnDefinitions = append([]v1beta1.TableColumnDefinition{
 			{
 				Name: "NAMESPACE",
@@ -67,6 +85,15 @@ func (w wrapper) refreshResource(b *bytes.Buffer) error {
 		}, table.ColumnDefinitions...)
 	}
 
+	pods := w.kind() == "pods"
+	if pods {
+		table.ColumnDefinitions = append(table.ColumnDefinitions, v1beta1.TableColumnDefinition{Name: "TREND"})
+	}
+	security := showPodSecurity() && pods
+	if security {
+		table.ColumnDefinitions = append(table.ColumnDefinitions, v1beta1.TableColumnDefinition{Name: "SECURITY"})
+	}
+
 	for i, header := range table.ColumnDefinitions {
 		b.Write([]byte(strings.ToUpper(header.Name)))
 		if i == len(table.ColumnDefinitions)-1 {
@@ -76,6 +103,8 @@ func (w wrapper) refreshResource(b *bytes.Buffer) error {
 		}
 	}
 
+	times := map[string]cellTimes{}
+	now := time.Now()
 	for _, row := range table.Rows {
 		converted, err := runtime.Decode(unstructured.UnstructuredJSONScheme, row.Object.Raw)
 		if err != nil {
@@ -87,9 +116,19 @@ func (w wrapper) refreshResource(b *bytes.Buffer) error {
 		if ok {
 			namespace = object.GetNamespace()
 		}
-		if namespaced {
+		if obj, ok := converted.(*unstructured.Unstructured); ok {
+			times[namespace+"/"+obj.GetName()] = timesOf(obj)
+			recordObject(objectRef{kind: w.kind(), namespace: namespace, name: obj.GetName()}, obj)
+		}
+		if namespaced && listed == "" {
 			row.Cells = append([]interface{}{namespace}, row.Cells...)
 		}
+		if obj, ok := converted.(*unstructured.Unstructured); ok && pods {
+			row.Cells = append(row.Cells, restartTrend(obj, now))
+		}
+		if obj, ok := converted.(*unstructured.Unstructured); ok && security {
+			row.Cells = append(row.Cells, securityColumn(obj))
+		}
 		for i, column := range row.Cells {
 			b.Write([]byte(convert.ToString(column)))
 			if i == len(row.Cells)-1 {
@@ -99,21 +138,21 @@ func (w wrapper) refreshResource(b *bytes.Buffer) error {
 			}
 		}
 	}
+	recordTimes(w.kind(), times)
 	return nil
 }
 
 func RefreshResourceKind(b *bytes.Buffer) error {
-	restConfig, err := clientcmd.BuildConfigFromFlags("", os.Getenv("KUBECONFIG"))
+	d, err := cachedDiscovery()
 	if err != nil {
 		return err
 	}
-	clientset := kubernetes.NewForConfigOrDie(restConfig)
 
 	Header := []string{
 		"NAME",
 		"GROUPVERSION",
 	}
-	list, err := clientset.Discovery().ServerPreferredResources()
+	list, err := d.ServerPreferredResources()
 	if err != nil {
 		return err
 	}
@@ -152,3 +191,22 @@ func RefreshResourceKind(b *bytes.Buffer) error {
 	}
 	return nil
 }
+
+// writeTable writes header and rows in the tab separated format the data feeder parses
+func writeTable(b *bytes.Buffer, header []string, rows [][]string) {
+	for _, row := range append([][]string{header}, rows...) {
+		for i, column := range row {
+			b.WriteString(strings.Map(func(r rune) rune {
+				if r == '\t' || r == '\n' {
+					return ' '
+				}
+				return r
+			}, column))
+			if i == len(row)-1 {
+				b.WriteString("\n")
+			} else {
+				b.WriteString("\t")
+			}
+		}
+	}
+}
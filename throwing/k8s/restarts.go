@@ -0,0 +1,130 @@
+package k8s
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// restartBucket is the time one character of the TREND column covers
+	restartBucket = 5 * time.Minute
+	// restartBuckets is how many of them are drawn, a pod restarting every 20 minutes shows two spikes
+	restartBuckets = 8
+	restartWindow  = restartBucket * restartBuckets
+)
+
+// sparkRunes draw a bucket by how many restarts it saw, a quiet one is _ so the trend reads in ASCII as well
+var sparkRunes = []rune("▂▃▄▅▆▇█")
+
+// restartHistory is what the refreshes of this session saw of a pod's restart count
+type restartHistory struct {
+	uid       types.UID
+	firstSeen time.Time
+	lastSeen  time.Time
+	restarts  int64
+	// increases are when the count went up and by how much, older than restartWindow they're dropped
+	increases []restartIncrease
+}
+
+type restartIncrease struct {
+	at time.Time
+	by int64
+}
+
+// restartHistories are keyed by namespace/name, a pod recreated under the same name starts over
+var restartHistories = struct {
+	sync.Mutex
+	byPod map[string]*restartHistory
+}{
+	byPod: map[string]*restartHistory{},
+}
+
+// podRestarts adds up the restart counts of every container of a listed pod
+func podRestarts(obj *unstructured.Unstructured) int64 {
+	var total int64
+	for _, field := range []string{"initContainerStatuses", "containerStatuses"} {
+		statuses, _, _ := unstructured.NestedSlice(obj.Object, "status", field)
+		for _, s := range statuses {
+			if s, ok := s.(map[string]interface{}); ok {
+				count, _, _ := unstructured.NestedInt64(s, "restartCount")
+				total += count
+			}
+		}
+	}
+	return total
+}
+
+/*
+restartTrend records the restart count of a listed pod and draws the TREND column: one character per
+restartBucket since the pod was first seen, oldest first, and how many restarts the window saw.
+Restarts from before axe saw the pod don't count, only the count going up does.
+*/
+func restartTrend(obj *unstructured.Unstructured, now time.Time) string {
+	key := obj.GetNamespace() + "/" + obj.GetName()
+	restarts := podRestarts(obj)
+
+	restartHistories.Lock()
+	defer restartHistories.Unlock()
+	for k, h := range restartHistories.byPod {
+		if now.Sub(h.lastSeen) > restartWindow {
+			delete(restartHistories.byPod, k)
+		}
+	}
+	h, ok := restartHistories.byPod[key]
+	if !ok || h.uid != obj.GetUID() {
+		h = &restartHistory{uid: obj.GetUID(), firstSeen: now, restarts: restarts}
+		restartHistories.byPod[key] = h
+	}
+	h.lastSeen = now
+	if restarts > h.restarts {
+		h.increases = append(h.increases, restartIncrease{at: now, by: restarts - h.restarts})
+	}
+	h.restarts = restarts
+	for len(h.increases) > 0 && now.Sub(h.increases[0].at) >= restartWindow {
+		h.increases = h.increases[1:]
+	}
+	return h.trend(now)
+}
+
+func (h *restartHistory) trend(now time.Time) string {
+	buckets := int(now.Sub(h.firstSeen)/restartBucket) + 1
+	if buckets > restartBuckets {
+		buckets = restartBuckets
+	}
+	counts := make([]int64, buckets)
+	var total int64
+	for _, increase := range h.increases {
+		i := buckets - 1 - int(now.Sub(increase.at)/restartBucket)
+		if i < 0 {
+			continue
+		}
+		counts[i] += increase.by
+		total += increase.by
+	}
+
+	b := &strings.Builder{}
+	for _, count := range counts {
+		switch {
+		case count == 0:
+			b.WriteRune('_')
+		case count > int64(len(sparkRunes)):
+			b.WriteRune(sparkRunes[len(sparkRunes)-1])
+		default:
+			b.WriteRune(sparkRunes[count-1])
+		}
+	}
+	if total > 0 {
+		fmt.Fprintf(b, " +%d", total)
+	}
+	return b.String()
+}
+
+// restartedRecently tells a TREND value with restarts in the window from a quiet one
+func restartedRecently(trend string) bool {
+	return strings.Contains(trend, "+")
+}
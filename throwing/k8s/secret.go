@@ -0,0 +1,81 @@
+package k8s
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/gdamore/tcell"
+	"github.com/rancher/axe/throwing"
+	"github.com/rivo/tview"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const redacted = "********"
+
+/*
+viewSecret lists the secret keys with their decoded values, hidden until revealed.
+
+s: show/hide values
+c: copy the selected value to the clipboard
+*/
+func viewSecret(t *throwing.TableView) {
+	namespace, name := getNamespaceAndName(t)
+	secret, err := t.GetClientSet().CoreV1().Secrets(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		t.Notify(err.Error(), throwing.SeverityError)
+		return
+	}
+
+	var keys []string
+	for k := range secret.Data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	table := tview.NewTable()
+	table.SetBorder(true)
+	table.SetTitle(fmt.Sprintf("secret - (%s/%s) [s] show/hide [c] copy", namespace, name))
+	table.SetTitleColor(tcell.ColorPurple)
+	table.SetBackgroundColor(tcell.ColorBlack)
+	table.SetSelectable(true, false)
+	table.SetFixed(1, 0)
+
+	reveal := false
+	render := func() {
+		table.Clear()
+		table.SetCell(0, 0, tview.NewTableCell("KEY").SetSelectable(false).SetAttributes(tcell.AttrBold))
+		table.SetCell(0, 1, tview.NewTableCell("VALUE").SetSelectable(false).SetAttributes(tcell.AttrBold))
+		for i, k := range keys {
+			value := redacted
+			if reveal {
+				value = tview.Escape(string(secret.Data[k]))
+			}
+			table.SetCell(i+1, 0, tview.NewTableCell(k).SetTextColor(tcell.ColorAntiqueWhite))
+			table.SetCell(i+1, 1, tview.NewTableCell(value).SetTextColor(tcell.ColorAntiqueWhite).SetExpansion(1))
+		}
+	}
+	render()
+
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Rune() {
+		case 's':
+			reveal = !reveal
+			render()
+		case 'c':
+			row, _ := table.GetSelection()
+			if row < 1 || row > len(keys) {
+				return event
+			}
+			key := keys[row-1]
+			if err := copyToClipboard(string(secret.Data[key])); err != nil {
+				t.Notify(err.Error(), throwing.SeverityError)
+				return event
+			}
+			t.Notify(fmt.Sprintf("value of %s copied", key), throwing.SeverityInfo)
+		}
+		return event
+	})
+
+	newpage := tview.NewPages().AddPage("secret", table, true, true)
+	t.SwitchPage(t.GetCurrentPage(), newpage)
+}
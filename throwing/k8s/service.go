@@ -0,0 +1,179 @@
+package k8s
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell"
+	"github.com/rancher/axe/throwing"
+	"github.com/rivo/tview"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	portForwardTimeout = 10 * time.Second
+	curlTimeout        = 5 * time.Second
+)
+
+var forwardingFrom = regexp.MustCompile(`Forwarding from 127\.0\.0\.1:(\d+)`)
+
+/*
+viewService shows the service ports and the endpoints behind them.
+
+u: port-forward and send a test request
+*/
+func viewService(t *throwing.TableView) {
+	namespace, name := getNamespaceAndName(t)
+	client := t.GetClientSet()
+	service, err := client.CoreV1().Services(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		t.Notify(err.Error(), throwing.SeverityError)
+		return
+	}
+	endpoints, err := client.CoreV1().Endpoints(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		t.Notify(err.Error(), throwing.SeverityError)
+		return
+	}
+
+	table := tview.NewTable()
+	table.SetBorder(true)
+	table.SetTitle(fmt.Sprintf("service - (%s/%s) %s %s [u] curl", namespace, name, service.Spec.Type, service.Spec.ClusterIP))
+	table.SetTitleColor(tcell.ColorPurple)
+	table.SetBackgroundColor(tcell.ColorBlack)
+	table.SetSelectable(true, false)
+	table.SetFixed(1, 0)
+
+	for col, h := range []string{"ADDRESS", "PORTS", "READY", "TARGET", "NODE"} {
+		table.SetCell(0, col, tview.NewTableCell(h).SetSelectable(false).SetAttributes(tcell.AttrBold).SetExpansion(1))
+	}
+	row := 1
+	addAddresses := func(addresses []v1.EndpointAddress, ports []v1.EndpointPort, ready bool) {
+		var portList []string
+		for _, p := range ports {
+			portList = append(portList, fmt.Sprintf("%s %d/%s", p.Name, p.Port, p.Protocol))
+		}
+		readyText, color := "yes", tcell.ColorGreen
+		if !ready {
+			readyText, color = "no", tcell.ColorRed
+		}
+		for _, a := range addresses {
+			target := ""
+			if a.TargetRef != nil {
+				target = fmt.Sprintf("%s/%s", strings.ToLower(a.TargetRef.Kind), a.TargetRef.Name)
+			}
+			node := ""
+			if a.NodeName != nil {
+				node = *a.NodeName
+			}
+			for col, value := range []string{a.IP, strings.Join(portList, ", "), readyText, target, node} {
+				table.SetCell(row, col, tview.NewTableCell(value).SetTextColor(color).SetExpansion(1))
+			}
+			row++
+		}
+	}
+	for _, subset := range endpoints.Subsets {
+		addAddresses(subset.Addresses, subset.Ports, true)
+		addAddresses(subset.NotReadyAddresses, subset.Ports, false)
+	}
+	if row == 1 {
+		table.SetCell(1, 0, tview.NewTableCell("no endpoints").SetTextColor(tcell.ColorRed))
+	}
+
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Rune() == 'u' {
+			curlService(t)
+			return nil
+		}
+		return event
+	})
+
+	newpage := tview.NewPages().AddPage("service", table, true, true)
+	t.SwitchPage(t.GetCurrentPage(), newpage)
+}
+
+// curlService port-forwards to the first port of the service and reports the status of GET /
+func curlService(t *throwing.TableView) {
+	namespace, name := getNamespaceAndName(t)
+	if !canI(t, "create", "pods", "portforward", namespace) {
+		return
+	}
+	service, err := t.GetClientSet().CoreV1().Services(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		t.Notify(err.Error(), throwing.SeverityError)
+		return
+	}
+	if len(service.Spec.Ports) == 0 {
+		t.Notify(fmt.Sprintf("service %s has no ports", name), throwing.SeverityWarning)
+		return
+	}
+	port := service.Spec.Ports[0].Port
+
+	t.Notify(fmt.Sprintf("port-forwarding to %s:%d", name, port), throwing.SeverityProgress)
+	t.Go(func() {
+		status, err := portForwardAndGet(namespace, "svc/"+name, port)
+		if err != nil {
+			t.Notify(err.Error(), throwing.SeverityError)
+			return
+		}
+		severity := throwing.SeverityInfo
+		if !strings.HasPrefix(status, "2") && !strings.HasPrefix(status, "3") {
+			severity = throwing.SeverityWarning
+		}
+		t.Notify(fmt.Sprintf("GET %s:%d/ -> %s", name, port, status), severity)
+	})
+}
+
+func portForwardAndGet(namespace, target string, port int32) (string, error) {
+	cmd := kubectl("port-forward", "-n", namespace, target, fmt.Sprintf(":%d", port))
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return "", err
+	}
+	errB := &strings.Builder{}
+	cmd.Stderr = errB
+	if err := cmd.Start(); err != nil {
+		return "", err
+	}
+	// the tunnel goes with the request, Wait reaps kubectl
+	defer func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	}()
+
+	localPort := make(chan string, 1)
+	go func() {
+		scanner := bufio.NewScanner(stdout)
+		for scanner.Scan() {
+			if m := forwardingFrom.FindStringSubmatch(scanner.Text()); m != nil {
+				localPort <- m[1]
+				break
+			}
+		}
+		// keep draining so kubectl never blocks on a full pipe
+		io.Copy(ioutil.Discard, stdout)
+	}()
+
+	select {
+	case p := <-localPort:
+		client := http.Client{Timeout: curlTimeout}
+		resp, err := client.Get(fmt.Sprintf("http://127.0.0.1:%s/", p))
+		if err != nil {
+			return "", err
+		}
+		resp.Body.Close()
+		return resp.Status, nil
+	case <-time.After(portForwardTimeout):
+		if errB.Len() > 0 {
+			return "", fmt.Errorf("port-forward failed: %s", errB.String())
+		}
+		return "", fmt.Errorf("port-forward to %s did not become ready", target)
+	}
+}
@@ -0,0 +1,89 @@
+package k8s
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/rancher/axe/throwing"
+	"github.com/rancher/norman/pkg/kv"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+/*
+sshConfig is how axe reaches the nodes, from ssh in the config.
+With JumpPod set to namespace/name the session runs ssh inside that pod, Key is then a path in the pod.
+*/
+type sshConfig struct {
+	User    string   `json:"user,omitempty"`
+	Key     string   `json:"key,omitempty"`
+	Port    int      `json:"port,omitempty"`
+	JumpPod string   `json:"jumpPod,omitempty"`
+	Options []string `json:"options,omitempty"`
+}
+
+var nodeSSH sshConfig
+
+// sshNode opens an ssh session to the selected node the way exec opens a shell in a pod
+func sshNode(t *throwing.TableView) {
+	_, name := getNamespaceAndName(t)
+	if deniedReadOnly(t, "ssh") {
+		return
+	}
+	node, err := t.GetClientSet().CoreV1().Nodes().Get(name, metav1.GetOptions{})
+	if err != nil {
+		t.Notify(err.Error(), throwing.SeverityError)
+		return
+	}
+	address := nodeAddress(node)
+	if address == "" {
+		t.Notify(fmt.Sprintf("node %s has no address to ssh to", name), throwing.SeverityError)
+		return
+	}
+
+	args := nodeSSH.args(address)
+	if nodeSSH.JumpPod == "" {
+		interactive(t, "ssh", name, exec.Command("ssh", args...))
+		return
+	}
+	namespace, pod := kv.Split(nodeSSH.JumpPod, "/")
+	if pod == "" {
+		t.Notify(fmt.Sprintf("jump pod %q isn't namespace/name", nodeSSH.JumpPod), throwing.SeverityError)
+		return
+	}
+	if !canI(t, "create", "pods", "exec", namespace) {
+		return
+	}
+	interactive(t, "ssh", name, kubectl(append([]string{"exec", "-it", "-n", namespace, pod, "--", "ssh"}, args...)...))
+}
+
+func (c sshConfig) args(address string) []string {
+	args := []string{"-t"}
+	if c.Key != "" {
+		args = append(args, "-i", c.Key)
+	}
+	if c.Port != 0 {
+		args = append(args, "-p", strconv.Itoa(c.Port))
+	}
+	for _, option := range c.Options {
+		args = append(args, "-o", option)
+	}
+	if c.User != "" {
+		address = c.User + "@" + address
+	}
+	return append(args, address)
+}
+
+// nodeAddress prefers the external address of the node, then the internal one, then its hostname
+func nodeAddress(node *v1.Node) string {
+	for _, addressType := range []v1.NodeAddressType{v1.NodeExternalIP, v1.NodeInternalIP, v1.NodeHostName} {
+		for _, address := range node.Status.Addresses {
+			if address.Type == addressType && strings.TrimSpace(address.Address) != "" {
+				return address.Address
+			}
+		}
+	}
+	return ""
+}
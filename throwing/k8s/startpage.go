@@ -0,0 +1,86 @@
+package k8s
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rancher/axe/throwing"
+	"k8s.io/client-go/discovery"
+)
+
+/*
+startTarget is the view named on the command line, like kubectl get takes it:
+
+	axe pods -n kube-system
+	axe deploy/my-app
+	axe deploy my-app -n web
+
+The flags come before it except --namespace, which is looked for after it as well.
+*/
+type startTarget struct {
+	kind      string
+	name      string
+	namespace string
+}
+
+func parseStartTarget(args []string) (startTarget, error) {
+	var target startTarget
+	var positional []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "-n" || arg == "--namespace":
+			if i+1 == len(args) {
+				return target, fmt.Errorf("%s needs a namespace", arg)
+			}
+			i++
+			target.namespace = args[i]
+		case strings.HasPrefix(arg, "-n="), strings.HasPrefix(arg, "--namespace="):
+			target.namespace = arg[strings.Index(arg, "=")+1:]
+		case strings.HasPrefix(arg, "-"):
+			return target, fmt.Errorf("flag %s has to come before %s", arg, args[0])
+		default:
+			positional = append(positional, arg)
+		}
+	}
+	switch len(positional) {
+	case 0:
+	case 1:
+		target.kind, target.name = positional[0], ""
+		if i := strings.Index(target.kind, "/"); i >= 0 {
+			target.kind, target.name = target.kind[:i], target.kind[i+1:]
+		}
+	case 2:
+		target.kind, target.name = positional[0], positional[1]
+	default:
+		return target, fmt.Errorf("expected a kind and a name at most, got %s", strings.Join(positional, " "))
+	}
+	if target.kind == "" && target.name != "" {
+		return target, fmt.Errorf("%q has no kind", positional[0])
+	}
+	return target, nil
+}
+
+// startPage resolves the kind of target before the UI comes up, so a typo fails like kubectl does
+func startPage(d discovery.DiscoveryInterface, target startTarget) (func(root *throwing.TableView), error) {
+	gvr, err := resolveKind(d, target.kind)
+	if err != nil {
+		return nil, err
+	}
+	w := wrapper{group: gvr.Group, version: gvr.Version, name: gvr.Resource}
+	kind := kindOf(gvr)
+	return func(root *throwing.TableView) {
+		openResourceTable(root, w, kind)
+		if target.name == "" {
+			return
+		}
+		namespace := target.namespace
+		if namespace == "" {
+			namespace = currentNamespace()
+		}
+		table := root.GetNestedTable(kind)
+		if table == nil || !table.SelectRow(namespace, target.name) {
+			root.Notify(fmt.Sprintf("%s %s not found", kind, target.name), throwing.SeverityWarning)
+		}
+	}, nil
+}
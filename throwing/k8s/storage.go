@@ -0,0 +1,209 @@
+package k8s
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell"
+	"github.com/rancher/axe/throwing"
+	"github.com/rancher/axe/throwing/datafeeder"
+	"github.com/rancher/axe/throwing/types"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func accessModes(modes []v1.PersistentVolumeAccessMode) string {
+	var short []string
+	for _, m := range modes {
+		switch m {
+		case v1.ReadWriteOnce:
+			short = append(short, "RWO")
+		case v1.ReadOnlyMany:
+			short = append(short, "ROX")
+		case v1.ReadWriteMany:
+			short = append(short, "RWX")
+		default:
+			short = append(short, string(m))
+		}
+	}
+	return strings.Join(short, ",")
+}
+
+func storageClass(name *string) string {
+	if name == nil || *name == "" {
+		return "<default>"
+	}
+	return *name
+}
+
+func (w wrapper) refreshClaims(b *bytes.Buffer) error {
+	clientset, err := newClientset()
+	if err != nil {
+		return err
+	}
+	namespace, scoped := w.listScope()
+	claims, err := clientset.CoreV1().PersistentVolumeClaims(namespace).List(metav1.ListOptions{LabelSelector: w.labelSelector})
+	if err != nil {
+		return err
+	}
+	var rows [][]string
+	for _, c := range claims.Items {
+		capacity := "-"
+		if q, ok := c.Status.Capacity[v1.ResourceStorage]; ok {
+			capacity = q.String()
+		} else if q, ok := c.Spec.Resources.Requests[v1.ResourceStorage]; ok {
+			capacity = q.String() + " (requested)"
+		}
+		rows = append(rows, []string{
+			c.Namespace,
+			c.Name,
+			string(c.Status.Phase),
+			c.Spec.VolumeName,
+			capacity,
+			accessModes(c.Status.AccessModes),
+			storageClass(c.Spec.StorageClassName),
+			age(c.CreationTimestamp),
+		})
+	}
+	writeScopedTable(b, scoped, []string{"NAMESPACE", "NAME", "STATUS", "VOLUME", "CAPACITY", "ACCESS MODES", "STORAGECLASS", "AGE"}, rows)
+	return nil
+}
+
+func (w wrapper) refreshVolumes(b *bytes.Buffer) error {
+	clientset, err := newClientset()
+	if err != nil {
+		return err
+	}
+	volumes, err := clientset.CoreV1().PersistentVolumes().List(metav1.ListOptions{LabelSelector: w.labelSelector})
+	if err != nil {
+		return err
+	}
+	// volumes aren't namespaced, the namespaces action narrows them down to the ones claimed from the namespace
+	namespace := scopedNamespace(w.kind())
+	var rows [][]string
+	for _, pv := range volumes.Items {
+		if namespace != "" && (pv.Spec.ClaimRef == nil || pv.Spec.ClaimRef.Namespace != namespace) {
+			continue
+		}
+		capacity := pv.Spec.Capacity[v1.ResourceStorage]
+		claim := ""
+		if pv.Spec.ClaimRef != nil {
+			claim = pv.Spec.ClaimRef.Namespace + "/" + pv.Spec.ClaimRef.Name
+		}
+		rows = append(rows, []string{
+			pv.Name,
+			capacity.String(),
+			accessModes(pv.Spec.AccessModes),
+			string(pv.Spec.PersistentVolumeReclaimPolicy),
+			string(pv.Status.Phase),
+			claim,
+			storageClass(&pv.Spec.StorageClassName),
+			age(pv.CreationTimestamp),
+		})
+	}
+	writeTable(b, []string{"NAME", "CAPACITY", "ACCESS MODES", "RECLAIM POLICY", "STATUS", "CLAIM", "STORAGECLASS", "AGE"}, rows)
+	return nil
+}
+
+func storageRowColor(header, row datafeeder.Row) tcell.Color {
+	switch columnOrEmpty(header, row, "STATUS") {
+	case string(v1.ClaimBound):
+		return tcell.ColorGreen
+	case string(v1.ClaimPending):
+		return tcell.ColorYellow
+	case string(v1.ClaimLost), string(v1.VolumeFailed):
+		return tcell.ColorRed
+	}
+	return tcell.ColorDefault
+}
+
+// podsMountingClaim lists the pods of a namespace that have the claim as a volume
+type podsMountingClaim struct {
+	namespace, claim string
+}
+
+func (p podsMountingClaim) refresh(b *bytes.Buffer) error {
+	clientset, err := newClientset()
+	if err != nil {
+		return err
+	}
+	pods, err := clientset.CoreV1().Pods(p.namespace).List(metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	var rows [][]string
+	for _, pod := range pods.Items {
+		mounted := false
+		for _, volume := range pod.Spec.Volumes {
+			if volume.PersistentVolumeClaim != nil && volume.PersistentVolumeClaim.ClaimName == p.claim {
+				mounted = true
+			}
+		}
+		if !mounted {
+			continue
+		}
+		ready, restarts := 0, int32(0)
+		for _, cs := range pod.Status.ContainerStatuses {
+			if cs.Ready {
+				ready++
+			}
+			restarts += cs.RestartCount
+		}
+		rows = append(rows, []string{
+			pod.Namespace,
+			pod.Name,
+			fmt.Sprintf("%d/%d", ready, len(pod.Spec.Containers)),
+			string(pod.Status.Phase),
+			fmt.Sprint(restarts),
+			age(pod.CreationTimestamp),
+			pod.Spec.NodeName,
+		})
+	}
+	writeTable(b, []string{"NAMESPACE", "NAME", "READY", "STATUS", "RESTARTS", "AGE", "NODE"}, rows)
+	return nil
+}
+
+func claimVolume(t *throwing.TableView) {
+	volume := selectedColumn(t, "VOLUME")
+	if volume == "" {
+		t.Notify("claim is not bound to a volume yet", throwing.SeverityWarning)
+		return
+	}
+	w := wrapper{version: "v1", name: "persistentvolumes"}
+	openResourceTable(t, w, w.kind())
+	if nt := t.GetNestedTable(w.kind()); nt != nil {
+		nt.SelectRow("", volume)
+	}
+}
+
+func claimPods(t *throwing.TableView) {
+	namespace, name := getNamespaceAndName(t)
+	p := podsMountingClaim{namespace: namespace, claim: name}
+	openTable(t, types.ResourceKind{
+		Title: fmt.Sprintf("pods (claim %s/%s)", namespace, name),
+		Kind:  "pods",
+	}, datafeeder.NewDataFeeder(p.refresh).SetRowColor(podRowColor))
+}
+
+func volumeClaim(t *throwing.TableView) {
+	claim := selectedColumn(t, "CLAIM")
+	namespace, name := splitNamespacedName(claim)
+	if name == "" {
+		t.Notify("volume is not claimed", throwing.SeverityWarning)
+		return
+	}
+	w := wrapper{version: "v1", name: "persistentvolumeclaims", namespace: namespace}
+	openResourceTable(t, w, fmt.Sprintf("%s (%s)", w.kind(), namespace))
+	if nt := t.GetNestedTable(w.kind()); nt != nil {
+		nt.SelectRow(namespace, name)
+	}
+}
+
+func splitNamespacedName(s string) (string, string) {
+	parts := strings.SplitN(s, "/", 2)
+	if len(parts) != 2 {
+		return "", s
+	}
+	return parts[0], parts[1]
+}
@@ -0,0 +1,87 @@
+package k8s
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/rancher/axe/throwing"
+)
+
+// How an action opens its session inside tmux
+const (
+	tmuxPane   = "pane"
+	tmuxWindow = "window"
+	tmuxOff    = "off"
+)
+
+// tmuxActions are the actions running an interactive command that can move to tmux
+var tmuxActions = []string{"exec", "logs", "ssh"}
+
+// tmuxModes maps action names to tmuxPane or tmuxWindow, set from --tmux and tmux in the config
+var tmuxModes = map[string]string{}
+
+// configureTmux opens every session in a pane with --tmux, the config sets the mode per action
+func configureTmux(enabled bool, modes map[string]string) error {
+	if enabled {
+		for _, action := range tmuxActions {
+			tmuxModes[action] = tmuxPane
+		}
+	}
+	for action, mode := range modes {
+		switch mode {
+		case tmuxPane, tmuxWindow:
+			tmuxModes[action] = mode
+		case tmuxOff:
+			delete(tmuxModes, action)
+		default:
+			return fmt.Errorf("tmux mode of %s: %q is none of %s, %s or %s", action, mode, tmuxPane, tmuxWindow, tmuxOff)
+		}
+	}
+	return nil
+}
+
+/*
+inTmux runs cmd in a new tmux pane or window when axe runs inside tmux and action is set up for it, so the UI keeps running.
+It returns false when the caller has to run cmd itself.
+*/
+func inTmux(t *throwing.TableView, action, title string, cmd *exec.Cmd) bool {
+	mode := tmuxModes[action]
+	if mode == "" || os.Getenv("TMUX") == "" {
+		return false
+	}
+
+	var args []string
+	if mode == tmuxWindow {
+		args = []string{"new-window", "-n", title}
+	} else {
+		args = []string{"split-window", "-h"}
+	}
+	args = append(args, tmuxCommand(cmd))
+	if out, err := exec.Command("tmux", args...).CombinedOutput(); err != nil {
+		t.Notify(fmt.Sprintf("tmux %s: %s", mode, strings.TrimSpace(string(out))), throwing.SeverityError)
+	}
+	return true
+}
+
+// tmuxCommand is cmd as one shell command, the pane starts with the environment of the tmux server rather than ours
+func tmuxCommand(cmd *exec.Cmd) string {
+	var words []string
+	if kubeconfig := os.Getenv("KUBECONFIG"); kubeconfig != "" {
+		words = append(words, "env", shellQuote("KUBECONFIG="+kubeconfig))
+	}
+	for _, arg := range cmd.Args {
+		words = append(words, shellQuote(arg))
+	}
+	return strings.Join(words, " ")
+}
+
+func shellQuote(s string) string {
+	if s != "" && strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./=:,@+", r))
+	}) < 0 {
+		return s
+	}
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
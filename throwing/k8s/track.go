@@ -0,0 +1,194 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rancher/axe/throwing"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// trackRetryPeriod is how long tracking waits before watching again after the watch failed or expired
+const trackRetryPeriod = 5 * time.Second
+
+// tracked holds the objects being tracked
+var tracked = struct {
+	sync.Mutex
+	objects map[objectRef]*tracking
+}{
+	objects: map[objectRef]*tracking{},
+}
+
+type tracking struct {
+	stop context.CancelFunc
+}
+
+/*
+trackResource watches the selected object in the background until it's tracked again or deleted.
+Whenever its status changes the terminal bell rings and the change shows up in the status bar, whatever page is on screen.
+*/
+func trackResource(t *throwing.TableView) {
+	namespace, name := getNamespaceAndName(t)
+	ref := objectRef{kind: t.GetResourceKind(), namespace: namespace, name: name}
+
+	tracked.Lock()
+	defer tracked.Unlock()
+	if tr, ok := tracked.objects[ref]; ok {
+		tr.stop()
+		delete(tracked.objects, ref)
+		t.Notify(fmt.Sprintf("stopped tracking %s", ref), throwing.SeverityInfo)
+		return
+	}
+	gvr, err := groupVersionResource(t.GetClientSet(), ref.kind)
+	if err != nil {
+		t.Notify(err.Error(), throwing.SeverityError)
+		return
+	}
+	ctx, stop := context.WithCancel(t.Context())
+	tr := &tracking{stop: stop}
+	tracked.objects[ref] = tr
+	t.Go(func() {
+		defer untrack(ref, tr)
+		track(ctx, t, gvr, ref)
+	})
+}
+
+// untrack forgets tr once its goroutine ended, unless ref is tracked anew by then
+func untrack(ref objectRef, tr *tracking) {
+	tr.stop()
+	tracked.Lock()
+	defer tracked.Unlock()
+	if tracked.objects[ref] == tr {
+		delete(tracked.objects, ref)
+	}
+}
+
+// track watches ref until ctx is done or the object is gone, a new watch picks up from the last status seen
+func track(ctx context.Context, t *throwing.TableView, gvr schema.GroupVersionResource, ref objectRef) {
+	last := ""
+	for {
+		done, err := watchStatus(ctx, t, gvr, ref, &last)
+		if done {
+			return
+		}
+		if err != nil {
+			logrus.Debugf("tracking %s: %v", ref, err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(trackRetryPeriod):
+		}
+	}
+}
+
+// watchStatus reports status changes of ref into last until the watch ends, done is set once ref is deleted or ctx is done
+func watchStatus(ctx context.Context, t *throwing.TableView, gvr schema.GroupVersionResource, ref objectRef, last *string) (done bool, err error) {
+	client, err := newDynamicClient()
+	if err != nil {
+		return false, err
+	}
+	w, err := client.Resource(gvr).Namespace(ref.namespace).Watch(metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("metadata.name", ref.name).String(),
+	})
+	if err != nil {
+		return false, err
+	}
+	defer w.Stop()
+	defer stopWith(ctx, w.Stop)()
+
+	for event := range w.ResultChan() {
+		switch event.Type {
+		case watch.Error:
+			return false, fmt.Errorf("watch failed: %v", event.Object)
+		case watch.Deleted:
+			t.Bell()
+			t.Notify(fmt.Sprintf("%s was deleted, stopped tracking it", ref), throwing.SeverityWarning)
+			return true, nil
+		}
+		obj, ok := event.Object.(*unstructured.Unstructured)
+		if !ok {
+			continue
+		}
+		status := objectStatus(obj)
+		switch {
+		case *last == "":
+			t.Notify(fmt.Sprintf("tracking %s: %s", ref, status), throwing.SeverityInfo)
+		case status != *last:
+			t.Bell()
+			t.Notify(fmt.Sprintf("%s: %s -> %s", ref, *last, status), statusSeverity(status))
+		}
+		*last = status
+	}
+	if ctx.Err() != nil {
+		return true, nil
+	}
+	return false, fmt.Errorf("watch closed")
+}
+
+/*
+objectStatus sums up the parts of the status worth being told about: the phase, why containers are
+waiting or terminated, ready replicas and the conditions, e.g. "Running, ready 1/1" or "Available=True Progressing=True".
+*/
+func objectStatus(obj *unstructured.Unstructured) string {
+	var parts []string
+	if phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase"); phase != "" {
+		parts = append(parts, phase)
+	}
+	containers, _, _ := unstructured.NestedSlice(obj.Object, "status", "containerStatuses")
+	ready := 0
+	for _, c := range containers {
+		container, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if isReady, _, _ := unstructured.NestedBool(container, "ready"); isReady {
+			ready++
+		}
+		for _, state := range []string{"waiting", "terminated"} {
+			if reason, _, _ := unstructured.NestedString(container, "state", state, "reason"); reason != "" {
+				parts = append(parts, reason)
+			}
+		}
+	}
+	if len(containers) > 0 {
+		parts = append(parts, fmt.Sprintf("ready %d/%d", ready, len(containers)))
+	}
+	if replicas, ok, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas"); ok {
+		readyReplicas, _, _ := unstructured.NestedInt64(obj.Object, "status", "readyReplicas")
+		parts = append(parts, fmt.Sprintf("ready %d/%d", readyReplicas, replicas))
+	}
+
+	var conditions []string
+	list, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, c := range list {
+		if condition, ok := c.(map[string]interface{}); ok {
+			conditions = append(conditions, fmt.Sprintf("%v=%v", condition["type"], condition["status"]))
+		}
+	}
+	if len(conditions) > 0 {
+		parts = append(parts, strings.Join(conditions, " "))
+	}
+	if len(parts) == 0 {
+		return "no status"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// statusSeverity makes a status that reads like a failure an error
+func statusSeverity(status string) throwing.Severity {
+	for _, bad := range []string{"BackOff", "Err", "Fail", "OOMKilled", "Evicted"} {
+		if strings.Contains(status, bad) {
+			return throwing.SeverityError
+		}
+	}
+	return throwing.SeverityInfo
+}
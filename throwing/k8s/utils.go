@@ -6,65 +6,89 @@ import (
 	"os/exec"
 	"strings"
 
-	"github.com/gdamore/tcell"
 	"github.com/rancher/axe/throwing"
 	"github.com/rancher/axe/throwing/datafeeder"
 	"github.com/rancher/axe/throwing/types"
 	"github.com/rancher/norman/pkg/kv"
-	"github.com/rivo/tview"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// getNamespaceAndName reads the selected object, tables listing one namespace have no NAMESPACE column to read it from
 func getNamespaceAndName(t *throwing.TableView) (string, string) {
 	table := t.GetTable()
-	namespaced := false
-	if strings.Contains(table.GetCell(0, 0).Text, "NAMESPACE") {
-		namespaced = true
+	row, _ := table.GetSelection()
+
+	nameCol := columnIndex(t, "NAME")
+	if nameCol < 0 {
+		nameCol = 0
 	}
+	name := table.GetCell(row, nameCol).Text
+	if col := columnIndex(t, "NAMESPACE"); col >= 0 {
+		return table.GetCell(row, col).Text, name
+	}
+	return listedNamespace(t.GetResourceKind()), name
+}
 
-	row, _ := table.GetSelection()
-	var namespace, name string
-	if namespaced {
-		namespace, name = table.GetCell(row, 0).Text, table.GetCell(row, 1).Text
-	} else {
-		name = table.GetCell(row, 0).Text
+// selectedColumn reads the column with the given header from the selected row
+func selectedColumn(t *throwing.TableView, header string) string {
+	col := columnIndex(t, header)
+	if col < 0 {
+		return ""
 	}
-	return namespace, name
+	row, _ := t.GetTable().GetSelection()
+	return t.GetTable().GetCell(row, col).Text
 }
 
 func get(t *throwing.TableView) {
+	out, err := kubectlOutput(t, "get", "-o", "yaml")
+	if err != nil {
+		t.Notify(err.Error(), throwing.SeverityError)
+		return
+	}
+	recordRevision(selectedRef(t), out)
+	_, name := getNamespaceAndName(t)
+	showObject(t, fmt.Sprintf("%s - (%s)", t.GetResourceKind(), name), out)
+}
+
+func describe(t *throwing.TableView) {
+	out, err := kubectlOutput(t, "describe")
+	if err != nil {
+		t.Notify(err.Error(), throwing.SeverityError)
+		return
+	}
+	_, name := getNamespaceAndName(t)
+	showText(t, "describe", fmt.Sprintf("describe %s - (%s)", t.GetResourceKind(), name), out, languageYAML)
+}
+
+// kubectlOutput runs a kubectl verb against the selected object and returns its stdout
+func kubectlOutput(t *throwing.TableView, verb string, flags ...string) (string, error) {
+	namespace, name := getNamespaceAndName(t)
+	return kubectlObject(verb, t.GetResourceKind(), namespace, name, flags...)
+}
+
+func kubectlObject(verb, kind, namespace, name string, flags ...string) (string, error) {
 	out := &strings.Builder{}
 	errB := &strings.Builder{}
 
-	var args []string
-	namespace, name := getNamespaceAndName(t)
+	args := []string{verb, kind, name}
 	if namespace != "" {
-		args = []string{"get", t.GetResourceKind(), "-n", namespace, name, "-o", "yaml"}
-	} else {
-		args = []string{"get", t.GetResourceKind(), name, "-o", "yaml"}
+		args = append(args, "-n", namespace)
 	}
-	cmd := exec.Command("kubectl", args...)
+	cmd := kubectl(append(args, flags...)...)
 	cmd.Stdout, cmd.Stderr = out, errB
 	if err := cmd.Run(); err != nil {
-		t.UpdateStatus(errB.String(), true)
-		return
-	}
-
-	box := tview.NewTextView()
-	box.SetDynamicColors(true).SetBackgroundColor(tcell.ColorBlack)
-	box.SetText(out.String())
-	box.SetDoneFunc(func(key tcell.Key) {
-		if key == tcell.KeyEscape {
-			t.SwitchToRootPage()
+		if errB.Len() > 0 {
+			return "", fmt.Errorf("%s", strings.TrimSpace(errB.String()))
 		}
-	})
-
-	newpage := tview.NewPages().AddPage("get", box, true, true)
-	t.SwitchPage(t.GetCurrentPage(), newpage)
+		return "", err
+	}
+	return out.String(), nil
 }
 
 func edit(t *throwing.TableView) {
 	namespace, name := getNamespaceAndName(t)
+	if !canI(t, "patch", t.GetResourceKind(), "", namespace) {
+		return
+	}
 	errb := &strings.Builder{}
 	var args []string
 	if namespace != "" {
@@ -72,13 +96,13 @@ func edit(t *throwing.TableView) {
 	} else {
 		args = []string{"edit", t.GetResourceKind(), name}
 	}
-	cmd := exec.Command("kubectl", args...)
+	cmd := kubectl(args...)
 	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, errb
 
 	t.GetApplication().Suspend(func() {
 		clearScreen()
 		if err := cmd.Run(); err != nil {
-			t.UpdateStatus(errb.String(), true)
+			t.Notify(errb.String(), throwing.SeverityError)
 		}
 		return
 	})
@@ -88,18 +112,36 @@ func execute(t *throwing.TableView) {
 	if t.GetResourceKind() != "pods" {
 		return
 	}
-
 	namespace, name := getNamespaceAndName(t)
-	errb := &strings.Builder{}
+	podExec(t, namespace, name, "")
+}
+
+// podExec opens a shell in the pod, in its default container when container is empty
+func podExec(t *throwing.TableView, namespace, name, container string) {
+	if !canI(t, "create", "pods", "exec", namespace) {
+		return
+	}
 	shellArgs := []string{"/bin/sh", "-c", "TERM=xterm-256color; export TERM; [ -x /bin/bash ] && ([ -x /usr/bin/script ] && /usr/bin/script -q -c /bin/bash /dev/null || exec /bin/bash) || exec /bin/sh"}
-	args := append([]string{"exec", "-it", "-n", namespace, name, "--"}, shellArgs...)
-	cmd := exec.Command("kubectl", args...)
+	args := []string{"exec", "-it", "-n", namespace, name}
+	if container != "" {
+		args = append(args, "-c", container)
+	}
+	args = append(append(args, "--"), shellArgs...)
+	interactive(t, "exec", name, kubectl(args...))
+}
+
+// interactive hands the terminal to cmd until it exits, or opens it in tmux when action is set up for that
+func interactive(t *throwing.TableView, action, title string, cmd *exec.Cmd) {
+	if inTmux(t, action, title, cmd) {
+		return
+	}
+	errb := &strings.Builder{}
 	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, errb
 
 	t.GetApplication().Suspend(func() {
 		clearScreen()
 		if err := cmd.Run(); err != nil {
-			t.UpdateStatus(errb.String(), true)
+			t.Notify(errb.String(), throwing.SeverityError)
 		}
 		return
 	})
@@ -109,76 +151,38 @@ func logs(t *throwing.TableView) {
 	if t.GetResourceKind() != "pods" {
 		return
 	}
-
-	errB := &strings.Builder{}
-	var args []string
 	namespace, name := getNamespaceAndName(t)
-	args = []string{"logs", "-f", "-n", namespace, name, "--all-containers"}
-	cmd := exec.Command("kubectl", args...)
-	cmd.Stderr = errB
-
-	logbox := tview.NewTextView()
-	{
-		logbox.SetTitle(fmt.Sprintf("logs - (%s)", name))
-		logbox.SetBorder(true)
-		logbox.SetTitleColor(tcell.ColorPurple)
-		logbox.SetDynamicColors(true)
-		logbox.SetBackgroundColor(tcell.ColorBlack)
-		logbox.SetChangedFunc(func() {
-			logbox.ScrollToEnd()
-			t.GetApplication().Draw()
-		})
-		logbox.SetDoneFunc(func(key tcell.Key) {
-			if key == tcell.KeyEscape {
-				cmd.Process.Kill()
-			}
-		})
-	}
-
-	cmd.Stdout = tview.ANSIWriter(logbox)
-	go func() {
-		if err := cmd.Run(); err != nil {
-			return
-		}
-	}()
-
-	newpage := tview.NewPages().AddPage("logs", logbox, true, true)
-	t.SwitchPage(t.GetCurrentPage(), newpage)
+	podLogs(t, namespace, name, "")
 }
 
 func clearScreen() {
 	fmt.Print("\033[H\033[2J")
 }
 
-func delete(t *throwing.TableView) {
+func deleteResource(t *throwing.TableView) {
 	namespace, name := getNamespaceAndName(t)
-	modal := tview.NewModal().
-		SetText(fmt.Sprintf("Do you want to delete %s %s?", t.GetResourceKind(), name)).
-		AddButtons([]string{"delete", "Cancel"}).
-		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
-			if buttonLabel == "delete" {
-				var args []string
-				if namespace != "" {
-					args = []string{"delete", t.GetResourceKind(), "-n", namespace, name}
-				} else {
-					args = []string{"delete", t.GetResourceKind(), name}
-				}
-				cmd := exec.Command("kubectl", args...)
-				errB := &strings.Builder{}
-				cmd.Stderr = errB
-				go func() {
-					if err := cmd.Run(); err != nil {
-						t.UpdateStatus(errB.String(), true)
-						return
-					}
-				}()
-				t.Refresh()
-				t.SwitchToRootPage()
-			} else if buttonLabel == "Cancel" {
-				t.BackPage()
+	if !canI(t, "delete", t.GetResourceKind(), "", namespace) {
+		return
+	}
+	t.Confirm(fmt.Sprintf("Do you want to delete %s %s?", t.GetResourceKind(), name), "delete", func() {
+		var args []string
+		if namespace != "" {
+			args = []string{"delete", t.GetResourceKind(), "-n", namespace, name}
+		} else {
+			args = []string{"delete", t.GetResourceKind(), name}
+		}
+		cmd := kubectl(args...)
+		errB := &strings.Builder{}
+		cmd.Stderr = errB
+		t.Go(func() {
+			if err := cmd.Run(); err != nil {
+				t.Notify(errB.String(), throwing.SeverityError)
+				return
 			}
+			t.Notify(fmt.Sprintf("%s %s deleted", t.GetResourceKind(), name), throwing.SeverityInfo)
+			publishChange(t, throwing.EventDeleted, t.GetResourceKind(), namespace, name)
 		})
-	t.InsertDialog("delete", t.GetCurrentPrimitive(), modal)
+	})
 }
 
 func resourceView(t *throwing.TableView) error {
@@ -192,38 +196,42 @@ func viewResource(t *throwing.TableView) {
 	kind := table.GetCell(row, 0).Text
 	groupVersion := table.GetCell(row, 1).Text
 
-	var apiResource metav1.APIResource
 	group, version := kv.Split(groupVersion, "/")
 	if version == "" {
 		version = group
 		group = ""
 	}
-	apiResource.Group = group
-	apiResource.Version = version
-	apiResource.Name = kind
-
-	withGroup := kind
-	if group != "" {
-		withGroup += "." + group
-	}
-	rkind := types.ResourceKind{
-		Title: withGroup,
-		Kind:  withGroup,
-	}
 
 	w := wrapper{
-		group:   apiResource.Group,
-		version: apiResource.Version,
-		name:    apiResource.Name,
+		group:   group,
+		version: version,
+		name:    kind,
 	}
+	openResourceTable(t, w, w.kind())
+}
 
-	feeder := datafeeder.NewDataFeeder(w.refreshResource)
+// openResourceTable switches to the table of the resource described by w
+func openResourceTable(t *throwing.TableView, w wrapper, title string) {
+	openTable(t, types.ResourceKind{
+		Title: title,
+		Kind:  w.kind(),
+	}, datafeeder.NewDataFeeder(refresherForKind(w)).SetRowColor(rowColorForKind(w.kind())).SetAge(ageForKind(w.kind())))
+}
 
+/*
+openTable switches to the nested table of rkind.
+The table is cached per kind, opening it again swaps in the new feeder so a filtered view doesn't stick around.
+*/
+func openTable(t *throwing.TableView, rkind types.ResourceKind, feeder datafeeder.DataSource) {
 	newtable := t.GetNestedTable(rkind.Kind)
 	if newtable == nil {
-		newtable = t.NewNestTableView(rkind, feeder, nil, nil, itemEventHandler)
+		newtable = t.NewNestTableView(rkind, feeder, actionsForKind(rkind.Kind), nil, itemEventHandler)
+		t.SetTableView(rkind.Kind, newtable)
+	} else {
+		t.UpdateFeeder(rkind.Kind, feeder)
+		newtable.RefreshManual()
 	}
-	t.SetTableView(rkind.Kind, newtable)
+	newtable.GetTable().SetTitle(rkind.Title)
 
 	t.SwitchPage(rkind.Kind, newtable)
 }
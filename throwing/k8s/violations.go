@@ -0,0 +1,211 @@
+package k8s
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gdamore/tcell"
+	"github.com/rancher/axe/throwing/datafeeder"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+)
+
+const (
+	violationsKind        = "violations"
+	gatekeeperConstraints = "constraints.gatekeeper.sh"
+	policyReportGroup     = "wgpolicyk8s.io"
+)
+
+type violation struct {
+	namespace, name, kind string
+	engine, policy        string
+	action, message       string
+}
+
+// policyResources are the listable resources of group, e.g. every constraint kind of Gatekeeper
+func policyResources(d discovery.DiscoveryInterface, group string) []schema.GroupVersionResource {
+	lists, _ := d.ServerPreferredResources()
+	var resources []schema.GroupVersionResource
+	for _, list := range lists {
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil || gv.Group != group {
+			continue
+		}
+		for _, r := range list.APIResources {
+			if !strings.Contains(r.Name, "/") {
+				resources = append(resources, gv.WithResource(r.Name))
+			}
+		}
+	}
+	return resources
+}
+
+/*
+violatingKind turns the apiVersion and Kind a policy engine reports into the kind tables are keyed by,
+so Enter can open the offending object. The lower cased Kind is kept when discovery doesn't know it.
+*/
+func violatingKind(d discovery.DiscoveryInterface, apiVersion, kind string) string {
+	typed := kind
+	if gv, err := schema.ParseGroupVersion(apiVersion); err == nil && gv.Group != "" {
+		typed += "." + gv.Group
+	}
+	gvr, err := resolveKind(d, typed)
+	if err != nil {
+		return strings.ToLower(kind)
+	}
+	return kindOf(gvr)
+}
+
+// gatekeeperViolations reads the violations the audit wrote to the status of every constraint
+func gatekeeperViolations(d discovery.DiscoveryInterface, client dynamic.Interface) ([]violation, error) {
+	var violations []violation
+	for _, gvr := range policyResources(d, gatekeeperConstraints) {
+		list, err := client.Resource(gvr).List(metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for _, constraint := range list.Items {
+			policy := constraint.GetKind() + "/" + constraint.GetName()
+			enforcement, _, _ := unstructured.NestedString(constraint.Object, "spec", "enforcementAction")
+			found, _, _ := unstructured.NestedSlice(constraint.Object, "status", "violations")
+			for _, v := range found {
+				v, ok := v.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				action := fmt.Sprint(v["enforcementAction"])
+				if v["enforcementAction"] == nil {
+					action = enforcement
+				}
+				group, _ := v["group"].(string)
+				version, _ := v["version"].(string)
+				kind, _ := v["kind"].(string)
+				namespace, _ := v["namespace"].(string)
+				name, _ := v["name"].(string)
+				message, _ := v["message"].(string)
+				violations = append(violations, violation{
+					namespace: namespace,
+					name:      name,
+					kind:      violatingKind(d, schema.GroupVersion{Group: group, Version: version}.String(), kind),
+					engine:    "gatekeeper",
+					policy:    policy,
+					action:    action,
+					message:   message,
+				})
+			}
+		}
+	}
+	return violations, nil
+}
+
+// policyReportViolations reads the failed results of the policy reports Kyverno and other engines write
+func policyReportViolations(d discovery.DiscoveryInterface, client dynamic.Interface) ([]violation, error) {
+	var violations []violation
+	for _, gvr := range policyResources(d, policyReportGroup) {
+		list, err := client.Resource(gvr).List(metav1.ListOptions{})
+		if err != nil {
+			return nil, err
+		}
+		for _, report := range list.Items {
+			results, _, _ := unstructured.NestedSlice(report.Object, "results")
+			for _, r := range results {
+				r, ok := r.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				result, _ := r["result"].(string)
+				if result == "" {
+					// the first version of the API called it status
+					result, _ = r["status"].(string)
+				}
+				switch result {
+				case "fail", "warn", "error":
+				default:
+					continue
+				}
+				policy, _ := r["policy"].(string)
+				if rule, _ := r["rule"].(string); rule != "" {
+					policy += "/" + rule
+				}
+				engine, _ := r["source"].(string)
+				if engine == "" {
+					engine = "policyreport"
+				}
+				message, _ := r["message"].(string)
+				resources, _, _ := unstructured.NestedSlice(r, "resources")
+				for _, res := range resources {
+					res, ok := res.(map[string]interface{})
+					if !ok {
+						continue
+					}
+					apiVersion, _ := res["apiVersion"].(string)
+					kind, _ := res["kind"].(string)
+					namespace, _ := res["namespace"].(string)
+					name, _ := res["name"].(string)
+					violations = append(violations, violation{
+						namespace: namespace,
+						name:      name,
+						kind:      violatingKind(d, apiVersion, kind),
+						engine:    strings.ToLower(engine),
+						policy:    policy,
+						action:    result,
+						message:   message,
+					})
+				}
+			}
+		}
+	}
+	return violations, nil
+}
+
+/*
+RefreshViolations lists what Gatekeeper constraints and policy reports (Kyverno writes them) find wrong
+with objects already in the cluster, by namespace and name. Engines that aren't installed are skipped.
+*/
+func RefreshViolations(b *bytes.Buffer) error {
+	d, err := cachedDiscovery()
+	if err != nil {
+		return err
+	}
+	client, err := newDynamicClient()
+	if err != nil {
+		return err
+	}
+	violations, err := gatekeeperViolations(d, client)
+	if err != nil {
+		return err
+	}
+	reported, err := policyReportViolations(d, client)
+	if err != nil {
+		return err
+	}
+	violations = append(violations, reported...)
+
+	sort.SliceStable(violations, func(i, j int) bool {
+		a, b := violations[i], violations[j]
+		if a.namespace != b.namespace {
+			return a.namespace < b.namespace
+		}
+		return a.name < b.name
+	})
+	var rows [][]string
+	for _, v := range violations {
+		rows = append(rows, []string{v.namespace, v.name, v.kind, v.engine, v.policy, v.action, v.message})
+	}
+	writeTable(b, []string{"NAMESPACE", "NAME", "KIND", "ENGINE", "POLICY", "ACTION", "MESSAGE"}, rows)
+	return nil
+}
+
+// violationRowColor makes what was or would be denied red and warnings or dry runs yellow
+func violationRowColor(header, row datafeeder.Row) tcell.Color {
+	switch columnOrEmpty(header, row, "ACTION") {
+	case "deny", "fail", "error":
+		return tcell.ColorRed
+	}
+	return tcell.ColorYellow
+}
@@ -0,0 +1,116 @@
+package k8s
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell"
+	"github.com/ghodss/yaml"
+	"github.com/rancher/axe/throwing"
+	"github.com/rivo/tview"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// conditionsSummary renders status.conditions one per line, false conditions in red
+func conditionsSummary(obj *unstructured.Unstructured) string {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	if len(conditions) == 0 {
+		return "[gray]no status conditions"
+	}
+	b := &strings.Builder{}
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		color := "green"
+		if fmt.Sprint(condition["status"]) != "True" {
+			color = "red"
+		}
+		fmt.Fprintf(b, "[%s]%-20v %-8v[white] %v %v\n", color, condition["type"], condition["status"], stringOrEmpty(condition["reason"]), stringOrEmpty(condition["message"]))
+	}
+	return b.String()
+}
+
+func stringOrEmpty(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprint(v)
+}
+
+/*
+watchResource shows the selected object and re-renders it on every change.
+
+The watch uses a metadata.name field selector so only this object is streamed, it stops once the page is left.
+*/
+func watchResource(t *throwing.TableView) {
+	namespace, name := getNamespaceAndName(t)
+	gvr, err := groupVersionResource(t.GetClientSet(), t.GetResourceKind())
+	if err != nil {
+		t.Notify(err.Error(), throwing.SeverityError)
+		return
+	}
+	client, err := newDynamicClient()
+	if err != nil {
+		t.Notify(err.Error(), throwing.SeverityError)
+		return
+	}
+	w, err := client.Resource(gvr).Namespace(namespace).Watch(metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("metadata.name", name).String(),
+	})
+	if err != nil {
+		t.Notify(err.Error(), throwing.SeverityError)
+		return
+	}
+
+	summary := tview.NewTextView()
+	summary.SetBorder(true)
+	summary.SetTitle("conditions")
+	summary.SetTitleColor(tcell.ColorPurple)
+	summary.SetDynamicColors(true).SetBackgroundColor(tcell.ColorBlack)
+
+	box := tview.NewTextView()
+	box.SetBorder(true)
+	box.SetTitle(fmt.Sprintf("watch - (%s %s)", t.GetResourceKind(), name))
+	box.SetTitleColor(tcell.ColorPurple)
+	box.SetDynamicColors(true).SetBackgroundColor(tcell.ColorBlack)
+	t.Go(func() {
+		defer stopWith(t.Context(), w.Stop)()
+		for event := range w.ResultChan() {
+			obj, ok := event.Object.(*unstructured.Unstructured)
+			if !ok {
+				if event.Type == watch.Error {
+					t.Notify(fmt.Sprintf("watch of %s failed: %v", name, event.Object), throwing.SeverityError)
+				}
+				continue
+			}
+			out, err := yaml.Marshal(obj.Object)
+			if err != nil {
+				t.Notify(err.Error(), throwing.SeverityError)
+				continue
+			}
+			recordRevision(objectRef{kind: t.GetResourceKind(), namespace: namespace, name: name}, string(out))
+			text, conditions := highlight(string(out), languageYAML), conditionsSummary(obj)
+			title := fmt.Sprintf("watch - (%s %s) %s at %s, resourceVersion %s", t.GetResourceKind(), name, strings.ToLower(string(event.Type)), time.Now().Format("15:04:05"), obj.GetResourceVersion())
+			t.GetApplication().QueueUpdateDraw(func() {
+				summary.SetText(conditions)
+				box.SetTitle(title)
+				box.SetText(text)
+			})
+		}
+	})
+
+	page := tview.NewFlex().SetDirection(tview.FlexRow)
+	page.AddItem(summary, 7, 1, false)
+	page.AddItem(box, 0, 1, true)
+
+	newpage := tview.NewPages().AddPage("watch", page, true, true)
+	t.SwitchPage(t.GetCurrentPage(), newpage)
+	t.OnLeave(newpage, w.Stop)
+	t.GetApplication().SetFocus(box)
+}
@@ -0,0 +1,160 @@
+package k8s
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell"
+	"github.com/rancher/axe/throwing"
+	"github.com/rivo/tview"
+	"k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	iconOK      = "✔"
+	iconPending = "◔"
+	iconFailed  = "✖"
+)
+
+// xrayRef is attached to tree nodes that can be opened in their table
+type xrayRef struct {
+	w               wrapper
+	namespace, name string
+}
+
+func ownedBy(refs []metav1.OwnerReference, uid k8stypes.UID) bool {
+	for _, ref := range refs {
+		if ref.UID == uid {
+			return true
+		}
+	}
+	return false
+}
+
+func statusNode(icon, text string, color tcell.Color) *tview.TreeNode {
+	return tview.NewTreeNode(fmt.Sprintf("%s %s", icon, text)).SetColor(color)
+}
+
+func podIcon(pod v1.Pod) (string, tcell.Color) {
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.State.Waiting != nil && badWaitingReasons[cs.State.Waiting.Reason] {
+			return iconFailed, tcell.ColorRed
+		}
+	}
+	switch pod.Status.Phase {
+	case v1.PodRunning, v1.PodSucceeded:
+		return iconOK, tcell.ColorGreen
+	case v1.PodPending:
+		return iconPending, tcell.ColorYellow
+	}
+	return iconFailed, tcell.ColorRed
+}
+
+func containerIcon(cs v1.ContainerStatus) (string, string, tcell.Color) {
+	switch {
+	case cs.State.Running != nil && cs.Ready:
+		return iconOK, "running", tcell.ColorGreen
+	case cs.State.Running != nil:
+		return iconPending, "running, not ready", tcell.ColorYellow
+	case cs.State.Waiting != nil && badWaitingReasons[cs.State.Waiting.Reason]:
+		return iconFailed, cs.State.Waiting.Reason, tcell.ColorRed
+	case cs.State.Waiting != nil:
+		return iconPending, cs.State.Waiting.Reason, tcell.ColorYellow
+	case cs.State.Terminated != nil && cs.State.Terminated.ExitCode == 0:
+		return iconOK, cs.State.Terminated.Reason, tcell.ColorGreen
+	case cs.State.Terminated != nil:
+		return iconFailed, cs.State.Terminated.Reason, tcell.ColorRed
+	}
+	return iconPending, "unknown", tcell.ColorYellow
+}
+
+/*
+xrayDeployment shows Deployment -> ReplicaSet -> Pod -> Container as a tree.
+
+Enter: expand or collapse
+o: open the selected object in its table
+*/
+func xrayDeployment(t *throwing.TableView) {
+	namespace, name := getNamespaceAndName(t)
+	client := t.GetClientSet()
+
+	deployment, err := client.AppsV1().Deployments(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		t.Notify(err.Error(), throwing.SeverityError)
+		return
+	}
+	replicaSets, err := client.AppsV1().ReplicaSets(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		t.Notify(err.Error(), throwing.SeverityError)
+		return
+	}
+	pods, err := client.CoreV1().Pods(namespace).List(metav1.ListOptions{})
+	if err != nil {
+		t.Notify(err.Error(), throwing.SeverityError)
+		return
+	}
+
+	icon, color := iconOK, tcell.ColorGreen
+	if deployment.Status.AvailableReplicas < deployment.Status.Replicas {
+		icon, color = iconPending, tcell.ColorYellow
+	}
+	root := statusNode(icon, fmt.Sprintf("Deployment %s (%d/%d available)", deployment.Name, deployment.Status.AvailableReplicas, deployment.Status.Replicas), color)
+	root.SetReference(xrayRef{w: builtinKinds["deployments.apps"], namespace: namespace, name: deployment.Name})
+
+	for _, rs := range replicaSets.Items {
+		if !ownedBy(rs.OwnerReferences, deployment.UID) {
+			continue
+		}
+		icon, color := iconOK, tcell.ColorGreen
+		if rs.Status.ReadyReplicas < rs.Status.Replicas {
+			icon, color = iconPending, tcell.ColorYellow
+		}
+		rsNode := statusNode(icon, fmt.Sprintf("ReplicaSet %s (%d/%d ready)", rs.Name, rs.Status.ReadyReplicas, rs.Status.Replicas), color)
+		rsNode.SetReference(xrayRef{w: builtinKinds["replicasets.apps"], namespace: namespace, name: rs.Name})
+		// old replicasets scaled to zero are kept collapsed
+		rsNode.SetExpanded(rs.Status.Replicas > 0)
+		root.AddChild(rsNode)
+
+		for _, pod := range pods.Items {
+			if !ownedBy(pod.OwnerReferences, rs.UID) {
+				continue
+			}
+			icon, color := podIcon(pod)
+			podNode := statusNode(icon, fmt.Sprintf("Pod %s (%s)", pod.Name, pod.Status.Phase), color)
+			podNode.SetReference(xrayRef{w: builtinKinds["pods"], namespace: namespace, name: pod.Name})
+			rsNode.AddChild(podNode)
+
+			for _, cs := range pod.Status.ContainerStatuses {
+				icon, state, color := containerIcon(cs)
+				podNode.AddChild(statusNode(icon, fmt.Sprintf("Container %s (%s, %d restarts)", cs.Name, state, cs.RestartCount), color))
+			}
+		}
+	}
+
+	tree := tview.NewTreeView().SetRoot(root).SetCurrentNode(root)
+	tree.SetBorder(true)
+	tree.SetTitle(fmt.Sprintf("x-ray - (%s/%s) [o] open", namespace, name))
+	tree.SetTitleColor(tcell.ColorPurple)
+	tree.SetBackgroundColor(tcell.ColorBlack)
+	tree.SetSelectedFunc(func(node *tview.TreeNode) {
+		node.SetExpanded(!node.IsExpanded())
+	})
+	tree.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Rune() != 'o' {
+			return event
+		}
+		ref, ok := tree.GetCurrentNode().GetReference().(xrayRef)
+		if !ok {
+			return event
+		}
+		openResourceTable(t, ref.w, ref.w.kind())
+		if nt := t.GetNestedTable(ref.w.kind()); nt != nil {
+			nt.SelectRow(ref.namespace, ref.name)
+		}
+		return nil
+	})
+
+	newpage := tview.NewPages().AddPage("xray", tree, true, true)
+	t.SwitchPage(t.GetCurrentPage(), newpage)
+}
@@ -0,0 +1,93 @@
+package throwing
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gdamore/tcell"
+	"github.com/rivo/tview"
+)
+
+const (
+	KeymapDefault = "default"
+	KeymapVim     = "vim"
+
+	// vimChordTimeout is how long a lone g waits for a second one before it's handed to the blade
+	vimChordTimeout = 500 * time.Millisecond
+)
+
+// keymap translates keys for a profile before the table and the blade see them
+type keymap struct {
+	profile  string
+	pendingG bool
+	chord    int
+}
+
+// SetKeymap selects the key profile of the tables, one of KeymapDefault and KeymapVim
+func (app *AppView) SetKeymap(profile string) error {
+	switch profile {
+	case "", KeymapDefault:
+		app.keymap.profile = KeymapDefault
+	case KeymapVim:
+		app.keymap.profile = KeymapVim
+	default:
+		return fmt.Errorf("unknown keymap %q, expected %s or %s", profile, KeymapDefault, KeymapVim)
+	}
+	return nil
+}
+
+/*
+wrap layers the keymap over the handler of a table.
+
+The vim profile moves with j/k, jumps with gg/G and pages with Ctrl+d/Ctrl+u, these keys never reach the blade.
+A single g is still given to the blade once the chord times out.
+*/
+func (k *keymap) wrap(t *TableView, handler func(event *tcell.EventKey) *tcell.EventKey) func(event *tcell.EventKey) *tcell.EventKey {
+	return func(event *tcell.EventKey) *tcell.EventKey {
+		if k.profile != KeymapVim {
+			return handler(event)
+		}
+		if event.Key() == tcell.KeyRune && event.Rune() == 'g' {
+			if k.pendingG {
+				k.pendingG = false
+				return tcell.NewEventKey(tcell.KeyHome, 0, tcell.ModNone)
+			}
+			k.pendingG = true
+			k.chord++
+			chord := k.chord
+			time.AfterFunc(vimChordTimeout, func() {
+				t.app.QueueUpdateDraw(func() {
+					if !k.pendingG || chord != k.chord {
+						return
+					}
+					k.pendingG = false
+					// what the handler leaves goes to the table, as it would have from the input capture
+					if event := handler(event); event != nil {
+						t.Table.InputHandler()(event, func(p tview.Primitive) {
+							t.app.SetFocus(p)
+						})
+					}
+				})
+			})
+			return nil
+		}
+		k.pendingG = false
+
+		switch event.Key() {
+		case tcell.KeyCtrlD:
+			return tcell.NewEventKey(tcell.KeyPgDn, 0, tcell.ModNone)
+		case tcell.KeyCtrlU:
+			return tcell.NewEventKey(tcell.KeyPgUp, 0, tcell.ModNone)
+		case tcell.KeyRune:
+			switch event.Rune() {
+			case 'j':
+				return tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone)
+			case 'k':
+				return tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone)
+			case 'G':
+				return tcell.NewEventKey(tcell.KeyEnd, 0, tcell.ModNone)
+			}
+		}
+		return handler(event)
+	}
+}
@@ -0,0 +1,198 @@
+package throwing
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gdamore/tcell"
+)
+
+// names of the global keys, the bindings of the app can be changed with SetKeyBindings
+const (
+	KeyBack        = "back"
+	KeyFinder      = "finder"
+	KeySplit       = "split"
+	KeyNextTab     = "next-tab"
+	KeyPreviousTab = "previous-tab"
+	KeyNewTab      = "new-tab"
+	KeyCloseTab    = "close-tab"
+	KeyNextPage    = "next-page"
+	KeyPrevPage    = "previous-page"
+	KeyHistory     = "history"
+
+	// pageKeyPrefix binds a key to the page of a kind, e.g. page.helm
+	pageKeyPrefix = "page."
+)
+
+var defaultGlobalKeys = map[string]string{
+	KeyBack:        "esc,q",
+	KeyFinder:      "ctrl+p",
+	KeySplit:       "ctrl+s",
+	KeyNextTab:     "tab",
+	KeyPreviousTab: "shift+tab",
+	KeyNewTab:      "ctrl+t",
+	KeyCloseTab:    "ctrl+w",
+	KeyNextPage:    "]",
+	KeyPrevPage:    "[",
+	KeyHistory:     "ctrl+o",
+}
+
+var keyAliases = map[string]string{
+	"escape":    "esc",
+	"shift-tab": "backtab",
+	"return":    "enter",
+}
+
+// Key is one key of a binding, Rune is only set for KeyRune
+type Key struct {
+	Key  tcell.Key
+	Rune rune
+}
+
+// Keys are the alternative keys of a binding, written comma separated like "esc,q"
+type Keys []Key
+
+/*
+ParseKeys parses a binding such as "ctrl+p", "shift+tab", "esc,q" or a single character.
+Special keys use the names of tcell.KeyNames, case and + or - as separator don't matter.
+*/
+func ParseKeys(spec string) (Keys, error) {
+	var keys Keys
+	for _, part := range strings.Split(spec, ",") {
+		k, err := parseKey(strings.TrimSpace(part))
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
+
+func parseKey(spec string) (Key, error) {
+	if utf8.RuneCountInString(spec) == 1 {
+		r, _ := utf8.DecodeRuneInString(spec)
+		return Key{Key: tcell.KeyRune, Rune: r}, nil
+	}
+	name := strings.ToLower(strings.Replace(spec, "+", "-", -1))
+	if alias, ok := keyAliases[name]; ok {
+		name = alias
+	}
+	if name == "space" {
+		return Key{Key: tcell.KeyRune, Rune: ' '}, nil
+	}
+	for k, n := range tcell.KeyNames {
+		if strings.ToLower(n) == name {
+			return Key{Key: k}, nil
+		}
+	}
+	return Key{}, fmt.Errorf("unknown key %q", spec)
+}
+
+func (k Key) String() string {
+	if k.Key == tcell.KeyRune {
+		return string(k.Rune)
+	}
+	if name, ok := tcell.KeyNames[k.Key]; ok {
+		return name
+	}
+	return fmt.Sprintf("Key[%d]", k.Key)
+}
+
+func (k Keys) String() string {
+	var names []string
+	for _, key := range k {
+		names = append(names, key.String())
+	}
+	return strings.Join(names, ",")
+}
+
+// Matches reports whether event is one of the keys
+func (k Keys) Matches(event *tcell.EventKey) bool {
+	for _, key := range k {
+		if event.Key() != key.Key {
+			continue
+		}
+		if key.Key != tcell.KeyRune || event.Rune() == key.Rune {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+KeyConflicts reports every key bound to more than one name.
+Blades call it with the global keys plus the actions of a table to find shortcuts that shadow each other.
+*/
+func KeyConflicts(bindings map[string]Keys) []string {
+	users := map[Key][]string{}
+	for name, keys := range bindings {
+		for _, k := range keys {
+			users[k] = append(users[k], name)
+		}
+	}
+	var conflicts []string
+	for k, names := range users {
+		if len(names) < 2 {
+			continue
+		}
+		sort.Strings(names)
+		conflicts = append(conflicts, fmt.Sprintf("%s is bound to %s", k, strings.Join(names, " and ")))
+	}
+	sort.Strings(conflicts)
+	return conflicts
+}
+
+// initKeys binds the default global keys and one key per page from the drawer's PageNav
+func (app *AppView) initKeys() {
+	app.keys = map[string]Keys{}
+	for name, spec := range defaultGlobalKeys {
+		app.keys[name], _ = ParseKeys(spec)
+	}
+	for r, kind := range app.PageNav {
+		app.keys[pageKeyPrefix+kind] = Keys{{Key: tcell.KeyRune, Rune: r}}
+	}
+}
+
+/*
+SetKeyBindings overrides global keys by name, page keys are set with page.<kind>.
+It returns the conflicts between the resulting global keys, an unknown name or key is an error.
+*/
+func (app *AppView) SetKeyBindings(bindings map[string]string) ([]string, error) {
+	for name, spec := range bindings {
+		if _, ok := app.keys[name]; !ok {
+			return nil, fmt.Errorf("unknown key binding %q", name)
+		}
+		keys, err := ParseKeys(spec)
+		if err != nil {
+			return nil, fmt.Errorf("key binding %s: %v", name, err)
+		}
+		app.keys[name] = keys
+	}
+	return KeyConflicts(app.keys), nil
+}
+
+// GlobalKeys returns a copy of the global and page bindings
+func (app *AppView) GlobalKeys() map[string]Keys {
+	keys := map[string]Keys{}
+	for name, k := range app.keys {
+		keys[name] = k
+	}
+	return keys
+}
+
+// pageKey returns the key bound to the page of kind, empty when it has none
+func (app *AppView) pageKey(kind string) string {
+	return app.keys[pageKeyPrefix+kind].String()
+}
+
+// pageFor returns the page bound to event, if any
+func (app *AppView) pageFor(event *tcell.EventKey) (string, bool) {
+	for name, keys := range app.keys {
+		if strings.HasPrefix(name, pageKeyPrefix) && keys.Matches(event) {
+			return strings.TrimPrefix(name, pageKeyPrefix), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,67 @@
+package throwing
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gdamore/tcell"
+	"github.com/rivo/tview"
+)
+
+const (
+	latencySamples = 10
+	latencyWidth   = 12
+	// refreshes slower than these turn the indicator yellow and red
+	latencySlow     = 500 * time.Millisecond
+	latencyCritical = 2 * time.Second
+)
+
+// latencyView shows the average round-trip of the last data feeder refreshes in the status bar
+type latencyView struct {
+	*tview.TextView
+	*AppView
+	lock    sync.Mutex
+	samples []time.Duration
+	next    int
+}
+
+func (l *latencyView) init() {
+	l.TextView.
+		SetTextAlign(tview.AlignRight).
+		SetWrap(false).SetBackgroundColor(tcell.ColorBlack)
+}
+
+func (l *latencyView) record(d time.Duration) {
+	l.lock.Lock()
+	if len(l.samples) < latencySamples {
+		l.samples = append(l.samples, d)
+	} else {
+		l.samples[l.next] = d
+	}
+	l.next = (l.next + 1) % latencySamples
+	var total time.Duration
+	for _, s := range l.samples {
+		total += s
+	}
+	average := total / time.Duration(len(l.samples))
+	l.lock.Unlock()
+
+	color := tcell.ColorGreen
+	switch {
+	case average >= latencyCritical:
+		color = tcell.ColorRed
+	case average >= latencySlow:
+		color = tcell.ColorYellow
+	}
+	text := fmt.Sprintf("api %v ", average.Round(time.Millisecond))
+	l.Application.QueueUpdateDraw(func() {
+		l.TextView.SetTextColor(color)
+		l.TextView.SetText(text)
+	})
+}
+
+// RecordLatency adds a round-trip to the API server to the latency shown in the status bar
+func (app *AppView) RecordLatency(d time.Duration) {
+	app.latencyView.record(d)
+}
@@ -0,0 +1,43 @@
+package throwing
+
+import (
+	"strings"
+
+	"github.com/rancher/axe/throwing/datafeeder"
+)
+
+// Markdown renders the rows of t as a GitHub flavored Markdown table, under a heading with its title
+func (t *TableView) Markdown() string {
+	return t.shared().markdown()
+}
+
+func (s *sharedTable) markdown() string {
+	b := &strings.Builder{}
+	b.WriteString("**" + markdownCell(s.Title) + "**")
+	if !s.Time.IsZero() {
+		b.WriteString(" as of " + s.Time.Format("2006-01-02 15:04:05 MST"))
+	}
+	b.WriteString("\n\n")
+
+	markdownRow(b, s.Header)
+	separator := make(datafeeder.Row, len(s.Header))
+	for i := range separator {
+		separator[i] = "---"
+	}
+	markdownRow(b, separator)
+	for _, row := range s.Rows {
+		markdownRow(b, row)
+	}
+	return b.String()
+}
+
+func markdownRow(b *strings.Builder, row datafeeder.Row) {
+	b.WriteString("|")
+	for _, cell := range row {
+		b.WriteString(" " + markdownCell(cell) + " |")
+	}
+	b.WriteString("\n")
+}
+
+// markdownCell keeps a value on one line and its pipes from ending the cell
+var markdownCell = strings.NewReplacer("|", `\|`, "\r\n", "<br>", "\n", "<br>").Replace
@@ -0,0 +1,161 @@
+package throwing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell"
+	"github.com/rivo/tview"
+)
+
+const (
+	defaultNotifyTimeout = 3 * time.Second
+	notifyQueueSize      = 32
+)
+
+type Severity int
+
+const (
+	SeverityInfo Severity = iota
+	SeverityProgress
+	SeverityWarning
+	SeverityError
+)
+
+func (s Severity) String() string {
+	switch s {
+	case SeverityProgress:
+		return "progress"
+	case SeverityWarning:
+		return "warning"
+	case SeverityError:
+		return "error"
+	}
+	return "info"
+}
+
+func (s Severity) color() tcell.Color {
+	switch s {
+	case SeverityProgress:
+		return tcell.ColorYellow
+	case SeverityWarning:
+		return tcell.ColorOrange
+	case SeverityError:
+		return tcell.ColorRed
+	}
+	return tcell.ColorGreen
+}
+
+/*
+Notification is a toast rendered in the status bar.
+Timeout of zero falls back to the app default.
+*/
+type Notification struct {
+	Message  string
+	Severity Severity
+	Timeout  time.Duration
+}
+
+type notifyView struct {
+	*tview.TextView
+	*AppView
+	queue   chan Notification
+	timeout time.Duration
+}
+
+func (n *notifyView) init() {
+	n.TextView.
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignRight).
+		SetWrap(false).SetBackgroundColor(tcell.ColorBlack)
+	if n.timeout == 0 {
+		n.timeout = defaultNotifyTimeout
+	}
+	n.AppView.Go(func() { n.run(n.AppView.context) })
+}
+
+// push never blocks the caller, if the queue is full the oldest toast is dropped
+func (n *notifyView) push(note Notification) {
+	for {
+		select {
+		case n.queue <- note:
+			return
+		default:
+			select {
+			case <-n.queue:
+			default:
+			}
+		}
+	}
+}
+
+func (n *notifyView) run(ctx context.Context) {
+	for {
+		select {
+		case note := <-n.queue:
+			n.show(note)
+			timeout := note.Timeout
+			if timeout == 0 {
+				timeout = n.timeout
+			}
+			select {
+			case <-time.After(timeout):
+			case <-ctx.Done():
+				return
+			}
+			// keep the last toast on screen if another one is already waiting
+			if len(n.queue) == 0 {
+				n.clear()
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (n *notifyView) show(note Notification) {
+	n.Application.QueueUpdateDraw(func() {
+		n.TextView.Clear()
+		n.TextView.SetTextColor(note.Severity.color())
+		n.TextView.SetText(tview.Escape(strings.Join(strings.Fields(note.Message), " ")))
+	})
+}
+
+func (n *notifyView) clear() {
+	n.Application.QueueUpdateDraw(func() {
+		n.TextView.Clear()
+	})
+}
+
+// Notify queues a toast with the given severity and the default timeout
+func (app *AppView) Notify(message string, severity Severity) {
+	app.NotifyWithTimeout(message, severity, 0)
+}
+
+func (app *AppView) NotifyWithTimeout(message string, severity Severity, timeout time.Duration) {
+	app.notifyView.push(Notification{
+		Message:  message,
+		Severity: severity,
+		Timeout:  timeout,
+	})
+}
+
+// Bell rings the terminal bell, it's written between draws so it can't end up inside an escape sequence
+func (app *AppView) Bell() {
+	app.QueueUpdate(func() {
+		fmt.Fprint(os.Stdout, "\a")
+	})
+}
+
+// Bell rings the terminal bell of the app of t
+func (t *TableView) Bell() {
+	t.app.Bell()
+}
+
+// SetNotifyTimeout configures how long a toast stays in the status bar
+func (app *AppView) SetNotifyTimeout(timeout time.Duration) {
+	app.notifyView.timeout = timeout
+}
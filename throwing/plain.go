@@ -0,0 +1,115 @@
+package throwing
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell"
+	"github.com/rivo/tview"
+)
+
+/*
+SetPlain turns on the accessible mode, it has to be called before Init.
+The screen loses its colors and box drawing, the selection is shown reversed instead,
+and a line above the status bar reads out the selected row as "row 2 of 5: NAME web, STATUS Running"
+with the terminal cursor on it, so screen readers and dumb terminals can follow the table.
+*/
+func (app *AppView) SetPlain(plain bool) {
+	app.plain = plain
+}
+
+func (app *AppView) initPlain() {
+	setBorders(' ', ' ', ' ', ' ', ' ', ' ')
+	app.readout = tview.NewTextView().SetWrap(false)
+}
+
+// setBorders replaces the border runes of every primitive, the T pieces and crosses take the corner
+func setBorders(horizontal, vertical, corner, horizontalFocus, verticalFocus, cornerFocus rune) {
+	b := &tview.Borders
+	b.Horizontal, b.Vertical = horizontal, vertical
+	b.TopLeft, b.TopRight, b.BottomLeft, b.BottomRight = corner, corner, corner, corner
+	b.LeftT, b.RightT, b.TopT, b.BottomT, b.Cross = corner, corner, corner, corner, corner
+	b.HorizontalFocus, b.VerticalFocus = horizontalFocus, verticalFocus
+	b.TopLeftFocus, b.TopRightFocus, b.BottomLeftFocus, b.BottomRightFocus = cornerFocus, cornerFocus, cornerFocus, cornerFocus
+}
+
+/*
+afterDraw rewrites what the primitives drew for the plain and ASCII modes.
+Plain strips it down to text, anything drawn on a background is reversed to stay visible.
+ASCII replaces what the terminal can't show, also in the parts tview draws itself.
+The result is what gets recorded.
+*/
+func (app *AppView) afterDraw(screen tcell.Screen) {
+	defer app.recorder.frame(screen)
+	if !app.plain && !app.ascii {
+		return
+	}
+	width, height := screen.Size()
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			main, combining, style, _ := screen.GetContent(x, y)
+			if app.plain {
+				_, bg, attr := style.Decompose()
+				plain := tcell.StyleDefault.Underline(attr&tcell.AttrUnderline != 0)
+				if bg != tcell.ColorDefault && bg != tcell.ColorBlack || attr&tcell.AttrReverse != 0 {
+					plain = plain.Reverse(true)
+				}
+				main, style = plainRune(main), plain
+			}
+			if app.ascii {
+				main = asciiRune(main)
+			}
+			screen.SetContent(x, y, main, combining, style)
+		}
+	}
+	// input fields place the cursor themselves, GetFocus would wait for the lock held while drawing
+	if t := app.currentPrimitive; app.plain && t != nil && t.HasFocus() {
+		x, y, _, _ := app.readout.GetRect()
+		screen.ShowCursor(x, y)
+	}
+}
+
+// plainRune replaces box drawing and block characters, which screen readers spell out
+func plainRune(r rune) rune {
+	switch {
+	case r >= '─' && r <= '╿':
+		return ' '
+	case r == '█':
+		return '#'
+	case r >= '▀' && r <= '▟':
+		return '.'
+	}
+	return r
+}
+
+// readOut puts the selected row of t on the readout line in plain mode
+func (app *AppView) readOut(t *TableView) {
+	if !app.plain || t != app.currentPrimitive {
+		return
+	}
+	rows := t.Table.GetRowCount() - 1
+	// the header row is selected for a moment while the table fills
+	row, _ := t.Table.GetSelection()
+	if row < 1 {
+		row = 1
+	}
+	var header, values []string
+	for col := 0; col < t.Table.GetColumnCount(); col++ {
+		header = append(header, strings.TrimPrefix(t.Table.GetCell(0, col).Text, "[white]"))
+		values = append(values, t.Table.GetCell(row, col).Text)
+	}
+	app.readout.SetText(linearRow(row, rows, header, values))
+}
+
+func linearRow(row, rows int, header, values []string) string {
+	if rows <= 0 {
+		return "no rows"
+	}
+	var fields []string
+	for i, h := range header {
+		if i < len(values) && strings.TrimSpace(values[i]) != "" {
+			fields = append(fields, fmt.Sprintf("%s %s", strings.TrimSpace(h), strings.TrimSpace(values[i])))
+		}
+	}
+	return fmt.Sprintf("row %d of %d: %s", row, rows, strings.Join(fields, ", "))
+}
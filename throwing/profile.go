@@ -0,0 +1,198 @@
+package throwing
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+/*
+SetProfileAddress serves pprof and the refresh costs of every view on addr once Init runs, e.g. "localhost:6060".
+Without a host it listens on 127.0.0.1 only. /debug/pprof/ has the usual profiles,
+/debug/views the refresh and draw times and allocations per view together with the runtime memory stats.
+*/
+func (app *AppView) SetProfileAddress(addr string) {
+	app.profileAddress = addr
+	if addr != "" {
+		app.profile = &profiler{views: map[string]*viewCosts{}}
+	}
+}
+
+// profiler adds up what refreshing and drawing cost per view, it's nil unless profiling is on
+type profiler struct {
+	lock  sync.Mutex
+	views map[string]*viewCosts
+}
+
+type viewCosts struct {
+	refreshes, failures            int
+	refreshLast, refreshMax, fetch time.Duration
+	drawLast, drawMax, draw        time.Duration
+	allocated                      uint64
+	rows                           int
+}
+
+// allocated is the total allocated so far, reading it stops the world for a moment so it's only done while profiling
+func (p *profiler) allocated() uint64 {
+	if p == nil {
+		return 0
+	}
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	return stats.TotalAlloc
+}
+
+// refreshed records a refresh of kind, allocatedBefore is what allocated returned when it started
+func (p *profiler) refreshed(kind string, fetch, draw time.Duration, allocatedBefore uint64, rows int) {
+	if p == nil {
+		return
+	}
+	allocated := p.allocated() - allocatedBefore
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	v := p.view(kind)
+	v.refreshes++
+	v.refreshLast, v.drawLast = fetch, draw
+	v.fetch += fetch
+	v.draw += draw
+	if fetch > v.refreshMax {
+		v.refreshMax = fetch
+	}
+	if draw > v.drawMax {
+		v.drawMax = draw
+	}
+	v.allocated += allocated
+	v.rows = rows
+}
+
+func (p *profiler) failed(kind string) {
+	if p == nil {
+		return
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.view(kind).failures++
+}
+
+// view has to be called with p locked
+func (p *profiler) view(kind string) *viewCosts {
+	v, ok := p.views[kind]
+	if !ok {
+		v = &viewCosts{}
+		p.views[kind] = v
+	}
+	return v
+}
+
+// ViewProfile is what /debug/views reports for one view, times in milliseconds.
+// Allocations are counted for the whole process while the view refreshed, other work running at the same time adds to them.
+type ViewProfile struct {
+	Kind          string  `json:"kind"`
+	Refreshes     int     `json:"refreshes"`
+	Failures      int     `json:"failures"`
+	Rows          int     `json:"rows"`
+	RefreshLastMs float64 `json:"refreshLastMs"`
+	RefreshMeanMs float64 `json:"refreshMeanMs"`
+	RefreshMaxMs  float64 `json:"refreshMaxMs"`
+	DrawLastMs    float64 `json:"drawLastMs"`
+	DrawMeanMs    float64 `json:"drawMeanMs"`
+	DrawMaxMs     float64 `json:"drawMaxMs"`
+	AllocatedMean uint64  `json:"allocatedBytesMean"`
+}
+
+// RuntimeProfile is the memory and goroutine count of the process for /debug/views
+type RuntimeProfile struct {
+	Goroutines   int    `json:"goroutines"`
+	HeapAlloc    uint64 `json:"heapAllocBytes"`
+	TotalAlloc   uint64 `json:"totalAllocBytes"`
+	Sys          uint64 `json:"sysBytes"`
+	Mallocs      uint64 `json:"mallocs"`
+	NumGC        uint32 `json:"numGC"`
+	PauseTotalMs uint64 `json:"gcPauseTotalMs"`
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+func (p *profiler) report() []ViewProfile {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	var out []ViewProfile
+	for kind, v := range p.views {
+		vp := ViewProfile{
+			Kind:          kind,
+			Refreshes:     v.refreshes,
+			Failures:      v.failures,
+			Rows:          v.rows,
+			RefreshLastMs: milliseconds(v.refreshLast),
+			RefreshMaxMs:  milliseconds(v.refreshMax),
+			DrawLastMs:    milliseconds(v.drawLast),
+			DrawMaxMs:     milliseconds(v.drawMax),
+		}
+		if v.refreshes > 0 {
+			vp.RefreshMeanMs = milliseconds(v.fetch / time.Duration(v.refreshes))
+			vp.DrawMeanMs = milliseconds(v.draw / time.Duration(v.refreshes))
+			vp.AllocatedMean = v.allocated / uint64(v.refreshes)
+		}
+		out = append(out, vp)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Kind < out[j].Kind })
+	return out
+}
+
+func (app *AppView) serveProfile() error {
+	addr := app.profileAddress
+	if host, port, err := net.SplitHostPort(addr); err == nil && host == "" {
+		addr = net.JoinHostPort("127.0.0.1", port)
+	}
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.HandleFunc("/debug/views", app.serveViewProfiles)
+	go func() {
+		if err := http.Serve(listener, mux); err != nil {
+			logrus.Errorf("serving pprof on %s: %v", addr, err)
+		}
+	}()
+	logrus.Infof("pprof on http://%s/debug/pprof/", listener.Addr())
+	return nil
+}
+
+func (app *AppView) serveViewProfiles(w http.ResponseWriter, r *http.Request) {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	report := struct {
+		Runtime RuntimeProfile `json:"runtime"`
+		Views   []ViewProfile  `json:"views"`
+	}{
+		Runtime: RuntimeProfile{
+			Goroutines:   runtime.NumGoroutine(),
+			HeapAlloc:    stats.HeapAlloc,
+			TotalAlloc:   stats.TotalAlloc,
+			Sys:          stats.Sys,
+			Mallocs:      stats.Mallocs,
+			NumGC:        stats.NumGC,
+			PauseTotalMs: stats.PauseTotalNs / uint64(time.Millisecond),
+		},
+		Views: app.profile.report(),
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		logrus.Debugf("serving %s: %v", r.URL.Path, err)
+	}
+}
@@ -1,9 +1,15 @@
 package throwing
 
 import (
+	"fmt"
+
+	"github.com/rancher/axe/throwing/types"
 	"github.com/rivo/tview"
 )
 
+// maxPageHistory bounds the pages BackPage can go back through, the oldest are dropped first
+const maxPageHistory = 50
+
 type PageTrack struct {
 	PageName string
 	tview.Primitive
@@ -14,8 +20,15 @@ type PrimitiveQueue struct {
 	items []PageTrack
 }
 
+// Enqueue records t, showing the same page again doesn't add a second entry
 func (p *PrimitiveQueue) Enqueue(t PageTrack) {
+	if n := len(p.items); n > 0 && p.items[n-1].PageName == t.PageName && p.items[n-1].Primitive == t.Primitive {
+		return
+	}
 	p.items = append(p.items, t)
+	if len(p.items) > maxPageHistory {
+		p.items = p.items[len(p.items)-maxPageHistory:]
+	}
 }
 
 func (p *PrimitiveQueue) Dequeue() PageTrack {
@@ -43,3 +56,44 @@ func (p *PrimitiveQueue) last(dequeue bool) PageTrack {
 func (p *PrimitiveQueue) Last() PageTrack {
 	return p.last(false)
 }
+
+// History returns the recorded pages, the most recent last
+func (p *PrimitiveQueue) History() []PageTrack {
+	return append([]PageTrack(nil), p.items...)
+}
+
+// truncate drops the pages recorded after the i-th
+func (p *PrimitiveQueue) truncate(i int) {
+	if i >= 0 && i < len(p.items) {
+		p.items = p.items[:i+1]
+	}
+}
+
+func trackActions(track PageTrack) []types.Action {
+	if t, ok := track.Primitive.(*TableView); ok {
+		return t.actions
+	}
+	return nil
+}
+
+// showHistory lists the pages BackPage would go through, picking one goes back to it
+func (app *AppView) showHistory() {
+	history := app.drawQueue.History()
+	if len(history) == 0 {
+		return
+	}
+	var options []string
+	for i := len(history) - 1; i >= 0; i-- {
+		name := history[i].PageName
+		if _, ok := history[i].Primitive.(*TableView); !ok {
+			name += " (dialog)"
+		}
+		options = append(options, fmt.Sprintf("%d. %s", len(history)-i, name))
+	}
+	app.Choose("History", options, func(index int, _ string) {
+		i := len(history) - 1 - index
+		app.drawQueue.truncate(i)
+		track := history[i]
+		app.SwitchPage(track.PageName, track.Primitive, trackActions(track))
+	})
+}
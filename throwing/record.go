@@ -0,0 +1,221 @@
+package throwing
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gdamore/tcell"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// maxReplayPause shortens the idle stretches of a recording on replay
+	maxReplayPause = 2 * time.Second
+	// maxRecordLine is the longest line Replay reads, a frame of a large terminal with colors fits easily
+	maxRecordLine = 16 << 20
+)
+
+/*
+SetRecordFile records the session to path once Init runs: every frame that changed as text with
+ANSI colors and every key pressed, one JSON object per line with the milliseconds since the start.
+Replay plays it back. Secrets shown on the screen end up in the file like anything else.
+*/
+func (app *AppView) SetRecordFile(path string) {
+	app.recordFile = path
+}
+
+// recordEvent is one line of a recording, either a frame or a key
+type recordEvent struct {
+	At     int64  `json:"at"`
+	Width  int    `json:"width,omitempty"`
+	Height int    `json:"height,omitempty"`
+	Frame  string `json:"frame,omitempty"`
+	Key    string `json:"key,omitempty"`
+}
+
+// recorder writes a recording, it's nil unless recording is on
+type recorder struct {
+	lock    sync.Mutex
+	file    *os.File
+	encoder *json.Encoder
+	started time.Time
+	last    string
+}
+
+func newRecorder(path string) (*recorder, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return &recorder{file: file, encoder: json.NewEncoder(file), started: time.Now()}, nil
+}
+
+// frame records what's on screen unless it's what the last frame showed, it's called after every draw
+func (r *recorder) frame(screen tcell.Screen) {
+	if r == nil {
+		return
+	}
+	width, height := screen.Size()
+	frame := ansiFrame(screen, width, height)
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if frame == r.last {
+		return
+	}
+	r.last = frame
+	r.write(recordEvent{Width: width, Height: height, Frame: frame})
+}
+
+func (r *recorder) key(event *tcell.EventKey) {
+	if r == nil {
+		return
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.write(recordEvent{Key: event.Name()})
+}
+
+// write has to be called with r locked, the recording stops at the first error
+func (r *recorder) write(e recordEvent) {
+	if r.encoder == nil {
+		return
+	}
+	e.At = int64(time.Since(r.started) / time.Millisecond)
+	if err := r.encoder.Encode(e); err != nil {
+		logrus.Errorf("recording to %s stopped: %v", r.file.Name(), err)
+		r.encoder = nil
+	}
+}
+
+func (r *recorder) close() {
+	if r == nil {
+		return
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.encoder = nil
+	if err := r.file.Close(); err != nil {
+		logrus.Errorf("closing recording %s: %v", r.file.Name(), err)
+	}
+}
+
+// ansiFrame reads the screen into lines of text with SGR sequences wherever the style changes
+func ansiFrame(screen tcell.Screen, width, height int) string {
+	b := &strings.Builder{}
+	for y := 0; y < height; y++ {
+		current := tcell.StyleDefault
+		b.WriteString("\x1b[0m")
+		for x := 0; x < width; x++ {
+			main, combining, style, cellWidth := screen.GetContent(x, y)
+			if style != current {
+				b.WriteString(sgr(style))
+				current = style
+			}
+			if main == 0 {
+				main = ' '
+			}
+			b.WriteRune(main)
+			for _, r := range combining {
+				b.WriteRune(r)
+			}
+			// the cell after a wide rune is drawn over by it
+			if cellWidth > 1 {
+				x++
+			}
+		}
+		b.WriteString("\x1b[0m")
+		if y < height-1 {
+			b.WriteString("\r\n")
+		}
+	}
+	return b.String()
+}
+
+func sgr(style tcell.Style) string {
+	fg, bg, attr := style.Decompose()
+	codes := []string{"0"}
+	for _, a := range []struct {
+		mask tcell.AttrMask
+		code string
+	}{{tcell.AttrBold, "1"}, {tcell.AttrDim, "2"}, {tcell.AttrUnderline, "4"}, {tcell.AttrBlink, "5"}, {tcell.AttrReverse, "7"}} {
+		if attr&a.mask != 0 {
+			codes = append(codes, a.code)
+		}
+	}
+	if c := sgrColor(fg); c != "" {
+		codes = append(codes, "38;"+c)
+	}
+	if c := sgrColor(bg); c != "" {
+		codes = append(codes, "48;"+c)
+	}
+	return "\x1b[" + strings.Join(codes, ";") + "m"
+}
+
+func sgrColor(c tcell.Color) string {
+	switch {
+	case c == tcell.ColorDefault:
+		return ""
+	case c&tcell.ColorIsRGB != 0:
+		r, g, b := c.RGB()
+		return fmt.Sprintf("2;%d;%d;%d", r, g, b)
+	case c >= 0 && c < 256:
+		return fmt.Sprintf("5;%d", c)
+	}
+	return ""
+}
+
+/*
+Replay plays a recording made with SetRecordFile to a terminal on w, speed 2 plays it twice as fast.
+Pauses are cut to a couple of seconds, the last key pressed is shown in the top right corner.
+It stops early when ctx is done.
+*/
+func Replay(ctx context.Context, r io.Reader, w io.Writer, speed float64) error {
+	if speed <= 0 {
+		speed = 1
+	}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxRecordLine)
+	fmt.Fprint(w, "\x1b[?25l")
+	defer fmt.Fprint(w, "\x1b[0m\x1b[?25h\r\n")
+
+	var (
+		at          int64
+		width       int
+		frame, keys string
+	)
+	for scanner.Scan() {
+		var e recordEvent
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return fmt.Errorf("reading recording: %v", err)
+		}
+		pause := time.Duration(float64(time.Duration(e.At-at)*time.Millisecond) / speed)
+		if pause > maxReplayPause {
+			pause = maxReplayPause
+		}
+		at = e.At
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(pause):
+		}
+
+		if e.Frame != "" {
+			frame, width = e.Frame, e.Width
+		}
+		if e.Key != "" {
+			keys = fmt.Sprintf(" %s ", e.Key)
+		}
+		fmt.Fprint(w, "\x1b[H\x1b[2J", frame)
+		if keys != "" && width > len(keys) {
+			fmt.Fprintf(w, "\x1b[1;%dH\x1b[0;7m%s\x1b[0m", width-len(keys)+1, keys)
+		}
+	}
+	return scanner.Err()
+}
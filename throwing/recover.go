@@ -0,0 +1,67 @@
+package throwing
+
+import (
+	"fmt"
+	"runtime/debug"
+	"sync/atomic"
+
+	"github.com/sirupsen/logrus"
+)
+
+/*
+Go runs f in a goroutine that can't take the terminal down with it, and that Run waits for on the way out.
+A panic in the event loop is caught by tview, which restores the terminal, one in a goroutine of its own isn't,
+the process dies with the terminal still in raw mode. Goroutines started by the app and its blades use Go or defer Recover.
+*/
+func (app *AppView) Go(f func()) {
+	app.workers.Add(1)
+	go func() {
+		defer app.workers.Done()
+		defer app.Recover()
+		f()
+	}()
+}
+
+/*
+Recover has to be deferred at the top of a goroutine. On a panic it writes the stack trace to the log,
+stops the application so the terminal is restored and has Run return the panic as its error.
+Before Run there's no screen to restore and the panic carries on.
+*/
+func (app *AppView) Recover() {
+	// recover only stops a panic when it's called by the deferred function itself
+	if r := recover(); r != nil {
+		app.recovered(r)
+	}
+}
+
+func (app *AppView) recovered(r interface{}) {
+	logrus.Errorf("panic: %v\n%s", r, debug.Stack())
+	if atomic.LoadInt32(&app.running) == 0 {
+		panic(r)
+	}
+	app.lock.Lock()
+	if app.crash == nil {
+		app.crash = fmt.Errorf("panic: %v, the stack trace is in the log", r)
+	}
+	app.lock.Unlock()
+	app.Stop()
+}
+
+// crashed is the panic a goroutine stopped the app with, nil when it wasn't stopped by one
+func (app *AppView) crashed() error {
+	app.lock.Lock()
+	defer app.lock.Unlock()
+	return app.crash
+}
+
+// Go runs f in a goroutine of the app of t, see AppView.Go
+func (t *TableView) Go(f func()) {
+	t.app.Go(f)
+}
+
+// Recover stops the app of t on a panic, see AppView.Recover
+func (t *TableView) Recover() {
+	if r := recover(); r != nil {
+		t.app.recovered(r)
+	}
+}
@@ -0,0 +1,190 @@
+package throwing
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"unicode/utf8"
+
+	"github.com/rancher/axe/throwing/datafeeder"
+	"github.com/rivo/tview"
+)
+
+const (
+	matchTag    = "[black:yellow]"
+	matchTagEnd = "[-:-]"
+)
+
+// searchHistoryLimit is how many searches are remembered per view
+const searchHistoryLimit = 50
+
+/*
+ShowSearch focuses the search input, the table is filtered and the matches highlighted on every keystroke.
+Up and Down go through the earlier searches of the view.
+*/
+func (t *TableView) ShowSearch() {
+	atomic.StoreInt32(&t.searching, 1)
+	walkHistory(t.app.searchView.InputField, t.app.searchHistory(t.resourceKind.Kind))
+	t.app.SetFocus(t.app.searchView.InputField)
+}
+
+// searchHistory returns the searches entered on the views of kind, it's only used from the UI goroutine
+func (app *AppView) searchHistory(kind string) *InputHistory {
+	if app.searchHistories == nil {
+		app.searchHistories = map[string]*InputHistory{}
+	}
+	h, ok := app.searchHistories[kind]
+	if !ok {
+		h = NewInputHistory(searchHistoryLimit)
+		app.searchHistories[kind] = h
+	}
+	return h
+}
+
+// NextMatch selects the next row matching the last search of the view without filtering the table
+func (t *TableView) NextMatch() {
+	t.selectMatch(1)
+}
+
+// PreviousMatch selects the previous row matching the last search of the view
+func (t *TableView) PreviousMatch() {
+	t.selectMatch(-1)
+}
+
+// selectMatch walks the rows from the selection in direction step, wrapping around at either end
+func (t *TableView) selectMatch(step int) {
+	entries := t.app.searchHistory(t.resourceKind.Kind).Entries()
+	if len(entries) == 0 {
+		t.Notify("nothing searched yet, / searches", SeverityInfo)
+		return
+	}
+	search := entries[len(entries)-1]
+	filter := parseFilter(search)
+
+	var header datafeeder.Row
+	for col := 0; col < t.Table.GetColumnCount(); col++ {
+		header = append(header, strings.TrimPrefix(t.Table.GetCell(0, col).Text, "[white]"))
+	}
+	nameCol := headerColumn(header, "NAME")
+	if nameCol < 0 {
+		nameCol = 0
+	}
+	rows := t.Table.GetRowCount() - 1
+	selected, _ := t.Table.GetSelection()
+	for i := 1; i <= rows; i++ {
+		// rows are 1 based below the header
+		r := (selected-1+i*step)%rows + 1
+		if r < 1 {
+			r += rows
+		}
+		var row datafeeder.Row
+		for col := range header {
+			row = append(row, t.Table.GetCell(r, col).Text)
+		}
+		if filter.matches(header, row, nameCol) {
+			t.Table.Select(r, 0)
+			return
+		}
+	}
+	t.Notify(fmt.Sprintf("no row matches %q", search), SeverityInfo)
+}
+
+/*
+liveSearch redraws t filtered by search from the rows it already has, without refreshing the source.
+It's called for every keystroke, the draws run one after the other under the table lock and a draw
+that a newer keystroke overtook is skipped.
+*/
+func (t *TableView) liveSearch(search string) {
+	if atomic.LoadInt32(&t.searching) == 0 {
+		return
+	}
+	seq := atomic.AddInt32(&t.searchSeq, 1)
+	t.app.Go(func() {
+		t.lock.Lock()
+		defer t.lock.Unlock()
+		if seq != atomic.LoadInt32(&t.searchSeq) {
+			return
+		}
+		t.search = search
+		t.draw()
+	})
+}
+
+// endSearch takes the highlights off, search stays applied to the next draw like a search entered the old way
+func (t *TableView) endSearch(search string) {
+	atomic.StoreInt32(&t.searching, 0)
+	atomic.AddInt32(&t.searchSeq, 1)
+	t.app.Go(func() {
+		t.lock.Lock()
+		defer t.lock.Unlock()
+		t.search = search
+		t.draw()
+	})
+}
+
+// highlight marks the parts of value in column col that the terms of f matched, in tview color tags
+func (f rowFilter) highlight(header datafeeder.Row, nameCol, col int, value string) string {
+	var spans [][2]int
+	for _, term := range f {
+		if term.value == "" || term.op == filterNot {
+			continue
+		}
+		termCol := nameCol
+		if term.column != "" {
+			termCol = headerColumn(header, term.column)
+		}
+		if termCol != col {
+			continue
+		}
+		spans = append(spans, foldedSpans(value, term.value)...)
+	}
+	if len(spans) == 0 {
+		return value
+	}
+
+	marked := make([]bool, len(value))
+	for _, s := range spans {
+		for i := s[0]; i < s[1]; i++ {
+			marked[i] = true
+		}
+	}
+	var b strings.Builder
+	for start := 0; start < len(value); {
+		end := start
+		for end < len(value) && marked[end] == marked[start] {
+			end++
+		}
+		// brackets in a cell would be read as tags once the cell has tags of its own
+		part := tview.Escape(value[start:end])
+		if marked[start] {
+			part = matchTag + part + matchTagEnd
+		}
+		b.WriteString(part)
+		start = end
+	}
+	return b.String()
+}
+
+/*
+foldedSpans returns the byte offsets in value of the runs of runes equal to want, case aside.
+It compares value itself rune by rune, lower casing it first can change the byte length of a rune like Ⱥ.
+*/
+func foldedSpans(value, want string) [][2]int {
+	runes := utf8.RuneCountInString(want)
+	var spans [][2]int
+	for start := 0; start < len(value); {
+		end := start
+		for n := 0; n < runes && end < len(value); n++ {
+			_, size := utf8.DecodeRuneInString(value[end:])
+			end += size
+		}
+		if strings.EqualFold(value[start:end], want) {
+			spans = append(spans, [2]int{start, end})
+			start = end
+			continue
+		}
+		_, size := utf8.DecodeRuneInString(value[start:])
+		start += size
+	}
+	return spans
+}
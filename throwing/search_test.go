@@ -0,0 +1,75 @@
+package throwing
+
+import (
+	"testing"
+
+	"github.com/rancher/axe/throwing/datafeeder"
+)
+
+func TestHighlight(t *testing.T) {
+	header := datafeeder.Row{"NAMESPACE", "NAME", "STATUS"}
+	tests := []struct {
+		name   string
+		search string
+		col    int
+		value  string
+		want   string
+	}{
+		{
+			name:   "ascii",
+			search: "web",
+			col:    1,
+			value:  "api-WEB-1",
+			want:   "api-" + matchTag + "WEB" + matchTagEnd + "-1",
+		},
+		{
+			name:   "longer when lower cased",
+			search: "web",
+			col:    1,
+			value:  "Ⱥpp-web",
+			want:   "Ⱥpp-" + matchTag + "web" + matchTagEnd,
+		},
+		{
+			name:   "non-ascii term",
+			search: "ⱥpp",
+			col:    1,
+			value:  "xȺPP-Ⱥpp",
+			want:   "x" + matchTag + "ȺPP" + matchTagEnd + "-" + matchTag + "Ⱥpp" + matchTagEnd,
+		},
+		{
+			name:   "shorter when lower cased",
+			search: "k",
+			col:    1,
+			value:  "Kelvin",
+			want:   matchTag + "K" + matchTagEnd + "elvin",
+		},
+		{
+			name:   "column term",
+			search: "status=größe",
+			col:    2,
+			value:  "GRÖßE",
+			want:   matchTag + "GRÖßE" + matchTagEnd,
+		},
+		{
+			name:   "other column",
+			search: "status=Ⱥ",
+			col:    1,
+			value:  "Ⱥ",
+			want:   "Ⱥ",
+		},
+		{
+			name:   "no match",
+			search: "db",
+			col:    1,
+			value:  "Ⱥ[web]",
+			want:   "Ⱥ[web]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseFilter(tt.search).highlight(header, 1, tt.col, tt.value); got != tt.want {
+				t.Errorf("highlight of %q by %q is %q, want %q", tt.value, tt.search, got, tt.want)
+			}
+		})
+	}
+}
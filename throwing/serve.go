@@ -0,0 +1,144 @@
+package throwing
+
+import (
+	"encoding/json"
+	"html/template"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/rancher/axe/throwing/datafeeder"
+	"github.com/sirupsen/logrus"
+)
+
+// serveRefresh is how often the shared HTML page reloads itself
+const serveRefresh = 5 * time.Second
+
+// sharedTable is the table on screen as served over HTTP
+type sharedTable struct {
+	Kind    string           `json:"kind"`
+	Title   string           `json:"title"`
+	Time    time.Time        `json:"time"`
+	Header  datafeeder.Row   `json:"header"`
+	Rows    []datafeeder.Row `json:"rows"`
+	Refresh int              `json:"-"`
+}
+
+var sharedPage = template.Must(template.New("table").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta http-equiv="refresh" content="{{.Refresh}}">
+<title>{{.Title}}</title>
+<style>
+body { background: #000; color: #faebd7; font-family: monospace; }
+h1 { color: #800080; font-size: 1.2em; }
+table { border-collapse: collapse; }
+th { color: #800080; text-align: left; }
+th, td { padding: 2px 12px 2px 0; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p>as of {{.Time.Format "15:04:05 MST"}}, reloads every {{.Refresh}}s</p>
+<table>
+<tr>{{range .Header}}<th>{{.}}</th>{{end}}</tr>
+{{range .Rows}}<tr>{{range .}}<td>{{.}}</td>{{end}}</tr>
+{{end}}</table>
+</body>
+</html>
+`))
+
+/*
+SetServeAddress shares the table on screen read-only over HTTP on addr, e.g. ":8080", once Init runs.
+/ is an HTML page reloading itself, /api/table the same rows as JSON and /table.md as Markdown. Anyone reaching addr sees them, there's no authentication.
+*/
+func (app *AppView) SetServeAddress(addr string) {
+	app.serveAddress = addr
+}
+
+func (app *AppView) serve() error {
+	listener, err := net.Listen("tcp", app.serveAddress)
+	if err != nil {
+		return err
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", app.serveHTML)
+	mux.HandleFunc("/api/table", app.serveJSON)
+	mux.HandleFunc("/table.md", app.serveMarkdown)
+	go func() {
+		if err := http.Serve(listener, mux); err != nil {
+			logrus.Errorf("serving %s: %v", app.serveAddress, err)
+		}
+	}()
+	logrus.Infof("sharing the current table on %s", listener.Addr())
+	return nil
+}
+
+// shared copies what the current table shows, nil before the first page is up
+func (app *AppView) shared() *sharedTable {
+	t := app.currentPrimitive
+	if t == nil {
+		return nil
+	}
+	return t.shared()
+}
+
+func (t *TableView) shared() *sharedTable {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	shared := &sharedTable{
+		Kind:    t.resourceKind.Kind,
+		Title:   t.resourceKind.Title,
+		Time:    t.lastRefresh,
+		Header:  t.dataSource.Header(),
+		Refresh: int(serveRefresh / time.Second),
+	}
+	for _, row := range t.dataSource.Data() {
+		if len(row) > 0 && row[0] != "" {
+			shared.Rows = append(shared.Rows, row)
+		}
+	}
+	return shared
+}
+
+func (app *AppView) serveHTML(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	shared := app.shared()
+	if shared == nil {
+		http.Error(w, "no table on screen yet", http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := sharedPage.Execute(w, shared); err != nil {
+		logrus.Debugf("serving %s: %v", r.URL.Path, err)
+	}
+}
+
+func (app *AppView) serveJSON(w http.ResponseWriter, r *http.Request) {
+	shared := app.shared()
+	if shared == nil {
+		http.Error(w, "no table on screen yet", http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(shared); err != nil {
+		logrus.Debugf("serving %s: %v", r.URL.Path, err)
+	}
+}
+
+func (app *AppView) serveMarkdown(w http.ResponseWriter, r *http.Request) {
+	shared := app.shared()
+	if shared == nil {
+		http.Error(w, "no table on screen yet", http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+	if _, err := io.WriteString(w, shared.markdown()); err != nil {
+		logrus.Debugf("serving %s: %v", r.URL.Path, err)
+	}
+}
@@ -0,0 +1,60 @@
+package throwing
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// shutdownTimeout is how long Run waits for the goroutines of the app after the UI stopped
+const shutdownTimeout = 5 * time.Second
+
+// Context is cancelled once the app stops, streams, watches and processes started by a blade end with it
+func (app *AppView) Context() context.Context {
+	return app.context
+}
+
+// stopOnSignal stops the app on SIGTERM and SIGHUP, the returned func stops listening
+func (app *AppView) stopOnSignal() func() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, syscall.SIGHUP)
+	done := make(chan struct{})
+	go func() {
+		select {
+		case s := <-signals:
+			logrus.Infof("stopping on %v", s)
+			app.Stop()
+		case <-done:
+		}
+	}()
+	return func() {
+		signal.Stop(signals)
+		close(done)
+	}
+}
+
+// shutdown cancels the context of the app and waits a while for the goroutines started with Go
+func (app *AppView) shutdown() {
+	if app.cancel != nil {
+		app.cancel()
+	}
+	done := make(chan struct{})
+	go func() {
+		app.workers.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(shutdownTimeout):
+		logrus.Warnf("goroutines still running %v after the app stopped", shutdownTimeout)
+	}
+}
+
+// Context is cancelled once the app of t stops
+func (t *TableView) Context() context.Context {
+	return t.app.context
+}
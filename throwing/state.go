@@ -0,0 +1,157 @@
+package throwing
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Selection is the selected cell of a table
+type Selection struct {
+	Row    int `json:"row"`
+	Column int `json:"column"`
+}
+
+/*
+State is what survives a restart: the page, the selection and the searches of every table and whatever the blade
+stored with SetStateValue. It's written on exit and read on launch when a state file is set.
+*/
+type State struct {
+	CurrentPage string               `json:"currentPage,omitempty"`
+	Selections  map[string]Selection `json:"selections,omitempty"`
+	Searches    map[string][]string  `json:"searches,omitempty"`
+	Values      map[string]string    `json:"values,omitempty"`
+}
+
+func loadState(path string) (*State, error) {
+	state := &State{}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return state, nil
+	} else if err != nil {
+		return state, err
+	}
+	return state, json.Unmarshal(data, state)
+}
+
+func (s *State) save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, data, 0600)
+}
+
+// SetStateFile loads the state saved in path, it has to be called before Init to restore the selections and searches
+func (app *AppView) SetStateFile(path string) {
+	app.stateFile = path
+	state, err := loadState(path)
+	if err != nil {
+		logrus.Warnf("failed to load state from %s: %v", path, err)
+	}
+	app.state = state
+	for kind, s := range state.Selections {
+		app.pageRows[kind] = position{row: s.Row, column: s.Column}
+	}
+	if len(state.Searches) > 0 {
+		app.searchHistories = map[string]*InputHistory{}
+	}
+	for kind, searches := range state.Searches {
+		app.searchHistories[kind] = NewInputHistory(searchHistoryLimit, searches...)
+	}
+}
+
+// StateValue returns a value a blade saved in an earlier session
+func (app *AppView) StateValue(key string) string {
+	if app.state == nil {
+		return ""
+	}
+	return app.state.Values[key]
+}
+
+func (app *AppView) SetStateValue(key, value string) {
+	if app.state == nil {
+		app.state = &State{}
+	}
+	if app.state.Values == nil {
+		app.state.Values = map[string]string{}
+	}
+	app.state.Values[key] = value
+}
+
+func (app *AppView) saveState() {
+	if app.stateFile == "" {
+		return
+	}
+	if app.state == nil {
+		app.state = &State{}
+	}
+	app.state.CurrentPage = app.currentPage
+	app.state.Selections = map[string]Selection{}
+	for kind, p := range app.pageRows {
+		app.state.Selections[kind] = Selection{Row: p.row, Column: p.column}
+	}
+	app.state.Searches = map[string][]string{}
+	for kind, h := range app.searchHistories {
+		if entries := h.Entries(); len(entries) > 0 {
+			app.state.Searches[kind] = entries
+		}
+	}
+	if err := app.state.save(app.stateFile); err != nil {
+		logrus.Warnf("failed to save state to %s: %v", app.stateFile, err)
+	}
+}
+
+/*
+Run runs the application and saves the session state once it stops, a panic in a goroutine comes back as the error.
+SIGTERM and SIGHUP stop it like quitting does, then the context of the app is cancelled and Run gives
+the goroutines started with Go a moment to wind down.
+*/
+func (app *AppView) Run() error {
+	defer app.recorder.close()
+	defer app.saveState()
+	defer app.shutdown()
+	stop := app.stopOnSignal()
+	defer stop()
+	atomic.StoreInt32(&app.running, 1)
+	defer atomic.StoreInt32(&app.running, 0)
+	if err := app.Application.Run(); err != nil {
+		return err
+	}
+	return app.crashed()
+}
+
+// restorePage returns to the page of the last session if it's one of the pages in the footer
+func (app *AppView) restorePage() {
+	if app.state == nil || app.state.CurrentPage == "" || app.state.CurrentPage == app.RootPage {
+		return
+	}
+	for _, kind := range app.PageNav {
+		if kind != app.state.CurrentPage {
+			continue
+		}
+		app.footerView.TextView.Highlight(kind).ScrollToHighlight()
+		if _, ok := app.tableViews[kind]; !ok {
+			app.tableViews[kind] = NewTableView(app, kind, app.Drawer)
+		}
+		app.SwitchPage(kind, app.tableViews[kind], app.tableViews[kind].actions)
+		return
+	}
+}
+
+// StateValue returns a value a blade saved in an earlier session
+func (t *TableView) StateValue(key string) string {
+	return t.app.StateValue(key)
+}
+
+// SetStateValue stores a value to be saved with the session state
+func (t *TableView) SetStateValue(key, value string) {
+	t.app.SetStateValue(key, value)
+}
@@ -0,0 +1,54 @@
+package throwing
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/rancher/axe/throwing/types"
+)
+
+func TestStateRoundTrip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "axe-state")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "state.json")
+
+	app := NewAppView(nil, types.Drawer{}, nil)
+	app.SetStateFile(path)
+	app.currentPage = "pods"
+	app.pageRows["pods"] = position{row: 3, column: 1}
+	app.searchHistory("pods").Add("web")
+	app.searchHistory("pods").Add("status!=Running")
+	app.SetStateValue("namespaceScopes", `{"pods":true}`)
+	app.saveState()
+
+	restored := NewAppView(nil, types.Drawer{}, nil)
+	restored.SetStateFile(path)
+	if restored.state.CurrentPage != "pods" {
+		t.Errorf("current page is %q, want pods", restored.state.CurrentPage)
+	}
+	if got, want := restored.pageRows["pods"], (position{row: 3, column: 1}); got != want {
+		t.Errorf("pods selection is %+v, want %+v", got, want)
+	}
+	if got, want := restored.searchHistory("pods").Entries(), []string{"web", "status!=Running"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("pods searches are %q, want %q", got, want)
+	}
+	if got := restored.StateValue("namespaceScopes"); got != `{"pods":true}` {
+		t.Errorf("namespace scopes are %q", got)
+	}
+}
+
+func TestLoadStateMissingFile(t *testing.T) {
+	state, err := loadState(filepath.Join(os.TempDir(), "axe-state-does-not-exist.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(state, &State{}) {
+		t.Errorf("state of a missing file is %+v, want an empty one", state)
+	}
+}
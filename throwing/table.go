@@ -4,20 +4,18 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/gdamore/tcell"
 	"github.com/rancher/axe/throwing/datafeeder"
 	"github.com/rancher/axe/throwing/types"
 	"github.com/rivo/tview"
+	"github.com/sirupsen/logrus"
 	"golang.org/x/net/context"
 	"k8s.io/client-go/kubernetes"
 )
 
-const (
-	errorDelayTime = 1
-)
-
 type TableView struct {
 	*tview.Table
 
@@ -28,12 +26,38 @@ type TableView struct {
 	data         []interface{}
 	dataSource   datafeeder.DataSource
 	lock         sync.Mutex
-	sync         chan struct{}
 	actions      []types.Action
 	resourceKind types.ResourceKind
 	search       string
+	// searching is set while the search input has focus, searchSeq counts its keystrokes
+	searching int32
+	searchSeq int32
+	// column is the column cursor Left and Right move, ShowCell shows its cell of the selected row
+	column int32
+	// shown is set by SwitchPage while t is the page on screen, the refresh goroutines read it
+	shown int32
+
+	previousRows   map[string]datafeeder.Row
+	drawGeneration int32
+	follow         bool
+
+	// drawn are the rows the last draw put below the header, the UI goroutine owns the cells themselves
+	drawn []datafeeder.Row
+
+	// each table refreshes under its own context, hidden tables hold their refreshes until shown unless kept warm
+	ctx      context.Context
+	cancel   context.CancelFunc
+	wake     chan struct{}
+	pending  int32
+	keepWarm bool
+
+	// refreshes closer together than minInterval are held back and merged into one
+	minInterval time.Duration
+	lastRefresh time.Time
 }
 
+const defaultMinRefreshInterval = time.Second
+
 type EventHandler func(t *TableView) func(event *tcell.EventKey) *tcell.EventKey
 
 func NewTableView(app *AppView, kind string, drawer types.Drawer) *TableView {
@@ -44,7 +68,7 @@ func NewTableView(app *AppView, kind string, drawer types.Drawer) *TableView {
 	}
 	t.init(app, view.Kind, view.Feeder, view.Actions, drawer.PageNav, nil)
 	if err := t.refresh(); err != nil {
-		return t.UpdateStatus(err.Error(), true).(*TableView)
+		t.refreshFailed(err)
 	}
 	return t
 }
@@ -56,7 +80,7 @@ func (t *TableView) NewNestTableView(kind types.ResourceKind, feeder datafeeder.
 	}
 	nt.init(t.app, kind, feeder, actions, pageNav, embeddedHandler)
 	if err := nt.refresh(); err != nil {
-		return t.UpdateStatus(err.Error(), true).(*TableView)
+		nt.refreshFailed(err)
 	}
 	return nt
 }
@@ -66,7 +90,6 @@ func (t *TableView) init(app *AppView, resource types.ResourceKind, dataFeeder d
 		t.app = app
 		t.resourceKind = resource
 		t.dataSource = dataFeeder
-		t.sync = app.syncs[resource.Kind]
 		t.actions = actions
 		t.client = app.clientset
 		t.navigateMap = pageNav
@@ -78,10 +101,6 @@ func (t *TableView) init(app *AppView, resource types.ResourceKind, dataFeeder d
 		t.Table.SetSelectable(true, false)
 		t.Table.SetTitle(t.resourceKind.Title)
 	}
-	if t.sync == nil {
-		t.sync = make(chan struct{}, 0)
-	}
-
 	if p, ok := t.app.pageRows[t.resourceKind.Kind]; ok {
 		t.Table.Select(p.row, p.column)
 	}
@@ -97,36 +116,115 @@ func (t *TableView) init(app *AppView, resource types.ResourceKind, dataFeeder d
 			row:    row,
 			column: column,
 		}
+		t.app.detailView.show(t)
+		t.app.readOut(t)
 	})
 
 	if embeddedHandler != nil {
-		t.SetInputCapture(embeddedHandler(t))
-		return
+		t.SetInputCapture(t.columnKeys(app.keymap.wrap(t, embeddedHandler(t))))
+	} else if app.handler != nil {
+		t.SetInputCapture(t.columnKeys(app.keymap.wrap(t, app.handler(t))))
 	}
 
-	if app.handler != nil {
-		t.SetInputCapture(app.handler(t))
+	t.minInterval = defaultMinRefreshInterval
+	t.ctx, t.cancel = context.WithCancel(app.context)
+	t.wake = make(chan struct{}, 1)
+	events, unsubscribe := app.Subscribe(t.resourceKind.Kind)
+	app.Go(func() {
+		defer unsubscribe()
+		t.run(t.ctx, events)
+	})
+	if watcher, ok := t.dataSource.(datafeeder.Watcher); ok {
+		app.Go(func() { watcher.Watch(t.ctx, t.Refresh) })
+	}
+	if _, ok := t.dataSource.(datafeeder.Ager); ok {
+		app.Go(func() { t.tick(t.ctx) })
 	}
-
-	go func() {
-		t.run(app.context)
-	}()
 }
 
-func (t *TableView) run(ctx context.Context) {
+func (t *TableView) run(ctx context.Context, events <-chan Event) {
 	for {
 		select {
-		case <-t.sync:
-			if t.resourceKind.Kind != t.app.currentPage {
-				continue
+		case <-events:
+			// a burst of events needs one refresh
+			drain(events)
+			if wait := t.refreshWait(); wait > 0 {
+				select {
+				case <-time.After(wait):
+				case <-ctx.Done():
+					return
+				}
+				drain(events)
 			}
-			if err := t.refresh(); err != nil {
-				t.UpdateStatus(err.Error(), true)
+			if !t.keepWarm && !t.visible() {
+				atomic.StoreInt32(&t.pending, 1)
+				// shown in the meantime, resume found nothing pending yet
+				if !t.visible() || !atomic.CompareAndSwapInt32(&t.pending, 1, 0) {
+					continue
+				}
 			}
-			t.SwitchPage(t.app.currentPage, t.app.tableViews[t.app.currentPage])
+		case <-t.wake:
 		case <-ctx.Done():
 			return
 		}
+		if err := t.refresh(); err != nil {
+			t.refreshFailed(err)
+		}
+		// whether t is still shown is decided on the UI goroutine, it may have been left while refreshing
+		t.app.QueueUpdateDraw(func() {
+			if t.visible() {
+				t.SwitchPage(t.app.currentPage, t)
+			}
+		})
+	}
+}
+
+// visible reports whether t is the page on screen in the active tab, it's safe to call from any goroutine
+func (t *TableView) visible() bool {
+	return atomic.LoadInt32(&t.shown) == 1
+}
+
+// refreshWait is how long the next refresh has to wait for the minimum interval
+func (t *TableView) refreshWait() time.Duration {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	return t.minInterval - time.Since(t.lastRefresh)
+}
+
+// SetMinRefreshInterval is the least time between two refreshes of t triggered by events, zero refreshes on every event
+func (t *TableView) SetMinRefreshInterval(interval time.Duration) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.minInterval = interval
+}
+
+// resume runs the refresh held back while t was hidden
+func (t *TableView) resume() {
+	if atomic.CompareAndSwapInt32(&t.pending, 1, 0) {
+		select {
+		case t.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// SetKeepWarm keeps t refreshing while it's hidden, so it's up to date the moment it's shown
+func (t *TableView) SetKeepWarm(keepWarm bool) {
+	t.keepWarm = keepWarm
+}
+
+// Close stops the refreshes of t for good, e.g. when its tab is closed
+func (t *TableView) Close() {
+	t.cancel()
+}
+
+func drain(events <-chan Event) {
+	for {
+		select {
+		case <-events:
+		default:
+			return
+		}
 	}
 }
 
@@ -145,16 +243,37 @@ func (t *TableView) refresh() error {
 	t.lock.Lock()
 	defer t.lock.Unlock()
 
+	if slots := t.app.refreshSlots; slots != nil {
+		select {
+		case slots <- struct{}{}:
+		case <-t.ctx.Done():
+			return nil
+		}
+		defer func() { <-slots }()
+	}
+
+	allocated := t.app.profile.allocated()
+	start := time.Now()
+	t.lastRefresh = start
 	if err := t.dataSource.Refresh(); err != nil {
+		logrus.Debugf("refresh %s failed after %v: %v", t.resourceKind.Kind, time.Since(start), err)
+		t.app.profile.failed(t.resourceKind.Kind)
 		return err
 	}
+	fetched := time.Now()
+	logrus.Debugf("refresh %s took %v", t.resourceKind.Kind, fetched.Sub(start))
+	// the table lock is held here, queueing the redraw must not wait for the UI
+	go t.app.RecordLatency(fetched.Sub(start))
 	t.draw()
+	t.app.profile.refreshed(t.resourceKind.Kind, fetched.Sub(start), time.Since(fetched), allocated, len(t.drawn))
 	return nil
 }
 
+/*
+draw works out the rows of t under the table lock, off the UI goroutine, and puts them in the table on it,
+where Table.Draw reads them. A draw that a newer one overtook before it got there is dropped.
+*/
 func (t *TableView) draw() {
-	t.Clear()
-
 	header := t.dataSource.Header()
 	data := t.dataSource.Data()
 
@@ -163,26 +282,87 @@ func (t *TableView) draw() {
 		if name == "NAME" {
 			nameRow = col
 		}
-		t.addHeaderCell(col, name)
 	}
 
-	r := 0
+	filter := parseFilter(t.search)
+	highlight := atomic.LoadInt32(&t.searching) == 1 && !t.app.plain
+	styler, _ := t.dataSource.(datafeeder.RowStyler)
+	changes, removed := t.diffRows(header, data)
+	generation := atomic.AddInt32(&t.drawGeneration, 1)
+	follow := t.follow
+
+	var rows []datafeeder.Row
+	var colors []tcell.Color
+	var highlighted []int
+	var backgrounds []tcell.Color
 	for _, row := range data {
 		if len(row) > 0 && row[0] == "" {
 			continue
 		}
-		if t.search != "" && !strings.Contains(row[nameRow], t.search) {
+		if !filter.matches(header, row, nameRow) {
 			continue
 		}
-		for col, value := range row {
-			t.addBodyCell(r, col, value)
+		color := tcell.ColorAntiqueWhite
+		if styler != nil {
+			if c := styler.RowColor(header, row); c != tcell.ColorDefault {
+				color = c
+			}
+		}
+		if change := changes[rowKey(header, row)]; change != rowUnchanged {
+			highlighted = append(highlighted, len(rows)+1)
+			backgrounds = append(backgrounds, change.color())
 		}
-		r++
+		if highlight {
+			marked := make(datafeeder.Row, len(row))
+			for col, value := range row {
+				marked[col] = filter.highlight(header, nameRow, col, value)
+			}
+			row = marked
+		}
+		rows = append(rows, row)
+		colors = append(colors, color)
 	}
-	if t.search != "" {
+	matched := len(rows)
+	rows = append(rows, removed...)
+	t.drawn = append([]datafeeder.Row(nil), rows...)
+	if t.search != "" && atomic.LoadInt32(&t.searching) == 0 {
 		t.search = ""
 	}
-	t.GetApplication().Draw()
+
+	t.app.QueueUpdateDraw(func() {
+		if generation != atomic.LoadInt32(&t.drawGeneration) {
+			return
+		}
+		t.Clear()
+		for col, name := range header {
+			t.addHeaderCell(col, name)
+		}
+		t.Table.SetFixed(1, fixedColumns(header))
+		t.markColumn()
+		for r, row := range rows {
+			color := tcell.ColorAntiqueWhite
+			if r < matched {
+				color = colors[r]
+			}
+			for col, value := range row {
+				t.addBodyCell(r, col, value, color)
+			}
+		}
+		for i, r := range highlighted {
+			t.setRowBackground(r, backgrounds[i])
+		}
+		for r := matched + 1; r <= len(rows); r++ {
+			t.setRowBackground(r, removedColor)
+		}
+		if follow && matched > 0 {
+			t.Table.Select(matched, 0)
+		}
+		if len(highlighted) > 0 || len(removed) > 0 {
+			t.app.Go(func() { t.fadeChanges(generation, highlighted, len(removed)) })
+		}
+		t.app.detailView.show(t)
+		t.app.readOut(t)
+	})
 }
 
 func (t *TableView) addHeaderCell(col int, name string) {
@@ -195,11 +375,12 @@ func (t *TableView) addHeaderCell(col int, name string) {
 	t.Table.SetCell(0, col, c)
 }
 
-func (t *TableView) addBodyCell(row, col int, value string) {
+func (t *TableView) addBodyCell(row, col int, value string, color tcell.Color) {
 	c := tview.NewTableCell(fmt.Sprintf("%s", value))
 	{
 		c.SetExpansion(1)
-		c.SetTextColor(tcell.ColorAntiqueWhite)
+		c.SetTextColor(color)
+		c.SetMaxWidth(t.app.maxCellWidth)
 	}
 	t.Table.SetCell(row+1, col, c)
 }
@@ -212,39 +393,27 @@ func (t *TableView) InsertDialog(name string, page tview.Primitive, dialog tview
 	t.app.Application.SetFocus(dialog)
 }
 
+/*
+UpdateStatus shows status as a toast in the status bar instead of replacing the current page.
+It is kept for existing blades, new code should call Notify directly.
+*/
 func (t *TableView) UpdateStatus(status string, isError bool) tview.Primitive {
-	statusBar := tview.NewTextView()
-	statusBar.SetBorder(true)
-	statusBar.SetBorderAttributes(tcell.AttrBold)
-	statusBar.SetBorderPadding(1, 1, 1, 1)
+	severity := SeverityProgress
 	if isError {
-		statusBar.SetTitle("Error")
-		statusBar.SetTitleColor(tcell.ColorRed)
-		statusBar.SetTextColor(tcell.ColorRed)
-		statusBar.SetBorderColor(tcell.ColorRed)
-	} else {
-		statusBar.SetTitle("Progress")
-		statusBar.SetTitleColor(tcell.ColorYellow)
-		statusBar.SetTextColor(tcell.ColorYellow)
-		statusBar.SetBorderColor(tcell.ColorYellow)
+		severity = SeverityError
 	}
-	statusBar.SetText(status)
-	statusBar.SetTextAlign(tview.AlignCenter)
-	newpage := tview.NewPages()
-	if _, ok := t.app.tableViews[t.app.currentPage]; ok {
-		newpage.AddPage("handler", t.app.currentPrimitive, true, true)
-	}
-	newpage.AddPage("dialog", center(statusBar, 100, 5), true, true)
-	t.app.SwitchPage(t.app.currentPage, newpage, t.actions)
-
-	go func() {
-		time.Sleep(time.Second * errorDelayTime)
-		t.SwitchToRootPage()
-		t.GetApplication().Draw()
-	}()
+	t.Notify(status, severity)
 	return t
 }
 
+func (t *TableView) Notify(message string, severity Severity) {
+	t.app.Notify(message, severity)
+}
+
+func (t *TableView) NotifyWithTimeout(message string, severity Severity, timeout time.Duration) {
+	t.app.NotifyWithTimeout(message, severity, timeout)
+}
+
 func (t *TableView) GetClientSet() *kubernetes.Clientset {
 	return t.client
 }
@@ -268,6 +437,8 @@ func (t *TableView) GetApplication() *tview.Application {
 func (t *TableView) UpdateFeeder(kind string, feeder datafeeder.DataSource) {
 	tableview := t.app.tableViews[kind]
 	tableview.dataSource = feeder
+	// a different feeder is a different list, don't diff against the old one
+	tableview.previousRows = nil
 }
 
 func (t *TableView) GetCurrentPrimitive() tview.Primitive {
@@ -281,6 +452,14 @@ func (t *TableView) SwitchPage(page string, draw tview.Primitive) {
 	t.app.SwitchPage(page, draw, t.app.tableViews[page].actions)
 }
 
+/*
+OnLeave calls leave once p, a page passed to SwitchPage, gives way to another page, whether by Escape, q,
+the footer or another tab. Watches and streams feeding p stop there instead of running on unseen.
+*/
+func (t *TableView) OnLeave(p tview.Primitive, leave func()) {
+	t.app.onLeave[p] = leave
+}
+
 func (t *TableView) SetCurrentPage(page string) {
 	t.app.currentPage = page
 }
@@ -300,35 +479,86 @@ func (t *TableView) BackPage() {
 	t.app.LastPage()
 }
 
+// Refresh publishes a refresh of the kind of t, so every table of the kind refreshes
 func (t *TableView) Refresh() {
-	go func() {
-		t.sync <- struct{}{}
-	}()
+	t.app.Publish(Event{Kind: t.resourceKind.Kind, Type: EventRefresh})
+}
+
+// Publish hands e to the subscribers of its kind
+func (t *TableView) Publish(e Event) {
+	t.app.Publish(e)
 }
 
 func (t *TableView) RefreshManual() {
 	if err := t.refresh(); err != nil {
-		t.UpdateStatus(err.Error(), true)
+		t.refreshFailed(err)
 	}
 }
 
-func (t *TableView) UpdateWithSearch(search string) {
-	t.search = search
+// refreshFailed has the connection checked, while the API server is away the banner already says why
+func (t *TableView) refreshFailed(err error) {
+	t.app.connectionView.probe()
+	if !t.app.Offline() {
+		t.Notify(err.Error(), SeverityError)
+	}
+}
+
+// Draw marks the table stale while the API server can't be reached
+func (t *TableView) Draw(screen tcell.Screen) {
+	if t.app.Offline() {
+		t.Table.SetBorderColor(tcell.ColorRed)
+	} else {
+		t.Table.SetBorderColor(tview.Styles.BorderColor)
+	}
+	t.Table.Draw(screen)
 }
 
-func (t *TableView) ShowSearch() {
-	t.app.SetFocus(t.app.searchView.InputField)
+// SetFollow keeps the selection on the last row after every refresh, like tail -f
+func (t *TableView) SetFollow(follow bool) {
+	t.follow = follow
+}
+
+func (t *TableView) Following() bool {
+	return t.follow
+}
+
+// UpdateWithSearch filters the next draw, plain words match NAME and column=value, column!=value
+// or column~value the named column, every term has to match
+func (t *TableView) UpdateWithSearch(search string) {
+	t.search = search
 }
 
 func (t *TableView) Navigate(r rune) {
-	app := t.app
 	if kind, ok := t.navigateMap[r]; ok {
-		app.footerView.TextView.Highlight(kind).ScrollToHighlight()
-		if _, ok := app.tableViews[kind]; !ok {
-			app.tableViews[kind] = NewTableView(app, kind, t.drawer)
+		t.navigate(kind)
+	}
+}
+
+// cyclePage moves to the next page of the footer, or the previous one when step is negative
+func (t *TableView) cyclePage(step int) {
+	footers := t.app.Footers
+	if len(footers) == 0 {
+		return
+	}
+	current := -1
+	for i, f := range footers {
+		if f.Kind == t.app.currentPage {
+			current = i
 		}
-		app.SwitchPage(kind, app.tableViews[kind], app.tableViews[kind].actions)
 	}
+	if current < 0 && step < 0 {
+		current = 0
+	}
+	t.navigate(footers[(current+step+len(footers))%len(footers)].Kind)
+}
+
+func (t *TableView) navigate(kind string) {
+	app := t.app
+	app.footerView.TextView.Highlight(kind).ScrollToHighlight()
+	if _, ok := app.tableViews[kind]; !ok {
+		app.tableViews[kind] = NewTableView(app, kind, t.drawer)
+	}
+	app.SwitchPage(kind, app.tableViews[kind], app.tableViews[kind].actions)
 }
 
 func (t *TableView) RootPage() {
@@ -345,5 +575,8 @@ func (t *TableView) GetNestedTable(kind string) *TableView {
 }
 
 func (t *TableView) SetTableView(kind string, nt *TableView) {
+	if old, ok := t.app.tableViews[kind]; ok && old != nt {
+		old.Close()
+	}
 	t.app.tableViews[kind] = nt
 }
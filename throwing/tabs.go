@@ -0,0 +1,118 @@
+package throwing
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell"
+	"github.com/rivo/tview"
+)
+
+/*
+tab is the navigation state of one tab.
+The active tab lives in the AppView fields, switching tabs saves them here and loads the next tab's.
+Every tab has its own tables, so each keeps its own filters and refresh loop.
+*/
+type tab struct {
+	tableViews       map[string]*TableView
+	pageRows         map[string]position
+	drawQueue        *PrimitiveQueue
+	currentPage      string
+	currentPrimitive *TableView
+}
+
+type tabBar struct {
+	*tview.TextView
+	*AppView
+}
+
+func (b *tabBar) init() {
+	b.TextView.
+		SetDynamicColors(true).
+		SetRegions(true).
+		SetWrap(false).SetBackgroundColor(tcell.ColorBlack)
+	b.draw()
+}
+
+func (b *tabBar) draw() {
+	b.TextView.Clear()
+	for i, t := range b.tabs {
+		page := t.currentPage
+		if i == b.activeTab {
+			page = b.currentPage
+		}
+		fmt.Fprintf(b.TextView, `["%d"][white] %d:%s [""] `, i, i+1, page)
+	}
+	b.TextView.Highlight(fmt.Sprint(b.activeTab))
+}
+
+func (app *AppView) saveTab() {
+	t := app.tabs[app.activeTab]
+	t.tableViews = app.tableViews
+	t.pageRows = app.pageRows
+	t.drawQueue = app.drawQueue
+	t.currentPage = app.currentPage
+	t.currentPrimitive = app.currentPrimitive
+}
+
+// loadTab makes tab i the active one and refreshes the table it shows, a new tab starts on the root page
+func (app *AppView) loadTab(i int) {
+	app.activeTab = i
+	t := app.tabs[i]
+	app.pageRows = t.pageRows
+	app.drawQueue = t.drawQueue
+	if t.tableViews == nil {
+		t.tableViews = map[string]*TableView{}
+		app.tableViews = t.tableViews
+		t.tableViews[app.RootPage] = NewTableView(app, app.RootPage, app.Drawer)
+	}
+	app.tableViews = t.tableViews
+
+	tv := t.currentPrimitive
+	if tv == nil {
+		tv = app.tableViews[app.RootPage]
+	}
+	// force SwitchPage to treat the page as new
+	app.currentPage = ""
+	app.SwitchPage(tv.resourceKind.Kind, tv, tv.actions)
+	tv.Refresh()
+	app.tabBar.draw()
+}
+
+func (app *AppView) activateTab(i int) {
+	app.saveTab()
+	app.loadTab(i)
+}
+
+// newTab opens a tab on the root page
+func (app *AppView) newTab() {
+	app.saveTab()
+	app.tabs = append(app.tabs, &tab{
+		pageRows:  make(map[string]position),
+		drawQueue: &PrimitiveQueue{AppView: app},
+	})
+	app.loadTab(len(app.tabs) - 1)
+}
+
+// closeTab closes the active tab, the last tab can't be closed
+func (app *AppView) closeTab() {
+	if len(app.tabs) == 1 {
+		return
+	}
+	i := app.activeTab
+	for _, t := range app.tableViews {
+		t.Close()
+	}
+	app.tabs = append(app.tabs[:i], app.tabs[i+1:]...)
+	if i >= len(app.tabs) {
+		i = len(app.tabs) - 1
+	}
+	app.loadTab(i)
+}
+
+// cycleTab moves to the next tab, or the previous one when step is negative
+func (app *AppView) cycleTab(step int) {
+	if len(app.tabs) < 2 {
+		return
+	}
+	app.activateTab((app.activeTab + step + len(app.tabs)) % len(app.tabs))
+}
@@ -0,0 +1,82 @@
+package throwing
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell"
+	"github.com/rivo/tview"
+)
+
+const (
+	progressWidth  = 60
+	progressHeight = 7
+	progressBar    = 40
+)
+
+// Progress reports how far a task got, total of zero draws no bar
+type Progress func(done, total int, message string)
+
+// Task is a long running action, it has to return soon after ctx is cancelled
+type Task func(ctx context.Context, progress Progress) error
+
+/*
+RunTask runs task in the background behind a progress dialog.
+c in the dialog cancels the task, the back key only hides the dialog and the task carries on.
+The outcome is reported as a notification either way.
+*/
+func (app *AppView) RunTask(title string, task Task) {
+	ctx, cancel := context.WithCancel(app.context)
+
+	view := tview.NewTextView().SetDynamicColors(true)
+	view.SetBorder(true)
+	view.SetTitle(title)
+	view.SetBackgroundColor(tcell.ColorBlack)
+	view.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Rune() == 'c' {
+			cancel()
+			view.SetText("cancelling...")
+			return nil
+		}
+		return event
+	})
+	view.SetText("starting...")
+	closeDialog := app.showDialog("task", view, progressWidth, progressHeight)
+
+	progress := func(done, total int, message string) {
+		text := tview.Escape(message)
+		if total > 0 {
+			filled := progressBar * done / total
+			text += fmt.Sprintf("\n\n[green]%s[white]%s %d/%d", strings.Repeat("█", filled), strings.Repeat("░", progressBar-filled), done, total)
+		}
+		text += "\n\n[gray]c cancel, esc hide"
+		app.Application.QueueUpdateDraw(func() {
+			view.SetText(text)
+		})
+	}
+
+	app.Go(func() {
+		defer cancel()
+		err := task(ctx, progress)
+		app.Application.QueueUpdateDraw(func() {
+			// the dialog is gone already when it was hidden
+			if app.GetFocus() == view {
+				closeDialog()
+			}
+		})
+		switch {
+		case ctx.Err() != nil && app.context.Err() == nil:
+			app.Notify(title+" cancelled", SeverityWarning)
+		case err != nil:
+			app.Notify(fmt.Sprintf("%s failed: %v", title, err), SeverityError)
+		default:
+			app.Notify(title+" done", SeverityInfo)
+		}
+	})
+}
+
+// RunTask runs task in the background on the app of t
+func (t *TableView) RunTask(title string, task Task) {
+	t.app.RunTask(title, task)
+}
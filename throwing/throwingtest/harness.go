@@ -0,0 +1,177 @@
+/*
+Package throwingtest runs a throwing app headless on a tcell.SimulationScreen, so views can be tested end to end:
+
+	feeder := datafeeder.NewFake(datafeeder.Row{"NAME", "STATUS"}, datafeeder.Row{"web", "Running"})
+	drawer := types.Drawer{
+		RootPage: "demo",
+		ViewMap: map[string]types.View{
+			"demo": {Kind: types.ResourceKind{Title: "demo", Kind: "demo"}, Feeder: feeder},
+		},
+	}
+
+	h, err := throwingtest.New(drawer, handler, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Stop()
+
+	h.Type("/")
+	h.Type("web")
+	h.Key(tcell.KeyEnter)
+	if err := h.WaitFor("Running", time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+The clientset passed to New answers what the app itself asks, like the version on Init, and what actions get
+from TableView.GetClientSet. NewFakeServer serves one from canned objects. Feeders that build their own clients,
+like the refreshers of the k8s blade with newClientset, don't use it; they need a kubeconfig pointing at server.URL.
+*/
+package throwingtest
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell"
+	"github.com/rancher/axe/throwing"
+	"github.com/rancher/axe/throwing/types"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	screenWidth  = 160
+	screenHeight = 50
+	pollInterval = 10 * time.Millisecond
+)
+
+// Harness is an app running on a simulation screen
+type Harness struct {
+	App    *throwing.AppView
+	Screen tcell.SimulationScreen
+	done   chan error
+}
+
+// New initializes an app with drawer and handler and runs it on a simulation screen, the clientset may be nil
+func New(drawer types.Drawer, handler throwing.EventHandler, clientset *kubernetes.Clientset) (*Harness, error) {
+	screen := tcell.NewSimulationScreen("UTF-8")
+	if err := screen.Init(); err != nil {
+		return nil, err
+	}
+	screen.SetSize(screenWidth, screenHeight)
+
+	app := throwing.NewAppView(clientset, drawer, handler)
+	app.SetScreen(screen)
+	if err := app.Init(); err != nil {
+		return nil, err
+	}
+	h := &Harness{
+		App:    app,
+		Screen: screen,
+		done:   make(chan error, 1),
+	}
+	go func() {
+		h.done <- app.Run()
+	}()
+	return h, nil
+}
+
+// Key sends a special key like tcell.KeyEnter
+func (h *Harness) Key(key tcell.Key) {
+	h.post(key, 0, tcell.ModNone)
+}
+
+// KeyWithMod sends a key with modifiers, e.g. tcell.KeyCtrlP
+func (h *Harness) KeyWithMod(key tcell.Key, r rune, mod tcell.ModMask) {
+	h.post(key, r, mod)
+}
+
+// Type sends every rune of text as a key press
+func (h *Harness) Type(text string) {
+	for _, r := range text {
+		h.post(tcell.KeyRune, r, tcell.ModNone)
+	}
+}
+
+// post waits for room in the event queue of the screen, InjectKey drops the key once ten are queued
+func (h *Harness) post(key tcell.Key, r rune, mod tcell.ModMask) {
+	h.Screen.PostEventWait(tcell.NewEventKey(key, r, mod))
+}
+
+// snapshotTimeout is how long a snapshot waits for the UI goroutine, a stopped app never takes one
+const snapshotTimeout = time.Second
+
+type snapshot struct {
+	cells         []tcell.SimCell
+	width, height int
+}
+
+// snapshot copies the screen on the UI goroutine, between draws, so a draw never changes it half way through
+func (h *Harness) snapshot() snapshot {
+	taken := make(chan snapshot, 1)
+	h.App.QueueUpdate(func() {
+		cells, width, height := h.Screen.GetContents()
+		taken <- snapshot{cells: append([]tcell.SimCell(nil), cells...), width: width, height: height}
+	})
+	select {
+	case s := <-taken:
+		return s
+	case <-time.After(snapshotTimeout):
+		return snapshot{}
+	}
+}
+
+// Lines returns what's on the screen, one string per row with trailing blanks trimmed
+func (h *Harness) Lines() []string {
+	s := h.snapshot()
+	lines := make([]string, s.height)
+	for y := 0; y < s.height; y++ {
+		var b strings.Builder
+		for x := 0; x < s.width; x++ {
+			runes := s.cells[y*s.width+x].Runes
+			if len(runes) == 0 {
+				b.WriteRune(' ')
+				continue
+			}
+			b.WriteString(string(runes))
+		}
+		lines[y] = strings.TrimRight(b.String(), " ")
+	}
+	return lines
+}
+
+// Text returns the whole screen
+func (h *Harness) Text() string {
+	return strings.Join(h.Lines(), "\n")
+}
+
+// Cell returns the text and the style drawn at x, y
+func (h *Harness) Cell(x, y int) (string, tcell.Style) {
+	s := h.snapshot()
+	if x < 0 || y < 0 || x >= s.width || y >= s.height {
+		return "", tcell.StyleDefault
+	}
+	cell := s.cells[y*s.width+x]
+	return string(cell.Runes), cell.Style
+}
+
+// WaitFor waits until text is on the screen, the error shows the screen when it doesn't show up in time
+func (h *Harness) WaitFor(text string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		h.App.Application.Draw()
+		if strings.Contains(h.Text(), text) {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("%q not on the screen after %v:\n%s", text, timeout, h.Text())
+		}
+		time.Sleep(pollInterval)
+	}
+}
+
+// Stop stops the app and waits for Run to return
+func (h *Harness) Stop() error {
+	h.App.Stop()
+	return <-h.done
+}
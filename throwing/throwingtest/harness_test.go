@@ -0,0 +1,105 @@
+package throwingtest_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gdamore/tcell"
+	"github.com/rancher/axe/throwing"
+	"github.com/rancher/axe/throwing/datafeeder"
+	"github.com/rancher/axe/throwing/throwingtest"
+	"github.com/rancher/axe/throwing/types"
+)
+
+const waitTimeout = 5 * time.Second
+
+// demoHandler searches with / and runs the restart action of the demo page on the selected row with r
+func demoHandler(t *throwing.TableView) func(event *tcell.EventKey) *tcell.EventKey {
+	return func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() != tcell.KeyRune {
+			return event
+		}
+		switch event.Rune() {
+		case '/':
+			t.ShowSearch()
+		case 'r':
+			header, row := t.SelectedRow()
+			for i, h := range header {
+				if h == "NAME" && i < len(row) {
+					t.Notify("restarted "+row[i], throwing.SeverityInfo)
+				}
+			}
+		default:
+			return event
+		}
+		return nil
+	}
+}
+
+func demoDrawer() types.Drawer {
+	feeder := datafeeder.NewFake(datafeeder.Row{"NAME", "STATUS"},
+		datafeeder.Row{"web", "Running"},
+		datafeeder.Row{"db", "Pending"},
+		datafeeder.Row{"cache", "Running"},
+	)
+	return types.Drawer{
+		RootPage: "demo",
+		ViewMap: map[string]types.View{
+			"demo": {
+				Actions: []types.Action{
+					{Name: "search", Shortcut: "/", Description: "search the table"},
+					{Name: "restart", Shortcut: "r", Description: "restart the selected row"},
+				},
+				Kind:   types.ResourceKind{Title: "demo", Kind: "demo"},
+				Feeder: feeder,
+			},
+		},
+		PageNav: map[rune]string{},
+	}
+}
+
+// waitGone waits until text is off the screen
+func waitGone(h *throwingtest.Harness, text string) error {
+	deadline := time.Now().Add(waitTimeout)
+	for {
+		h.App.Application.Draw()
+		if !strings.Contains(h.Text(), text) {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("%q still on the screen after %v:\n%s", text, waitTimeout, h.Text())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestPageSearchAndAction(t *testing.T) {
+	h, err := throwingtest.New(demoDrawer(), demoHandler, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer h.Stop()
+
+	for _, name := range []string{"web", "db", "cache"} {
+		if err := h.WaitFor(name, waitTimeout); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	h.Type("/")
+	h.Type("status=pending")
+	h.Key(tcell.KeyEnter)
+	if err := waitGone(h, "cache"); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.WaitFor("db", waitTimeout); err != nil {
+		t.Fatal(err)
+	}
+
+	h.Type("r")
+	if err := h.WaitFor("restarted db", waitTimeout); err != nil {
+		t.Fatal(err)
+	}
+}
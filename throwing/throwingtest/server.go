@@ -0,0 +1,84 @@
+package throwingtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+// defaultVersion is what /version answers, AppView.Init asks for it
+var defaultVersion = map[string]string{
+	"major":      "1",
+	"minor":      "14",
+	"gitVersion": "v1.14.0-fake",
+}
+
+/*
+FakeServer is an API server answering GET requests with the objects set for their path.
+Pointing a real clientset at it exercises the same code paths as a cluster does, paths without an object answer 404.
+*/
+type FakeServer struct {
+	*httptest.Server
+	lock     sync.Mutex
+	objects  map[string]interface{}
+	requests []string
+}
+
+func NewFakeServer() *FakeServer {
+	f := &FakeServer{
+		objects: map[string]interface{}{
+			"/version": defaultVersion,
+		},
+	}
+	f.Server = httptest.NewServer(http.HandlerFunc(f.serve))
+	return f
+}
+
+// Set makes GET path answer with obj encoded as JSON, nil removes the path
+func (f *FakeServer) Set(path string, obj interface{}) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if obj == nil {
+		delete(f.objects, path)
+		return
+	}
+	f.objects[path] = obj
+}
+
+// Requests returns the method and path of every request served so far
+func (f *FakeServer) Requests() []string {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return append([]string(nil), f.requests...)
+}
+
+// Clientset returns a clientset talking to the server
+func (f *FakeServer) Clientset() *kubernetes.Clientset {
+	return kubernetes.NewForConfigOrDie(&rest.Config{Host: f.URL})
+}
+
+func (f *FakeServer) serve(w http.ResponseWriter, r *http.Request) {
+	f.lock.Lock()
+	f.requests = append(f.requests, r.Method+" "+r.URL.Path)
+	obj, ok := f.objects[r.URL.Path]
+	f.lock.Unlock()
+
+	if r.Method != http.MethodGet || !ok {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"kind":       "Status",
+			"apiVersion": "v1",
+			"status":     "Failure",
+			"reason":     "NotFound",
+			"code":       http.StatusNotFound,
+		})
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(obj)
+}
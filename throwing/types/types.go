@@ -29,6 +29,9 @@ type ResourceKind struct {
 
 type Refresher func(b *bytes.Buffer) error
 
+// DetailFunc renders the summary shown in the split pane for one row, it's called off the UI goroutine
+type DetailFunc func(kind string, header, row []string) (string, error)
+
 type Drawer struct {
 	RootPage  string
 	ViewMap   map[string]View
@@ -36,6 +39,7 @@ type Drawer struct {
 	Shortcuts [][]string
 	Footers   []ResourceView
 	Menu      []Action
+	Detail    DetailFunc
 }
 
 type Action struct {
@@ -0,0 +1,126 @@
+package throwing
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/gdamore/tcell"
+	"github.com/rivo/tview"
+)
+
+const summaryPage = "summary"
+
+/*
+WizardStep is one page of a wizard.
+Validate checks the values of the step together before the wizard moves on, after the validators of the fields passed.
+*/
+type WizardStep struct {
+	Title    string
+	Fields   []FormField
+	Validate func(values FormValues) error
+}
+
+/*
+ShowWizard walks through the steps with Next and Back, then shows a summary of every value before Finish.
+Going back keeps what was typed. done gets the values of all steps, an error it returns is notified and keeps the wizard open.
+Escape closes the wizard without calling done.
+*/
+func (app *AppView) ShowWizard(title string, steps []WizardStep, done func(values FormValues) error) {
+	if len(steps) == 0 {
+		return
+	}
+	pages := tview.NewPages()
+	maxFields := 0
+	for _, step := range steps {
+		if len(step.Fields) > maxFields {
+			maxFields = len(step.Fields)
+		}
+	}
+	closeDialog := app.showDialog("wizard", pages, formWidth, formHeight(maxFields+1))
+
+	collected := make([]FormValues, len(steps))
+	stepPage := func(i int) string {
+		return fmt.Sprintf("step-%d", i)
+	}
+	show := func(page string) {
+		pages.SwitchToPage(page)
+		app.SetFocus(pages)
+	}
+
+	summary := tview.NewTextView().SetDynamicColors(true)
+	summary.SetBackgroundColor(tcell.ColorBlack)
+	summaryButtons := tview.NewForm()
+	summaryButtons.SetBackgroundColor(tcell.ColorBlack)
+	summaryView := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(summary, 0, 1, false).
+		AddItem(summaryButtons, formButtonRows, 0, true)
+	summaryView.SetBorder(true)
+	summaryView.SetTitle(fmt.Sprintf("%s - summary", title))
+	summaryView.SetBackgroundColor(tcell.ColorBlack)
+
+	showSummary := func() {
+		summary.Clear()
+		for i, step := range steps {
+			fmt.Fprintf(summary, "[purple]%s[white]\n", tview.Escape(step.Title))
+			var names []string
+			for name := range collected[i] {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				fmt.Fprintf(summary, "  %s: %s\n", tview.Escape(name), tview.Escape(collected[i][name]))
+			}
+		}
+		show(summaryPage)
+	}
+
+	for i, step := range steps {
+		i, step := i, step
+		form := newForm(fmt.Sprintf("%s - step %d/%d: %s", title, i+1, len(steps), step.Title))
+		collect := addFields(form, step.Fields)
+		if i > 0 {
+			form.AddButton("Back", func() { show(stepPage(i - 1)) })
+		}
+		form.AddButton("Next", func() {
+			values, err := collect()
+			if err == nil && step.Validate != nil {
+				err = step.Validate(values)
+			}
+			if err != nil {
+				app.Notify(err.Error(), SeverityError)
+				return
+			}
+			collected[i] = values
+			if i == len(steps)-1 {
+				showSummary()
+				return
+			}
+			show(stepPage(i + 1))
+		})
+		form.SetCancelFunc(closeDialog)
+		pages.AddPage(stepPage(i), form, true, i == 0)
+	}
+
+	summaryButtons.AddButton("Back", func() { show(stepPage(len(steps) - 1)) })
+	summaryButtons.AddButton("Finish", func() {
+		all := FormValues{}
+		for _, values := range collected {
+			for name, value := range values {
+				all[name] = value
+			}
+		}
+		if err := done(all); err != nil {
+			app.Notify(err.Error(), SeverityError)
+			return
+		}
+		closeDialog()
+	})
+	summaryButtons.SetCancelFunc(closeDialog)
+	pages.AddPage(summaryPage, summaryView, true, false)
+	show(stepPage(0))
+}
+
+// ShowWizard walks through the steps on the app of t
+func (t *TableView) ShowWizard(title string, steps []WizardStep, done func(values FormValues) error) {
+	t.app.ShowWizard(title, steps, done)
+}